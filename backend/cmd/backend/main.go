@@ -9,6 +9,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // availability windows use IANA time zones; images may lack /usr/share/zoneinfo
 
 	"github.com/easyhire/backend/internal/handlers"
 	"github.com/easyhire/backend/internal/middleware"
@@ -105,10 +106,42 @@ func main() {
 	assessmentRepo := repository.NewAssessmentRepository(db.DB)
 	questionRepo := repository.NewQuestionRepository(db.DB)
 
-	// ✅ FIX: pass db.DB as 3rd argument (NewAssessmentService expects *gorm.DB)
-	assessmentService := services.NewAssessmentService(assessmentRepo, questionRepo, db.DB)
+	reviewRepo := repository.NewReviewRepository(db.DB)
+	scoringRepo := repository.NewScoringRepository(db.DB)
+	resultRepo := repository.NewResultRepository(db.DB)
+	calibrationRepo := repository.NewCalibrationRepository(db.DB)
+
+	scoringService := services.NewScoringService(scoringRepo)
+	resultService := services.NewResultService(resultRepo, assessmentRepo)
+	calibrationService := services.NewCalibrationService(calibrationRepo)
+
+	executorClient := services.NewExecutorClient()
+
+	// ✅ FIX: pass db.DB as last argument (NewAssessmentService expects *gorm.DB)
+	assessmentService := services.NewAssessmentService(assessmentRepo, questionRepo, reviewRepo, scoringService, resultService, executorClient, db.DB)
+	reviewService := services.NewReviewService(reviewRepo, assessmentRepo, questionRepo, assessmentService)
+	questionService := services.NewQuestionService(questionRepo, scoringService, executorClient)
+	templateService := services.NewAssessmentTemplateService(assessmentRepo, assessmentService)
+	invitationService := services.NewInvitationService(assessmentRepo, repository.NewUserRepository(db.DB), assessmentService, passwordService)
+
+	aiProvider, err := services.NewAIProvider(cfg.AI)
+	if err != nil {
+		log.Warn().Err(err).Msg("AI provider is not configured, question generation, AI grading and AI feedback are disabled")
+	}
+	generationService := services.NewQuestionGenerationService(questionRepo, aiProvider)
+	aiGradingService := services.NewAIGradingService(reviewRepo, assessmentRepo, questionRepo, aiProvider)
+	feedbackService := services.NewFeedbackService(assessmentRepo, questionRepo, aiProvider)
 
 	assessmentHandler := handlers.NewAssessmentHandler(assessmentService)
+	templateHandler := handlers.NewAssessmentTemplateHandler(templateService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService, jwtService)
+	reviewHandler := handlers.NewReviewHandler(reviewService, aiGradingService)
+	questionHandler := handlers.NewQuestionHandler(questionService)
+	generationHandler := handlers.NewGenerationHandler(generationService)
+	feedbackHandler := handlers.NewFeedbackHandler(feedbackService)
+	scoringHandler := handlers.NewScoringHandler(scoringService)
+	resultHandler := handlers.NewResultHandler(resultService)
+	calibrationHandler := handlers.NewCalibrationHandler(calibrationService)
 
 	// ===== Init other handlers =====
 	healthHandler := handlers.NewHealthHandler(db, redisClient)
@@ -157,6 +190,33 @@ func main() {
 
 		// Task #9 routes (Assessment Engine)
 		routes.SetupAssessmentRoutes(apiV1, jwtService, assessmentHandler)
+
+		// Invitation acceptance: open the emailed link, accept, start the bound session
+		routes.SetupInvitationRoutes(apiV1, jwtService, invitationHandler)
+
+		// Assessment templates (presets seeded from TEST_STRUCTURE)
+		routes.SetupAssessmentTemplateRoutes(apiV1, jwtService, templateHandler)
+
+		// Manual review of answers that can't be auto-graded
+		routes.SetupReviewRoutes(apiV1, jwtService, reviewHandler)
+
+		// Question bank
+		routes.SetupQuestionRoutes(apiV1, jwtService, questionHandler)
+
+		// AI question generation
+		routes.SetupGenerationRoutes(apiV1, jwtService, generationHandler)
+
+		// Strengths, gaps and study recommendations on results
+		routes.SetupFeedbackRoutes(apiV1, jwtService, feedbackHandler)
+
+		// Scoring formula (admin)
+		routes.SetupScoringRoutes(apiV1, jwtService, scoringHandler)
+
+		// Score normalization (percentile, z-score, rank)
+		routes.SetupResultRoutes(apiV1, jwtService, resultHandler)
+
+		// IRT calibration of the question bank (admin)
+		routes.SetupCalibrationRoutes(apiV1, jwtService, calibrationHandler)
 	}
 
 	// Start server
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/pkg/config"
+	"github.com/easyhire/backend/internal/repository"
+	"github.com/easyhire/backend/internal/services"
+	"github.com/easyhire/backend/pkg/database"
+)
+
+// Пакетная калибровка банка вопросов (2PL IRT), например по cron:
+//
+//	go run ./cmd/calibrate -min-responses 30
+func main() {
+	configPath := flag.String("config", "config/.env", "path to configuration")
+	minResponses := flag.Int("min-responses", 0, "minimum responses per question (0 = default)")
+	maxIterations := flag.Int("max-iterations", 0, "maximum fitting iterations (0 = default)")
+	timeout := flag.Duration("timeout", 30*time.Minute, "job timeout")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := database.NewDatabase(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	calibrationService := services.NewCalibrationService(repository.NewCalibrationRepository(db.DB))
+	run, err := calibrationService.Calibrate(ctx, models.CalibrationRequest{
+		MinResponses:  *minResponses,
+		MaxIterations: *maxIterations,
+	}, "")
+	if err != nil {
+		log.Fatalf("❌ Calibration failed: %v", err)
+	}
+
+	log.Printf("✅ Calibration %s: %d questions calibrated, %d skipped (< %d responses), %d sessions, %d iterations, converged=%t, logL=%.2f",
+		run.ID, run.Calibrated, run.Skipped, run.MinResponses, run.Sessions, run.Iterations, run.Converged, run.LogLikelihood)
+}
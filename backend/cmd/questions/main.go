@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/pkg/config"
+	"github.com/easyhire/backend/internal/repository"
+	"github.com/easyhire/backend/internal/services"
+	"github.com/easyhire/backend/pkg/database"
+)
+
+// Импорт и экспорт банка вопросов:
+//
+//	go run ./cmd/questions import -file bank.yaml -author <user-id> [-dry-run]
+//	go run ./cmd/questions import -file moodle.gift -author <user-id> -competency concurrency -level middle
+//	go run ./cmd/questions export -format qti -out questions-qti.zip -competency concurrency
+//	go run ./cmd/questions reindex [-report -threshold 0.8]
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "import":
+		runImport(os.Args[2:])
+	case "export":
+		runExport(os.Args[2:])
+	case "reindex":
+		runReindex(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: questions import|export|reindex [flags] (-h for flags)")
+	os.Exit(2)
+}
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	configPath := fs.String("config", "config/.env", "path to configuration")
+	file := fs.String("file", "", "file to import (- for stdin)")
+	format := fs.String("format", "", "yaml, json, gift or qti (default: by file extension)")
+	author := fs.String("author", "", "user id recorded as the author of created versions")
+	competency := fs.String("competency", "", "competency for questions without one (GIFT, QTI)")
+	level := fs.String("level", "", "level for questions without one (GIFT, QTI)")
+	dryRun := fs.Bool("dry-run", false, "validate and report without writing")
+	timeout := fs.Duration("timeout", 10*time.Minute, "job timeout")
+	fs.Parse(args)
+
+	if *file == "" || *author == "" {
+		log.Fatal("-file and -author are required")
+	}
+	if *format == "" {
+		*format = services.QuestionFormatFromFileName(*file)
+	}
+
+	var data []byte
+	var err error
+	if *file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *file, err)
+	}
+
+	questionService, closeDB := newQuestionService(*configPath)
+	defer closeDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	report, err := questionService.ImportQuestions(ctx, data, models.QuestionImportOptions{
+		Format:     *format,
+		DryRun:     *dryRun,
+		Competency: *competency,
+		Level:      models.DifficultyLevel(*level),
+	}, *author)
+	if err != nil {
+		log.Fatalf("❌ Import failed: %v", err)
+	}
+
+	for _, item := range report.Items {
+		if item.Status == models.ImportStatusFailed {
+			log.Printf("  #%d %s (%s): %v", item.Index, item.ExternalID, item.Title, item.Errors)
+		}
+		for _, similar := range item.Similar {
+			log.Printf("  ⚠️ #%d %s is %.0f%% similar to %s (%s)", item.Index, item.ExternalID, similar.Similarity*100, similar.QuestionID, similar.Title)
+		}
+	}
+	mode := ""
+	if report.DryRun {
+		mode = " (dry run)"
+	}
+	log.Printf("✅ Import%s: %d questions, %d created, %d updated, %d unchanged, %d failed, %d with near-duplicates",
+		mode, report.Total, report.Created, report.Updated, report.Unchanged, report.Failed, report.Warnings)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	configPath := fs.String("config", "config/.env", "path to configuration")
+	format := fs.String("format", models.QuestionFormatYAML, "yaml, json, gift or qti")
+	out := fs.String("out", "", "output file (default: stdout)")
+	competency := fs.String("competency", "", "only this competency")
+	level := fs.String("level", "", "only this level")
+	questionType := fs.String("type", "", "only this question type")
+	status := fs.String("validation-status", "", "only this validation status")
+	timeout := fs.Duration("timeout", 10*time.Minute, "job timeout")
+	fs.Parse(args)
+
+	questionService, closeDB := newQuestionService(*configPath)
+	defer closeDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	active := true
+	export, err := questionService.ExportQuestions(ctx, *format, repository.QuestionFilter{
+		CompetencyID: *competency,
+		Level:        *level,
+		Type:         *questionType,
+		Status:       *status,
+		IsActive:     &active,
+	})
+	if err != nil {
+		log.Fatalf("❌ Export failed: %v", err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(export.Data)
+	} else if err := os.WriteFile(*out, export.Data, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+	log.Printf("✅ Export: %d questions, %d skipped (not supported by %s)", export.Exported, export.Skipped, export.Format)
+}
+
+func runReindex(args []string) {
+	fs := flag.NewFlagSet("reindex", flag.ExitOnError)
+	configPath := fs.String("config", "config/.env", "path to configuration")
+	report := fs.Bool("report", false, "print near-duplicate clusters after indexing")
+	threshold := fs.Float64("threshold", 0, "similarity threshold for the report (default 0.8)")
+	timeout := fs.Duration("timeout", 30*time.Minute, "job timeout")
+	fs.Parse(args)
+
+	questionService, closeDB := newQuestionService(*configPath)
+	defer closeDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	indexed, err := questionService.ReindexSimilarity(ctx)
+	if err != nil {
+		log.Fatalf("❌ Reindex failed: %v", err)
+	}
+	log.Printf("✅ Similarity index: %d questions indexed", indexed)
+	if !*report {
+		return
+	}
+
+	duplicates, err := questionService.DuplicateReport(ctx, *threshold)
+	if err != nil {
+		log.Fatalf("❌ Duplicate report failed: %v", err)
+	}
+	for i, cluster := range duplicates.Clusters {
+		log.Printf("  cluster %d (max %.0f%%):", i+1, cluster.MaxSimilarity*100)
+		for _, q := range cluster.Questions {
+			log.Printf("    %s %s", q.QuestionID, q.Title)
+		}
+	}
+	log.Printf("✅ Duplicates: %d clusters among %d questions (threshold %.2f)", len(duplicates.Clusters), duplicates.Indexed, duplicates.Threshold)
+}
+
+func newQuestionService(configPath string) (services.QuestionService, func()) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := database.NewDatabase(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	questionRepo := repository.NewQuestionRepository(db.DB)
+	scoringService := services.NewScoringService(repository.NewScoringRepository(db.DB))
+	return services.NewQuestionService(questionRepo, scoringService, services.NewExecutorClient()), func() { db.Close() }
+}
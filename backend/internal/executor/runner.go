@@ -21,6 +21,8 @@ const (
 	defaultCPUs           = 1.0
 
 	maxOutputBytes = 256 * 1024 // 256KB total (stdout+stderr)
+
+	stdinFile = ".stdin"
 )
 
 type Runner struct {
@@ -84,6 +86,14 @@ func (r *Runner) Execute(ctx context.Context, req ExecuteRequest) ExecuteRespons
 		return fail("invalid request", err, start)
 	}
 
+	// stdin for "run" mode goes through a file in the workdir
+	if req.Mode == "run" && req.Stdin != "" {
+		if err := writeFiles(workdir, map[string]string{stdinFile: req.Stdin}); err != nil {
+			return fail("write stdin failed", err, start)
+		}
+		cmdLine = strings.TrimRight(cmdLine, "\n") + " < " + stdinFile + "\n"
+	}
+
 	wd := filepath.Base(workdir) // folder name inside the shared volume
 
 	args := []string{
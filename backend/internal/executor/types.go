@@ -9,6 +9,7 @@ type ExecuteRequest struct {
 	TimeoutSeconds int               `json:"timeout_seconds" binding:"min=1,max=120"`
 	CPUs           float64           `json:"cpus" binding:"omitempty,min=0.1,max=4"`
 	MemoryMB       int               `json:"memory_mb" binding:"omitempty,min=64,max=2048"`
+	Stdin          string            `json:"stdin"` // only for mode "run"
 }
 
 type ExecuteResponse struct {
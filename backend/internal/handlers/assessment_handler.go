@@ -1,13 +1,19 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/easyhire/backend/internal/models"
 	"github.com/easyhire/backend/internal/repository"
 	"github.com/easyhire/backend/internal/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type AssessmentHandler struct {
@@ -34,7 +40,7 @@ func (h *AssessmentHandler) CreateAssessment(c *gin.Context) {
 
 	assessment, err := h.assessmentService.CreateAssessment(c.Request.Context(), req, userID.(string))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondAssessmentError(c, err)
 		return
 	}
 
@@ -64,7 +70,7 @@ func (h *AssessmentHandler) UpdateAssessment(c *gin.Context) {
 
 	assessment, err := h.assessmentService.UpdateAssessment(c.Request.Context(), id, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondAssessmentError(c, err)
 		return
 	}
 
@@ -82,40 +88,211 @@ func (h *AssessmentHandler) DeleteAssessment(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// ListAssessments список оценок с фильтрами status, tag (через запятую — любой из), target_level,
+// created_by (id или "me"), from/to (RFC3339 или YYYY-MM-DD, to включительно), search, сортировкой
+// sort (поле или -поле) и страницами по cursor (next_cursor прошлого ответа) либо page
 func (h *AssessmentHandler) ListAssessments(c *gin.Context) {
-	filter := repository.AssessmentFilter{Limit: 20, Offset: 0}
+	filter := repository.AssessmentFilter{
+		Limit:       20,
+		Status:      c.Query("status"),
+		TargetLevel: c.Query("target_level"),
+		CreatedBy:   c.Query("created_by"),
+		Search:      c.Query("search"),
+		Sort:        c.Query("sort"),
+		Cursor:      c.Query("cursor"),
+	}
 
 	if limit := c.Query("limit"); limit != "" {
 		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
-			filter.Limit = l
+			filter.Limit = min(l, maxAssessmentPage)
 		}
 	}
-	if page := c.Query("page"); page != "" {
+	if page := c.Query("page"); page != "" && filter.Cursor == "" {
 		if p, err := strconv.Atoi(page); err == nil && p > 0 {
 			filter.Offset = (p - 1) * filter.Limit
 		}
 	}
+	if filter.Status != "" && !validAssessmentStatuses[models.AssessmentStatus(filter.Status)] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status: " + filter.Status})
+		return
+	}
+	if filter.TargetLevel != "" && !validTargetLevels[filter.TargetLevel] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target_level: " + filter.TargetLevel})
+		return
+	}
+	if filter.CreatedBy == "me" {
+		userID, ok := currentUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+		filter.CreatedBy = userID
+	}
+	for _, tag := range strings.Split(c.Query("tag"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := parseDateParam(from, false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from: " + err.Error()})
+			return
+		}
+		filter.CreatedFrom = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := parseDateParam(to, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to: " + err.Error()})
+			return
+		}
+		filter.CreatedTo = &t
+	}
 
-	assessments, err := h.assessmentService.ListAssessments(c.Request.Context(), filter)
+	list, err := h.assessmentService.ListAssessments(c.Request.Context(), filter)
 	if err != nil {
+		if errors.Is(err, repository.ErrInvalidAssessmentSort) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":       err.Error(),
+				"sort_fields": repository.AssessmentSortFields,
+			})
+			return
+		}
+		if errors.Is(err, repository.ErrInvalidAssessmentCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"assessments": assessments,
-		"total":       len(assessments),
-		"limit":       filter.Limit,
+		"assessments": list.Assessments,
+		"total":       list.Total,
+		"limit":       list.Limit,
 		"offset":      filter.Offset,
+		"next_cursor": list.NextCursor,
 	})
 }
 
+const maxAssessmentPage = 100
+
+var validAssessmentStatuses = map[models.AssessmentStatus]bool{
+	models.AssessmentStatusDraft:    true,
+	models.AssessmentStatusActive:   true,
+	models.AssessmentStatusArchived: true,
+}
+
+var validTargetLevels = map[string]bool{"junior": true, "middle": true, "senior": true, "expert": true}
+
+// parseDateParam RFC3339 или YYYY-MM-DD; для верхней границы дата без времени — начало следующего дня
+func parseDateParam(value string, upper bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// CloneAssessment копирует оценку с компетенциями, тегами и набором вопросов в новый draft
+func (h *AssessmentHandler) CloneAssessment(c *gin.Context) {
+	var req models.CloneAssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	assessment, err := h.assessmentService.CloneAssessment(c.Request.Context(), c.Param("id"), req, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, assessment)
+}
+
+// PublishCheck готова ли оценка к публикации (покрытие компетенций одобренными вопросами)
+func (h *AssessmentHandler) PublishCheck(c *gin.Context) {
+	check, err := h.assessmentService.PublishCheck(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondAssessmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, check)
+}
+
+// PublishAssessment draft → active
+func (h *AssessmentHandler) PublishAssessment(c *gin.Context) {
+	assessment, err := h.assessmentService.PublishAssessment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondAssessmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, assessment)
+}
+
+// ArchiveAssessment → archived
+func (h *AssessmentHandler) ArchiveAssessment(c *gin.Context) {
+	assessment, err := h.assessmentService.ArchiveAssessment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondAssessmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, assessment)
+}
+
+// respondAssessmentError ошибки жизненного цикла оценки; неудачная публикация возвращает отчёт проверки
+func respondAssessmentError(c *gin.Context, err error) {
+	var publishErr *services.PublishValidationError
+	var availabilityErr *services.AvailabilityError
+	switch {
+	case errors.As(err, &publishErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "check": publishErr.Check})
+	case errors.As(err, &availabilityErr):
+		status := http.StatusForbidden
+		if errors.Is(err, services.ErrAttemptCooldown) {
+			status = http.StatusTooManyRequests
+		}
+		// ещё не открыта или идёт пауза между попытками — можно повторить позже
+		if next := availabilityErr.Status.NextAttemptAt; next != nil {
+			c.Header("Retry-After", strconv.Itoa(max(int(time.Until(*next).Seconds())+1, 1)))
+		}
+		c.JSON(status, gin.H{"error": err.Error(), "availability": availabilityErr.Status})
+	case errors.Is(err, services.ErrInvalidAvailability):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, services.ErrQuestionNotInAssessment):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidAssessmentTransition),
+		errors.Is(err, services.ErrSessionNotInProgress),
+		errors.Is(err, services.ErrAssessmentLocked),
+		errors.Is(err, services.ErrAssessmentNotPublished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 func (h *AssessmentHandler) InviteCandidate(c *gin.Context) {
 	assessmentID := c.Param("id")
 
-	var req struct {
-		Email string `json:"email" binding:"required,email"`
-	}
+	var req models.InviteCandidateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -132,9 +309,10 @@ func (h *AssessmentHandler) InviteCandidate(c *gin.Context) {
 		assessmentID,
 		req.Email,
 		userID.(string), // ✅ invited_by
+		req.Availability,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondAssessmentError(c, err)
 		return
 	}
 
@@ -145,7 +323,8 @@ func (h *AssessmentHandler) BulkInvite(c *gin.Context) {
 	assessmentID := c.Param("id")
 
 	var req struct {
-		Emails []string `json:"emails" binding:"required,min=1"`
+		Emails       []string                   `json:"emails" binding:"required,min=1"`
+		Availability *models.AvailabilityWindow `json:"availability"` // the same window for every invitation
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -167,6 +346,7 @@ func (h *AssessmentHandler) BulkInvite(c *gin.Context) {
 			assessmentID,
 			email,
 			userID.(string),
+			req.Availability,
 		)
 		if err != nil {
 			failedEmails = append(failedEmails, email)
@@ -189,29 +369,34 @@ func (h *AssessmentHandler) BulkInvite(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
-func (h *AssessmentHandler) GetInvitation(c *gin.Context) {
-	token := c.Param("token")
+// CheckAvailability может ли текущий пользователь начать попытку: окно, лимит попыток, пауза
+func (h *AssessmentHandler) CheckAvailability(c *gin.Context) {
+	candidateID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
 
-	invitation, err := h.assessmentService.GetInvitation(c.Request.Context(), token)
+	status, err := h.assessmentService.CheckAvailability(c.Request.Context(), c.Param("id"), candidateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "invitation not found"})
+		respondAssessmentError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, invitation)
+	c.JSON(http.StatusOK, status)
 }
 
 func (h *AssessmentHandler) StartSession(c *gin.Context) {
 	assessmentID := c.Param("id")
 
-	candidateID, exists := c.Get("user_id")
-	if !exists {
+	candidateID, ok := currentUserID(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	session, err := h.assessmentService.StartSession(c.Request.Context(), assessmentID, candidateID.(string))
+	session, err := h.assessmentService.StartSession(c.Request.Context(), assessmentID, candidateID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondAssessmentError(c, err)
 		return
 	}
 
@@ -232,6 +417,9 @@ func (h *AssessmentHandler) GetSession(c *gin.Context) {
 
 func (h *AssessmentHandler) SubmitAnswer(c *gin.Context) {
 	sessionID := c.Param("session_id")
+	if !h.authorizeSession(c, sessionID) {
+		return
+	}
 
 	var req struct {
 		QuestionID string                        `json:"question_id" binding:"required"`
@@ -252,7 +440,7 @@ func (h *AssessmentHandler) SubmitAnswer(c *gin.Context) {
 	}
 
 	if err := h.assessmentService.SubmitAnswer(c.Request.Context(), sessionID, req.QuestionID, payload); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondAssessmentError(c, err)
 		return
 	}
 
@@ -261,12 +449,130 @@ func (h *AssessmentHandler) SubmitAnswer(c *gin.Context) {
 
 func (h *AssessmentHandler) CompleteSession(c *gin.Context) {
 	sessionID := c.Param("session_id")
+	if !h.authorizeSession(c, sessionID) {
+		return
+	}
 
 	result, err := h.assessmentService.CompleteSession(c.Request.Context(), sessionID)
+	if errors.Is(err, services.ErrSessionPendingReview) {
+		c.JSON(http.StatusAccepted, gin.H{
+			"session_id": sessionID,
+			"status":     models.SessionStatusPendingReview,
+			"message":    "some answers require manual review, result will be available later",
+		})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondAssessmentError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
+
+// maxEditRequestSize предел тела запроса с пачкой дельт (JSON с экранированием или base64)
+const maxEditRequestSize = 4 << 20
+
+func (h *AssessmentHandler) RecordEdits(c *gin.Context) {
+	sessionID := c.Param("session_id")
+	questionID := c.Param("question_id")
+
+	if !h.authorizeSession(c, sessionID) {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxEditRequestSize)
+	var req models.EditHistoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.assessmentService.RecordEdits(c.Request.Context(), sessionID, questionID, req); err != nil {
+		if errors.Is(err, services.ErrInvalidEditBatch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondAssessmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "edits recorded", "seq": req.Seq})
+}
+
+func (h *AssessmentHandler) ReplayAnswer(c *gin.Context) {
+	sessionID := c.Param("session_id")
+	questionID := c.Param("question_id")
+
+	at := int64(-1)
+	if v := c.Query("at"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "at must be a non-negative number of milliseconds"})
+			return
+		}
+		at = parsed
+	}
+
+	replay, err := h.assessmentService.ReplayAnswer(c.Request.Context(), sessionID, questionID, at)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, replay)
+}
+
+func (h *AssessmentHandler) GetAnswerDetails(c *gin.Context) {
+	sessionID := c.Param("session_id")
+
+	details, err := h.assessmentService.GetAnswerDetails(c.Request.Context(), sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"session_id": sessionID, "answers": details})
+}
+
+// GetResultBreakdown разбивка результата по компетенциям
+func (h *AssessmentHandler) GetResultBreakdown(c *gin.Context) {
+	breakdown, err := h.assessmentService.GetResultBreakdown(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// ServeQuestion выдаёт вопрос сессии; с этого момента сервер считает время ответа
+func (h *AssessmentHandler) ServeQuestion(c *gin.Context) {
+	if !h.authorizeSession(c, c.Param("session_id")) {
+		return
+	}
+	question, err := h.assessmentService.ServeQuestion(c.Request.Context(), c.Param("session_id"), c.Param("question_id"))
+	if err != nil {
+		respondAssessmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, question)
+}
+
+// authorizeSession сессия существует и принадлежит текущему пользователю; иначе ответ уже отправлен
+func (h *AssessmentHandler) authorizeSession(c *gin.Context, sessionID string) bool {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return false
+	}
+	session, err := h.assessmentService.GetSession(c.Request.Context(), sessionID)
+	if err != nil {
+		respondAssessmentError(c, fmt.Errorf("session not found: %w", err))
+		return false
+	}
+	if session.CandidateID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "session belongs to another candidate"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AssessmentTemplateHandler struct {
+	templateService services.AssessmentTemplateService
+}
+
+func NewAssessmentTemplateHandler(templateService services.AssessmentTemplateService) *AssessmentTemplateHandler {
+	return &AssessmentTemplateHandler{templateService: templateService}
+}
+
+func (h *AssessmentTemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListTemplates(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": templates, "total": len(templates)})
+}
+
+func (h *AssessmentTemplateHandler) GetTemplate(c *gin.Context) {
+	template, err := h.templateService.GetTemplate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *AssessmentTemplateHandler) CreateTemplate(c *gin.Context) {
+	var req models.AssessmentTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(c.Request.Context(), req, userID)
+	if err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *AssessmentTemplateHandler) UpdateTemplate(c *gin.Context) {
+	var req models.AssessmentTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *AssessmentTemplateHandler) DeleteTemplate(c *gin.Context) {
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "template deleted"})
+}
+
+// InstantiateTemplate создаёт оценку (draft) из шаблона; поля запроса переопределяют шаблон
+func (h *AssessmentTemplateHandler) InstantiateTemplate(c *gin.Context) {
+	var req models.InstantiateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	assessment, err := h.templateService.InstantiateTemplate(c.Request.Context(), c.Param("id"), req, userID)
+	if err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, assessment)
+}
+
+func templateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrBuiltinTemplate):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrTemplateKeyExists):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type CalibrationHandler struct {
+	calibrationService services.CalibrationService
+}
+
+func NewCalibrationHandler(calibrationService services.CalibrationService) *CalibrationHandler {
+	return &CalibrationHandler{calibrationService: calibrationService}
+}
+
+// Calibrate запускает калибровку 2PL IRT по всем завершённым сессиям
+func (h *CalibrationHandler) Calibrate(c *gin.Context) {
+	var req models.CalibrationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userID, _ := currentUserID(c)
+	run, err := h.calibrationService.Calibrate(c.Request.Context(), req, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+func (h *CalibrationHandler) ListRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	runs, err := h.calibrationService.ListRuns(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserID достаёт id пользователя, который AuthMiddleware кладёт в контекст (uuid.UUID или string)
+func currentUserID(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		return "", false
+	}
+	switch id := v.(type) {
+	case string:
+		return id, id != ""
+	case fmt.Stringer:
+		return id.String(), true
+	}
+	return "", false
+}
+
+// currentUserRole роль пользователя из контекста в виде строки
+func currentUserRole(c *gin.Context) string {
+	v, exists := c.Get("user_role")
+	if !exists {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/easyhire/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type FeedbackHandler struct {
+	feedbackService services.FeedbackService
+}
+
+func NewFeedbackHandler(feedbackService services.FeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{feedbackService: feedbackService}
+}
+
+// GetResultFeedback полный отзыв по результату (со ссылками на вопросы)
+func (h *FeedbackHandler) GetResultFeedback(c *gin.Context) {
+	feedback, err := h.feedbackService.GetResultFeedback(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, feedback)
+}
+
+// RegenerateFeedback пересчитывает отзыв; ?ai=true — с рекомендациями модели
+func (h *FeedbackHandler) RegenerateFeedback(c *gin.Context) {
+	feedback, err := h.feedbackService.RegenerateFeedback(c.Request.Context(), c.Param("id"), c.Query("ai") == "true")
+	if err != nil {
+		if errors.Is(err, services.ErrAIUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, feedback)
+}
+
+// GetCandidateFeedback отзыв кандидату по его сессии (если в оценке включён show_explanation)
+func (h *FeedbackHandler) GetCandidateFeedback(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	feedback, err := h.feedbackService.GetCandidateFeedback(c.Request.Context(), c.Param("session_id"), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrFeedbackHidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrSessionPendingReview):
+			c.JSON(http.StatusAccepted, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, feedback)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type GenerationHandler struct {
+	generationService services.QuestionGenerationService
+}
+
+func NewGenerationHandler(generationService services.QuestionGenerationService) *GenerationHandler {
+	return &GenerationHandler{generationService: generationService}
+}
+
+// GenerateQuestions генерирует вопросы моделью; они сохраняются как ai_generated и ждут проверки
+func (h *GenerationHandler) GenerateQuestions(c *gin.Context) {
+	var req models.GenerateQuestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	result, err := h.generationService.Generate(c.Request.Context(), req, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrAIUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/services"
+	coremodels "github.com/easyhire/internal/models"
+	"github.com/easyhire/internal/pkg/auth"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type InvitationHandler struct {
+	invitationService services.InvitationService
+	jwtService        *auth.JWTService
+}
+
+func NewInvitationHandler(invitationService services.InvitationService, jwtService *auth.JWTService) *InvitationHandler {
+	return &InvitationHandler{invitationService: invitationService, jwtService: jwtService}
+}
+
+// GetInvitation открывает приглашение по токену из письма (отмечает opened)
+func (h *InvitationHandler) GetInvitation(c *gin.Context) {
+	view, err := h.invitationService.OpenInvitation(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// AcceptInvitation принимает приглашение и начинает сессию по нему. Кандидат без токена
+// входит паролем или регистрируется — в ответе тогда токены нового входа.
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req models.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, authenticated := currentUserID(c)
+
+	acceptance, user, err := h.invitationService.AcceptInvitation(c.Request.Context(), c.Param("token"), req, userID)
+	if err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	resp := gin.H{
+		"invitation":      acceptance.Invitation,
+		"session":         acceptance.Session,
+		"availability":    acceptance.Availability,
+		"account_created": acceptance.AccountCreated,
+	}
+	if !authenticated {
+		tokens, err := h.jwtService.GenerateTokenPair(user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate authentication tokens"})
+			return
+		}
+		resp["auth"] = coremodels.LoginResponse{
+			AccessToken:  tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			TokenType:    "Bearer",
+			ExpiresAt:    tokens.ExpiresAt,
+			User: coremodels.UserInfo{
+				ID:        user.ID,
+				Email:     user.Email,
+				Name:      user.Name,
+				Role:      user.Role,
+				Company:   user.Company,
+				AvatarURL: user.AvatarURL,
+				IsActive:  user.IsActive,
+			},
+		}
+	}
+
+	status := http.StatusOK
+	if acceptance.AccountCreated {
+		status = http.StatusCreated
+	}
+	c.JSON(status, resp)
+}
+
+func respondInvitationError(c *gin.Context, err error) {
+	var availabilityErr *services.AvailabilityError
+	if errors.As(err, &availabilityErr) || errors.Is(err, services.ErrAssessmentNotPublished) {
+		respondAssessmentError(c, err)
+		return
+	}
+	c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
+}
+
+func invitationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvitationExpired):
+		return http.StatusGone
+	case errors.Is(err, services.ErrInvitationCompleted),
+		errors.Is(err, services.ErrInvitationTaken):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvitationEmailMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrInvalidCredentials):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrInvalidAccountDetails):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
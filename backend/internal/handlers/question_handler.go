@@ -0,0 +1,500 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+	"github.com/easyhire/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type QuestionHandler struct {
+	questionService services.QuestionService
+}
+
+func NewQuestionHandler(questionService services.QuestionService) *QuestionHandler {
+	return &QuestionHandler{questionService: questionService}
+}
+
+func (h *QuestionHandler) CreateQuestion(c *gin.Context) {
+	var req models.QuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	question, err := h.questionService.CreateQuestion(c.Request.Context(), req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, question)
+}
+
+// ListQuestions последние версии вопросов; ?all_versions=true — все версии.
+// ?q= — полнотекстовый поиск по заголовку, условию и разбору (выдача по рангу, с подсветкой);
+// ?tags=a,b&tag_match=any|all — фильтр по тегам.
+func (h *QuestionHandler) ListQuestions(c *gin.Context) {
+	filter := repository.QuestionFilter{
+		CompetencyID: c.Query("competency"),
+		Level:        c.Query("level"),
+		Type:         c.Query("type"),
+		Search:       c.Query("search"),
+		Query:        strings.TrimSpace(c.Query("q")),
+		TagMatch:     c.DefaultQuery("tag_match", repository.TagMatchAny),
+		Status:       c.Query("validation_status"),
+		LatestOnly:   c.Query("all_versions") != "true",
+		Limit:        20,
+	}
+	if tags := c.Query("tags"); tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+	if filter.TagMatch != repository.TagMatchAny && filter.TagMatch != repository.TagMatchAll {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tag_match must be any or all"})
+		return
+	}
+	if active := c.Query("is_active"); active != "" {
+		v := active == "true"
+		filter.IsActive = &v
+	}
+	if generated := c.Query("ai_generated"); generated != "" {
+		v := generated == "true"
+		filter.AIGenerated = &v
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			filter.Limit = l
+		}
+	}
+	if page := c.Query("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil && p > 0 {
+			filter.Offset = (p - 1) * filter.Limit
+		}
+	}
+
+	questions, total, err := h.questionService.ListQuestions(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"questions": questions,
+		"total":     total,
+		"limit":     filter.Limit,
+		"offset":    filter.Offset,
+	})
+}
+
+func (h *QuestionHandler) GetQuestion(c *gin.Context) {
+	question, err := h.questionService.GetQuestion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, question)
+}
+
+// UpdateQuestion создаёт новую версию вопроса и возвращает её
+func (h *QuestionHandler) UpdateQuestion(c *gin.Context) {
+	var req models.QuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	question, err := h.questionService.UpdateQuestion(c.Request.Context(), c.Param("id"), req, userID)
+	if err != nil {
+		c.JSON(questionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, question)
+}
+
+// DeleteQuestion снимает вопрос с использования; версии остаются для истории оценок
+func (h *QuestionHandler) DeleteQuestion(c *gin.Context) {
+	if err := h.questionService.RetireQuestion(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "question retired"})
+}
+
+func (h *QuestionHandler) ListVersions(c *gin.Context) {
+	versions, err := h.questionService.ListVersions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// SubmitForReview отправка вопроса на проверку (с прогоном эталонного решения)
+func (h *QuestionHandler) SubmitForReview(c *gin.Context) {
+	var req models.SubmitQuestionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userID, _ := currentUserID(c)
+	question, err := h.questionService.SubmitForReview(c.Request.Context(), c.Param("id"), userID, req)
+	if err != nil {
+		respondQuestionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, question)
+}
+
+// ValidateQuestion approve / reject / needs_review
+func (h *QuestionHandler) ValidateQuestion(c *gin.Context) {
+	var req models.ValidateQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	isAdmin := currentUserRole(c) == "admin"
+
+	question, err := h.questionService.ValidateQuestion(c.Request.Context(), c.Param("id"), userID, isAdmin, req)
+	if err != nil {
+		respondQuestionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, question)
+}
+
+// CheckSolution прогон эталонного решения на тест-кейсах без смены статуса
+func (h *QuestionHandler) CheckSolution(c *gin.Context) {
+	check, err := h.questionService.CheckSolution(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, check)
+}
+
+func (h *QuestionHandler) ListValidations(c *gin.Context) {
+	entries, err := h.questionService.ListValidations(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"validations": entries})
+}
+
+// maxQuestionImportSize предел размера импортируемого файла
+const maxQuestionImportSize = 20 << 20
+
+// ImportQuestions импорт банка вопросов: файл в multipart-поле "file" или тело запроса.
+// ?format=yaml|json|gift|qti (по умолчанию — по расширению файла), ?dry_run=true — только проверка,
+// ?competency= и ?level= — значения для вопросов без них (GIFT, QTI)
+func (h *QuestionHandler) ImportQuestions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxQuestionImportSize)
+	format := c.Query("format")
+	var data []byte
+	var err error
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, ferr := c.FormFile("file")
+		if ferr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		if format == "" {
+			format = services.QuestionFormatFromFileName(file.Filename)
+		}
+		f, ferr := file.Open()
+		if ferr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": ferr.Error()})
+			return
+		}
+		defer f.Close()
+		data, err = io.ReadAll(f)
+	} else {
+		data, err = io.ReadAll(c.Request.Body)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("read import file failed: %v", err)})
+		return
+	}
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "import file is empty"})
+		return
+	}
+
+	opts := models.QuestionImportOptions{
+		Format:     strings.ToLower(format),
+		DryRun:     c.Query("dry_run") == "true",
+		Competency: c.Query("competency"),
+		Level:      models.DifficultyLevel(c.Query("level")),
+	}
+	report, err := h.questionService.ImportQuestions(c.Request.Context(), data, opts, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportQuestions выгрузка последних версий вопросов файлом:
+// ?format=yaml|json|gift|qti&competency=&level=&type=&validation_status=
+func (h *QuestionHandler) ExportQuestions(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", models.QuestionFormatYAML))
+	filter := repository.QuestionFilter{
+		CompetencyID: c.Query("competency"),
+		Level:        c.Query("level"),
+		Type:         c.Query("type"),
+		Status:       c.Query("validation_status"),
+	}
+	if active := c.DefaultQuery("is_active", "true"); active != "all" {
+		v := active == "true"
+		filter.IsActive = &v
+	}
+
+	export, err := h.questionService.ExportQuestions(c.Request.Context(), format, filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUnsupportedQuestionFormat) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName))
+	c.Header("X-Exported-Count", strconv.Itoa(export.Exported))
+	c.Header("X-Skipped-Count", strconv.Itoa(export.Skipped))
+	c.Data(http.StatusOK, export.ContentType, export.Data)
+}
+
+func questionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrQuestionNotLatest), errors.Is(err, services.ErrInvalidValidationTransition):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrSelfValidation):
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
+
+func respondQuestionError(c *gin.Context, err error) {
+	var checkErr *services.SolutionCheckError
+	if errors.As(err, &checkErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "solution_check": checkErr.Check})
+		return
+	}
+	c.JSON(questionErrorStatus(err), gin.H{"error": err.Error()})
+}
+
+func (h *QuestionHandler) GetRubric(c *gin.Context) {
+	criteria, err := h.questionService.GetRubric(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"question_id": c.Param("id"), "criteria": criteria})
+}
+
+func (h *QuestionHandler) SetRubric(c *gin.Context) {
+	var req models.RubricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := currentUserID(c)
+	question, err := h.questionService.SetRubric(c.Request.Context(), c.Param("id"), req, userID)
+	if err != nil {
+		c.JSON(questionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	// рубрика сохраняется в новой версии вопроса
+	c.JSON(http.StatusOK, gin.H{"question_id": question.ID, "version": question.Version, "criteria": question.Rubric})
+}
+
+// GetQuestionAnalytics статистика ответов на вопрос
+func (h *QuestionHandler) GetQuestionAnalytics(c *gin.Context) {
+	analytics, err := h.questionService.GetQuestionAnalytics(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, analytics)
+}
+
+// ListQuestionAnalytics статистика по банку: ?competency=&level=&type=&flagged=true
+func (h *QuestionHandler) ListQuestionAnalytics(c *gin.Context) {
+	filter := repository.QuestionFilter{
+		CompetencyID: c.Query("competency"),
+		Level:        c.Query("level"),
+		Type:         c.Query("type"),
+	}
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+	filter.Offset, _ = strconv.Atoi(c.Query("offset"))
+	flaggedOnly := c.Query("flagged") == "true"
+
+	analytics, err := h.questionService.ListQuestionAnalytics(c.Request.Context(), filter, flaggedOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"questions": analytics, "total": len(analytics)})
+}
+
+// ListTags справочник тегов с числом вопросов
+func (h *QuestionHandler) ListTags(c *gin.Context) {
+	tags, err := h.questionService.ListTags(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tags": tags, "total": len(tags)})
+}
+
+func (h *QuestionHandler) CreateTag(c *gin.Context) {
+	var req models.QuestionTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.questionService.CreateTag(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, tag)
+}
+
+func (h *QuestionHandler) UpdateTag(c *gin.Context) {
+	var req models.QuestionTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.questionService.UpdateTag(c.Request.Context(), c.Param("tag_id"), req)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
+
+func (h *QuestionHandler) DeleteTag(c *gin.Context) {
+	if err := h.questionService.DeleteTag(c.Request.Context(), c.Param("tag_id")); err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "tag deleted"})
+}
+
+// SetQuestionTags заменяет теги последней версии вопроса (без новой версии)
+func (h *QuestionHandler) SetQuestionTags(c *gin.Context) {
+	var req models.SetQuestionTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	question, err := h.questionService.SetQuestionTags(c.Request.Context(), c.Param("id"), req.Tags)
+	if err != nil {
+		c.JSON(questionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"question_id": question.ID, "tags": question.Tags})
+}
+
+func tagErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrQuestionTagExists):
+		return http.StatusConflict
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+// FindSimilarQuestions похожие вопросы банка (near-duplicates); ?threshold=0..1
+func (h *QuestionHandler) FindSimilarQuestions(c *gin.Context) {
+	threshold, err := similarityThresholdParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	similar, err := h.questionService.FindSimilarQuestions(c.Request.Context(), c.Param("id"), threshold)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"question_id": c.Param("id"), "similar_questions": similar, "total": len(similar)})
+}
+
+// DuplicateReport кластеры похожих вопросов по всему банку; ?threshold=0..1
+func (h *QuestionHandler) DuplicateReport(c *gin.Context) {
+	threshold, err := similarityThresholdParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := h.questionService.DuplicateReport(c.Request.Context(), threshold)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ReindexSimilarity строит сигнатуры вопросов, которых нет в индексе похожести
+func (h *QuestionHandler) ReindexSimilarity(c *gin.Context) {
+	indexed, err := h.questionService.ReindexSimilarity(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"indexed": indexed})
+}
+
+// similarityThresholdParam ?threshold= (0 — порог по умолчанию)
+func similarityThresholdParam(c *gin.Context) (float64, error) {
+	raw := c.Query("threshold")
+	if raw == "" {
+		return 0, nil
+	}
+	threshold, err := strconv.ParseFloat(raw, 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		return 0, errors.New("threshold must be a number in (0, 1]")
+	}
+	return threshold, nil
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/easyhire/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ResultHandler struct {
+	resultService services.ResultService
+}
+
+func NewResultHandler(resultService services.ResultService) *ResultHandler {
+	return &ResultHandler{resultService: resultService}
+}
+
+// GetResultStanding перцентиль, z-score и ранг результата — в целом и по компетенциям
+func (h *ResultHandler) GetResultStanding(c *gin.Context) {
+	standing, err := h.resultService.GetResultStanding(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, standing)
+}
+
+// GetDistributions распределение процентов по оценке и её компетенциям
+func (h *ResultHandler) GetDistributions(c *gin.Context) {
+	dists, err := h.resultService.GetDistributions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"distributions": dists})
+}
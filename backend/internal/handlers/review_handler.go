@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+	"github.com/easyhire/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	reviewService    services.ReviewService
+	aiGradingService services.AIGradingService
+}
+
+func NewReviewHandler(reviewService services.ReviewService, aiGradingService services.AIGradingService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService, aiGradingService: aiGradingService}
+}
+
+// ListReviews очередь проверок.
+// Эксперт по умолчанию видит свои проверки, с ?queue=true — неназначенные.
+func (h *ReviewHandler) ListReviews(c *gin.Context) {
+	filter := repository.ReviewFilter{
+		Status:    c.Query("status"),
+		SessionID: c.Query("session_id"),
+		Limit:     20,
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			filter.Limit = l
+		}
+	}
+	if page := c.Query("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil && p > 0 {
+			filter.Offset = (p - 1) * filter.Limit
+		}
+	}
+
+	if currentUserRole(c) == "technical_expert" {
+		if c.Query("queue") == "true" {
+			filter.Status = string(models.ReviewStatusPending)
+		} else {
+			userID, _ := currentUserID(c)
+			filter.ReviewerID = userID
+		}
+	} else {
+		filter.ReviewerID = c.Query("reviewer_id")
+	}
+
+	reviews, total, err := h.reviewService.ListReviews(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+		"total":   total,
+		"limit":   filter.Limit,
+		"offset":  filter.Offset,
+	})
+}
+
+func (h *ReviewHandler) GetReview(c *gin.Context) {
+	userID, _ := currentUserID(c)
+	role := currentUserRole(c)
+	privileged := role == "hr" || role == "admin"
+
+	detail, err := h.reviewService.GetReview(c.Request.Context(), c.Param("id"), userID, privileged)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, detail)
+}
+
+func (h *ReviewHandler) AssignReview(c *gin.Context) {
+	var req models.AssignReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewService.AssignReview(c.Request.Context(), c.Param("id"), req.ReviewerID)
+	if errors.Is(err, services.ErrReviewTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+func (h *ReviewHandler) ClaimReview(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	review, err := h.reviewService.ClaimReview(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+func (h *ReviewHandler) SubmitReview(c *gin.Context) {
+	var req models.SubmitReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	review, err := h.reviewService.SubmitReview(c.Request.Context(), c.Param("id"), userID, req)
+	if errors.Is(err, services.ErrReviewTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+// SuggestGrade оценка ответа моделью (?refresh=true — запросить заново)
+func (h *ReviewHandler) SuggestGrade(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	role := currentUserRole(c)
+	privileged := role == "hr" || role == "admin"
+
+	suggestion, err := h.aiGradingService.SuggestGrade(c.Request.Context(), c.Param("id"), userID, privileged, c.Query("refresh") == "true")
+	if err != nil {
+		aiGradingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// SuggestSessionGrades оценки моделью всех ответов сессии, ожидающих проверки
+func (h *ReviewHandler) SuggestSessionGrades(c *gin.Context) {
+	var req models.SuggestSessionGradesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := currentUserID(c)
+	suggestions, err := h.aiGradingService.SuggestSessionGrades(c.Request.Context(), req.SessionID, userID)
+	if err != nil {
+		aiGradingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions, "total": len(suggestions)})
+}
+
+// AcceptAISuggestion эксперт принимает оценку модели как свою
+func (h *ReviewHandler) AcceptAISuggestion(c *gin.Context) {
+	var req models.AcceptAISuggestionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	review, err := h.reviewService.AcceptAISuggestion(c.Request.Context(), c.Param("id"), userID, req)
+	if err != nil {
+		aiGradingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+func aiGradingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAIUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAIGradingDisabled),
+		errors.Is(err, services.ErrAISuggestionHidden),
+		errors.Is(err, services.ErrReviewTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// AgreementReport согласованность экспертов (kappa, разброс, смещение) с фильтрами
+// question_id, reviewer_id, from/to (RFC3339)
+func (h *ReviewHandler) AgreementReport(c *gin.Context) {
+	filter := repository.AgreementFilter{
+		QuestionID: c.Query("question_id"),
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from: " + err.Error()})
+			return
+		}
+		filter.From = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to: " + err.Error()})
+			return
+		}
+		filter.To = &t
+	}
+
+	report, err := h.reviewService.AgreementReport(c.Request.Context(), filter, c.Query("reviewer_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ScoringHandler struct {
+	scoringService services.ScoringService
+}
+
+func NewScoringHandler(scoringService services.ScoringService) *ScoringHandler {
+	return &ScoringHandler{scoringService: scoringService}
+}
+
+func (h *ScoringHandler) ListConfigs(c *gin.Context) {
+	configs, err := h.scoringService.ListConfigs(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"configs": configs})
+}
+
+func (h *ScoringHandler) GetActiveConfig(c *gin.Context) {
+	cfg, err := h.scoringService.ActiveConfig(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
+
+func (h *ScoringHandler) GetConfig(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+
+	cfg, err := h.scoringService.GetConfig(c.Request.Context(), version)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
+
+// CreateConfig новая версия формулы; с "activate": true она сразу начинает применяться
+func (h *ScoringHandler) CreateConfig(c *gin.Context) {
+	var req models.ScoringConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := currentUserID(c)
+	cfg, err := h.scoringService.CreateConfig(c.Request.Context(), req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, cfg)
+}
+
+func (h *ScoringHandler) ActivateConfig(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+
+	cfg, err := h.scoringService.ActivateConfig(c.Request.Context(), version)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
@@ -0,0 +1,53 @@
+package models
+
+// AIGradeStatus результат запроса оценки у модели
+type AIGradeStatus string
+
+const (
+	AIGradeStatusSuggested AIGradeStatus = "suggested" // оценка получена и ждёт решения эксперта
+	AIGradeStatusFailed    AIGradeStatus = "failed"    // модель не ответила или ответ не прошёл проверку
+)
+
+// AIDecision решение эксперта по предложенной оценке
+type AIDecision string
+
+const (
+	AIDecisionAccepted   AIDecision = "accepted"   // эксперт выставил оценку модели без изменений
+	AIDecisionOverridden AIDecision = "overridden" // эксперт выставил свою оценку
+)
+
+// AIGradeSuggestion оценка ответа, предложенная моделью.
+// Это только подсказка эксперту: в ответ кандидата попадает оценка из AnswerReview.
+// Provider, Model и PromptVersion сохраняются для аудита.
+type AIGradeSuggestion struct {
+	BaseModel
+	AnswerID      string        `gorm:"type:uuid;not null;index" json:"answer_id"`
+	SessionID     string        `gorm:"type:uuid;not null;index" json:"session_id"`
+	QuestionID    string        `gorm:"type:uuid;not null;index" json:"question_id"`
+	Status        AIGradeStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Provider      string        `gorm:"type:varchar(50);not null" json:"provider"`
+	Model         string        `gorm:"type:varchar(200);not null" json:"model"`
+	PromptVersion string        `gorm:"type:varchar(50);not null" json:"prompt_version"`
+	RequestedBy   *string       `gorm:"type:uuid" json:"requested_by"`
+
+	Score           *float64         `json:"score"`
+	MaxScore        float64          `gorm:"not null" json:"max_score"`
+	CriterionScores []CriterionScore `gorm:"type:jsonb;serializer:json" json:"criterion_scores"` // Comment — обоснование модели
+	Justification   string           `gorm:"type:text" json:"justification"`
+	Error           string           `gorm:"type:text" json:"error,omitempty"`
+
+	// Raw model output and usage, for audit
+	RawResponse      string `gorm:"type:text" json:"raw_response,omitempty"`
+	PromptTokens     int    `gorm:"default:0" json:"prompt_tokens"`
+	CompletionTokens int    `gorm:"default:0" json:"completion_tokens"`
+}
+
+// AcceptAISuggestionRequest эксперт принимает оценку модели как свою
+type AcceptAISuggestionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// SuggestSessionGradesRequest оценка моделью всех ответов сессии, ожидающих проверки
+type SuggestSessionGradesRequest struct {
+	SessionID string `json:"session_id" binding:"required"`
+}
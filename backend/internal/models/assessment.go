@@ -19,14 +19,52 @@ type Assessment struct {
 	CreatedBy        string           `gorm:"type:uuid;not null" json:"created_by"`
 	Status           AssessmentStatus `gorm:"type:varchar(20);default:'draft'" json:"status"`
 
+	// Lifecycle: draft → active (published) → archived; scoring settings are frozen after publishing
+	PublishedAt *time.Time `gorm:"type:timestamp" json:"published_at"`
+	ArchivedAt  *time.Time `gorm:"type:timestamp" json:"archived_at"`
+
+	// Availability: when candidates may start and how often they may retake
+	Availability    AvailabilityWindow `gorm:"embedded" json:"availability"`
+	MaxAttempts     int                `gorm:"not null;default:0" json:"max_attempts"`     // per candidate; 0 — unlimited
+	AttemptCooldown int                `gorm:"not null;default:0" json:"attempt_cooldown"` // seconds between attempts
+
+	// Manual review: how many experts grade each answer and when they must be adjudicated
+	ReviewersPerAnswer    int     `gorm:"not null;default:1" json:"reviewers_per_answer"`
+	DisagreementThreshold float64 `gorm:"not null;default:0.25" json:"disagreement_threshold"` // share of max score
+
+	// AI-assisted grading: experts get a suggested grade from the configured AI provider
+	AIGrading bool `gorm:"not null;default:false" json:"ai_grading"`
+
+	// Per-assessment overrides on top of the active scoring config
+	CompetencyWeights map[string]float64 `gorm:"type:jsonb;serializer:json" json:"competency_weights"`
+
+	// Test structure (TEST_STRUCTURE): questions per type and per level; empty — no constraint.
+	// Both add up to TotalQuestions and are checked against the approved pool before publishing.
+	QuestionTypes     map[QuestionType]int    `gorm:"type:jsonb;serializer:json" json:"question_types"`
+	LevelDistribution map[DifficultyLevel]int `gorm:"type:jsonb;serializer:json" json:"level_distribution"`
+
 	// Relationships
 	Competencies []AssessmentCompetency `gorm:"foreignKey:AssessmentID" json:"competencies"`
-	Tags         []AssessmentTag        `gorm:"foreignKey:AssessmentID" json:"tags"`
+	Tags         []AssessmentTag        `gorm:"many2many:assessment_tag_associations;joinForeignKey:AssessmentID;joinReferences:TagID" json:"tags"`
 	Questions    []AssessmentQuestion   `gorm:"foreignKey:AssessmentID" json:"questions"`
 	Sessions     []AssessmentSession    `gorm:"foreignKey:AssessmentID" json:"sessions"`
 	Invitations  []Invitation           `gorm:"foreignKey:AssessmentID" json:"invitations"`
 }
 
+// AvailabilityWindow окно, в которое можно начать попытку. OpensAt/ClosesAt — моменты времени
+// (хранятся в UTC), Timezone — IANA-пояс, в котором окно задавали и в котором его показывают.
+// Пустая граница — без ограничения с этой стороны.
+type AvailabilityWindow struct {
+	OpensAt  *time.Time `gorm:"type:timestamp" json:"opens_at"`
+	ClosesAt *time.Time `gorm:"type:timestamp" json:"closes_at"`
+	Timezone string     `gorm:"type:varchar(64)" json:"timezone,omitempty" binding:"omitempty,timezone"`
+}
+
+// IsSet задана ли хотя бы одна граница окна
+func (w AvailabilityWindow) IsSet() bool {
+	return w.OpensAt != nil || w.ClosesAt != nil
+}
+
 // AssessmentQuestion связь между оценкой и вопросами
 type AssessmentQuestion struct {
 	BaseModel
@@ -53,14 +91,12 @@ type AssessmentCompetency struct {
 	Assessment Assessment `gorm:"foreignKey:AssessmentID"`
 }
 
-// AssessmentTag теги оценки
+// AssessmentTag тег из справочника тегов оценок (связь с оценками — assessment_tag_associations)
 type AssessmentTag struct {
-	BaseModel
-	AssessmentID string `gorm:"type:uuid;not null;index" json:"assessment_id"`
-	Name         string `gorm:"type:varchar(100);not null" json:"name"`
-
-	// Relationship
-	Assessment Assessment `gorm:"foreignKey:AssessmentID"`
+	ID          string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name        string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	CreatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
 }
 
 // AssessmentSession сессия кандидата
@@ -68,6 +104,7 @@ type AssessmentSession struct {
 	BaseModel
 	AssessmentID string        `gorm:"type:uuid;not null;index" json:"assessment_id"`
 	CandidateID  string        `gorm:"type:uuid;not null;index" json:"candidate_id"`
+	InvitationID *string       `gorm:"type:uuid;index" json:"invitation_id"` // invitation the attempt was started from
 	Status       SessionStatus `gorm:"type:varchar(20);default:'pending'" json:"status"`
 	StartedAt    *time.Time    `gorm:"type:timestamp" json:"started_at"`
 	CompletedAt  *time.Time    `gorm:"type:timestamp" json:"completed_at"`
@@ -95,6 +132,14 @@ type CandidateAnswer struct {
 	IsCorrect   bool       `gorm:"default:false" json:"is_correct"`
 	Score       float64    `json:"score"`
 
+	// TimeSpent is measured by the server from StartedAt (question served) to SubmittedAt;
+	// the client-reported value is kept for audit only
+	ClientTimeSpent int  `gorm:"default:0" json:"client_time_spent"`
+	IsLate          bool `gorm:"default:false" json:"is_late"`
+
+	// Per-criterion scores when the question has a rubric
+	CriterionScores []CriterionScore `gorm:"type:jsonb;serializer:json" json:"criterion_scores"`
+
 	// Relationships
 	Session  AssessmentSession `gorm:"foreignKey:SessionID"`
 	Question Question          `gorm:"foreignKey:QuestionID"`
@@ -110,6 +155,25 @@ type Result struct {
 	TimeSpent   int       `gorm:"not null" json:"time_spent"`
 	CompletedAt time.Time `gorm:"type:timestamp;not null" json:"completed_at"`
 
+	// Achieved/possible per competency, computed at scoring time
+	CompetencyBreakdown []CompetencyScore `gorm:"type:jsonb;serializer:json" json:"competency_breakdown"`
+	// Version of the scoring config (scoring_configs.version) that produced this result
+	ScoringConfigVersion int `gorm:"not null;default:0" json:"scoring_config_version"`
+	// Assessment competency weight overrides in effect at scoring time (later edits don't apply)
+	CompetencyWeights map[string]float64 `gorm:"type:jsonb;serializer:json" json:"competency_weights,omitempty"`
+	// Why the level was (or wasn't) reached
+	LevelExplanation *LevelExplanation `gorm:"type:jsonb;serializer:json" json:"level_explanation,omitempty"`
+
+	// Anti-farming: score per difficulty level after caps, and which caps were applied
+	LevelScores      map[string]float64 `gorm:"type:jsonb;serializer:json" json:"level_scores"`
+	AntiFarmingFlags []AntiFarmingFlag  `gorm:"type:jsonb;serializer:json" json:"anti_farming_flags,omitempty"`
+	FarmingCapped    bool               `gorm:"not null;default:false;index" json:"farming_capped"`
+
+	// Strengths, gaps and study recommendations mapped to the competency matrix
+	Feedback            []Feedback `gorm:"type:jsonb;serializer:json" json:"feedback"`
+	FeedbackGeneratedAt *time.Time `gorm:"type:timestamp" json:"feedback_generated_at,omitempty"`
+	FeedbackAIModel     string     `gorm:"type:varchar(200)" json:"feedback_ai_model,omitempty"`
+
 	// Relationships
 	Session AssessmentSession `gorm:"foreignKey:SessionID"`
 }
@@ -124,15 +188,24 @@ type Invitation struct {
 	Status       InvitationStatus `gorm:"type:varchar(20);default:'pending'" json:"status"`
 	InvitedBy    string           `gorm:"type:uuid;not null" json:"invited_by"`
 	OpenedAt     *time.Time       `gorm:"type:timestamp" json:"opened_at"`
+	AcceptedAt   *time.Time       `gorm:"type:timestamp" json:"accepted_at"`
+	CompletedAt  *time.Time       `gorm:"type:timestamp" json:"completed_at"`
 	ExpiresAt    time.Time        `gorm:"type:timestamp;not null" json:"expires_at"`
 
+	// Personal window; when set it replaces the assessment window for this candidate
+	Availability AvailabilityWindow `gorm:"embedded" json:"availability"`
+
 	// Relationships
-	Assessment Assessment `gorm:"foreignKey:AssessmentID"`
+	Assessment Assessment `gorm:"foreignKey:AssessmentID" json:"-"`
 }
 
 // ScoreResult результат оценки
 type ScoreResult struct {
-	TotalScore float64 `json:"total_score"`
-	Percentage float64 `json:"percentage"`
-	Level      string  `json:"level"`
+	TotalScore          float64           `json:"total_score"`
+	Percentage          float64           `json:"percentage"`
+	Level               string            `json:"level"`
+	CompetencyBreakdown []CompetencyScore `json:"competency_breakdown"`
+	LevelExplanation    *LevelExplanation `json:"level_explanation"`
+	LevelScores         map[string]float64 `json:"level_scores"`
+	AntiFarmingFlags    []AntiFarmingFlag  `json:"anti_farming_flags"`
 }
@@ -19,7 +19,20 @@ type CreateAssessmentRequest struct {
 	ShuffleQuestions bool               `json:"shuffle_questions"`
 	ShowExplanation  bool               `json:"show_explanation"`
 	Competencies     []CompetencyWeight `json:"competencies" binding:"required,min=1"`
-	Tags             []string           `json:"tags"`
+	Tags             []string           `json:"tags" binding:"omitempty,max=20,dive,max=100"`
+
+	Availability    *AvailabilityWindow `json:"availability"`
+	MaxAttempts     int                 `json:"max_attempts" binding:"omitempty,min=0,max=100"`
+	AttemptCooldown int                 `json:"attempt_cooldown" binding:"omitempty,min=0"` // seconds
+
+	ReviewersPerAnswer    int     `json:"reviewers_per_answer" binding:"omitempty,min=1,max=5"`
+	DisagreementThreshold float64 `json:"disagreement_threshold" binding:"omitempty,gt=0,max=1"`
+	AIGrading             bool    `json:"ai_grading"`
+
+	CompetencyWeights map[string]float64 `json:"competency_weights" binding:"omitempty,dive,gt=0,max=5"`
+
+	QuestionTypes     map[QuestionType]int    `json:"question_types"`     // each adds up to total_questions
+	LevelDistribution map[DifficultyLevel]int `json:"level_distribution"` // empty — no constraint
 }
 
 // CompetencyWeight вес компетенции в оценке
@@ -79,11 +92,88 @@ func (c *CompetencyWeight) UnmarshalJSON(data []byte) error {
 type UpdateAssessmentRequest struct {
 	Title            *string           `json:"title"`
 	Description      *string           `json:"description"`
-	Status           *AssessmentStatus `json:"status"`
+	Status           *AssessmentStatus `json:"status"` // goes through the same transitions as /publish and /archive
 	TimeLimit        *int              `json:"time_limit"`
 	PassingScore     *float64          `json:"passing_score"`
 	ShuffleQuestions *bool             `json:"shuffle_questions"`
 	ShowExplanation  *bool             `json:"show_explanation"`
+
+	ReviewersPerAnswer    *int     `json:"reviewers_per_answer" binding:"omitempty,min=1,max=5"`
+	DisagreementThreshold *float64 `json:"disagreement_threshold" binding:"omitempty,gt=0,max=1"`
+	AIGrading             *bool    `json:"ai_grading"`
+
+	CompetencyWeights map[string]float64 `json:"competency_weights" binding:"omitempty,dive,gt=0,max=5"` // replaces overrides; {} clears
+
+	QuestionTypes     map[QuestionType]int    `json:"question_types"`     // replaces the structure; {} clears
+	LevelDistribution map[DifficultyLevel]int `json:"level_distribution"` // replaces the structure; {} clears
+
+	Tags *[]string `json:"tags" binding:"omitempty,max=20,dive,max=100"` // replaces tags; [] clears
+
+	Availability    *AvailabilityWindow `json:"availability"` // replaces the window; {} removes it
+	MaxAttempts     *int                `json:"max_attempts" binding:"omitempty,min=0,max=100"`
+	AttemptCooldown *int                `json:"attempt_cooldown" binding:"omitempty,min=0"`
+}
+
+// InviteCandidateRequest приглашение кандидата, при необходимости — со своим окном доступности
+type InviteCandidateRequest struct {
+	Email        string              `json:"email" binding:"required,email"`
+	Availability *AvailabilityWindow `json:"availability"`
+}
+
+// InvitationView что видит кандидат, открыв ссылку из письма
+type InvitationView struct {
+	Invitation     *Invitation        `json:"invitation"`
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	TimeLimit      int                `json:"time_limit"`
+	TotalQuestions int                `json:"total_questions"`
+	Availability   AvailabilityWindow `json:"availability"`   // the invitation window if set, otherwise the assessment one
+	AccountExists  bool               `json:"account_exists"` // accepting without a login then needs this account's password
+}
+
+// AcceptInvitationRequest принятие приглашения. Без авторизации: для нового аккаунта — имя и пароль,
+// для уже зарегистрированного email — пароль этого аккаунта. Авторизованному кандидату тело не нужно.
+type AcceptInvitationRequest struct {
+	Name     string `json:"name" binding:"omitempty,max=255"`
+	Password string `json:"password"`
+}
+
+// InvitationAcceptance итог принятия: приглашение, привязанная к нему сессия или причина, по которой
+// начать её пока нельзя (окно ещё не открылось, попытки исчерпаны)
+type InvitationAcceptance struct {
+	Invitation     *Invitation         `json:"invitation"`
+	Session        *AssessmentSession  `json:"session,omitempty"`
+	Availability   *AvailabilityStatus `json:"availability,omitempty"`
+	AccountCreated bool                `json:"account_created"`
+}
+
+// AvailabilityStatus может ли кандидат начать новую попытку
+type AvailabilityStatus struct {
+	AssessmentID  string             `json:"assessment_id"`
+	Available     bool               `json:"available"`
+	Reason        string             `json:"reason,omitempty"` // not_open, closed, attempts_exhausted, cooldown
+	Window        AvailabilityWindow `json:"window"`           // the invitation window if set, otherwise the assessment one
+	InvitationID  string             `json:"invitation_id,omitempty"`
+	AttemptsUsed  int                `json:"attempts_used"`
+	MaxAttempts   int                `json:"max_attempts"` // 0 — unlimited
+	NextAttemptAt *time.Time         `json:"next_attempt_at,omitempty"`
+}
+
+// CompetencyCoverage одобренные вопросы банка для компетенции оценки
+type CompetencyCoverage struct {
+	CompetencyID string `json:"competency_id"`
+	Level        string `json:"level"`
+	MinQuestions int    `json:"min_questions"`
+	Available    int64  `json:"available"` // approved, active, latest versions
+	Enough       bool   `json:"enough"`
+}
+
+// PublishCheck готовность оценки к публикации
+type PublishCheck struct {
+	AssessmentID string               `json:"assessment_id"`
+	Ready        bool                 `json:"ready"`
+	Problems     []string             `json:"problems"`
+	Competencies []CompetencyCoverage `json:"competencies"`
 }
 
 // InviteCandidatesRequest запрос на приглашение кандидатов
@@ -92,13 +182,35 @@ type InviteCandidatesRequest struct {
 	Message string   `json:"message"`
 }
 
-// CandidateAnswerRequest запрос с ответом кандидата
+// CandidateAnswerRequest запрос с ответом кандидата.
+// TimeSpent от клиента только сохраняется для аудита — время считает сервер.
 type CandidateAnswerRequest struct {
 	Answer    string `json:"answer"`
 	Code      string `json:"code"`
 	TimeSpent int    `json:"time_spent" binding:"min=0"`
 }
 
+// ServedQuestion вопрос, выданный кандидату в сессии (без правильных ответов)
+type ServedQuestion struct {
+	ID          string                 `json:"id"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Type        QuestionType           `json:"type"`
+	Difficulty  DifficultyLevel        `json:"difficulty"`
+	Options     []ServedQuestionOption `json:"options,omitempty"`
+	Files       map[string]string      `json:"starter_files,omitempty"`
+	TimeLimit   int                    `json:"time_limit"`
+	ServedAt    time.Time              `json:"served_at"`
+	Deadline    time.Time              `json:"deadline"`
+}
+
+// ServedQuestionOption вариант ответа без признака правильности
+type ServedQuestionOption struct {
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+	Order int    `json:"order"`
+}
+
 // AssessmentResponse ответ с данными оценки
 type AssessmentResponse struct {
 	ID             string             `json:"id"`
@@ -116,6 +228,14 @@ type AssessmentResponse struct {
 	Tags           []string           `json:"tags"`
 }
 
+// AssessmentList страница списка оценок
+type AssessmentList struct {
+	Assessments []Assessment `json:"assessments"`
+	Total       int64        `json:"total"` // all assessments matching the filters, not just this page
+	Limit       int          `json:"limit"`
+	NextCursor  string       `json:"next_cursor,omitempty"` // empty on the last page
+}
+
 // SessionProgress прогресс сессии
 type SessionProgress struct {
 	SessionID       string        `json:"session_id"`
@@ -141,9 +261,27 @@ type AssessmentReport struct {
 type CompetencyScore struct {
 	CompetencyID   string  `json:"competency_id"`
 	CompetencyName string  `json:"competency_name"`
+	Category       string  `json:"category,omitempty"`
+	Description    string  `json:"description,omitempty"`
 	Achieved       float64 `json:"achieved"`
 	Possible       float64 `json:"possible"`
 	Percentage     float64 `json:"percentage"`
+	Level          string  `json:"level"`
+	Weight         float64 `json:"weight"`
+	Questions      int     `json:"questions"`
+	Correct        int     `json:"correct"`
+}
+
+// ResultBreakdown разбивка результата по компетенциям (GET /results/{id}/breakdown)
+type ResultBreakdown struct {
+	ResultID            string            `json:"result_id"`
+	SessionID           string            `json:"session_id"`
+	TotalScore          float64           `json:"total_score"`
+	Percentage          float64           `json:"percentage"`
+	Level               string            `json:"level"`
+	CompetencyScores    []CompetencyScore `json:"competency_scores"`
+	Strengths           []string          `json:"strengths"`
+	AreasForImprovement []string          `json:"areas_for_improvement"`
 }
 
 // AnswerDetail детали ответа
@@ -157,6 +295,8 @@ type AnswerDetail struct {
 	PointsEarned  float64 `json:"points_earned"`
 	TimeSpent     int     `json:"time_spent"`
 	Explanation   string  `json:"explanation,omitempty"`
+
+	CriterionScores []CriterionScore `json:"criterion_scores,omitempty"`
 }
 
 // BulkInviteRequest запрос на массовое приглашение
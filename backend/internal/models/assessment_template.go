@@ -0,0 +1,77 @@
+package models
+
+// AssessmentTemplate заготовка оценки: настройки, компетенции и структура теста.
+// Встроенные шаблоны засеяны миграцией по TEST_STRUCTURE (docs/assessment-framework.md)
+// и не меняются через API.
+type AssessmentTemplate struct {
+	BaseModel
+	Key         string  `gorm:"type:varchar(100);uniqueIndex;not null" json:"key"`
+	Name        string  `gorm:"type:varchar(255);not null" json:"name"`
+	Description string  `gorm:"type:text" json:"description"`
+	IsBuiltin   bool    `gorm:"not null;default:false" json:"is_builtin"`
+	CreatedBy   *string `gorm:"type:uuid" json:"created_by"`
+
+	// Настройки создаваемой оценки
+	Type                  string  `gorm:"type:varchar(50);not null" json:"type"`
+	TargetLevel           string  `gorm:"type:varchar(50);not null" json:"target_level"`
+	TimeLimit             int     `gorm:"not null" json:"time_limit"` // seconds
+	TotalQuestions        int     `gorm:"not null" json:"total_questions"`
+	PassingScore          float64 `gorm:"not null;default:70" json:"passing_score"`
+	ShuffleQuestions      bool    `gorm:"not null;default:true" json:"shuffle_questions"`
+	ShowExplanation       bool    `gorm:"not null;default:true" json:"show_explanation"`
+	ReviewersPerAnswer    int     `gorm:"not null;default:1" json:"reviewers_per_answer"`
+	DisagreementThreshold float64 `gorm:"not null;default:0.25" json:"disagreement_threshold"`
+	AIGrading             bool    `gorm:"not null;default:false" json:"ai_grading"`
+
+	Competencies      []CompetencyWeight `gorm:"type:jsonb;serializer:json" json:"competencies"`
+	CompetencyWeights map[string]float64 `gorm:"type:jsonb;serializer:json" json:"competency_weights,omitempty"`
+	Tags              []string           `gorm:"type:jsonb;serializer:json" json:"tags"`
+
+	// Структура теста (TEST_STRUCTURE): число вопросов по типам и по уровням
+	QuestionTypes     map[QuestionType]int    `gorm:"type:jsonb;serializer:json" json:"question_types"`
+	LevelDistribution map[DifficultyLevel]int `gorm:"type:jsonb;serializer:json" json:"level_distribution"`
+}
+
+// AssessmentTemplateRequest создание или изменение шаблона.
+// TotalQuestions по умолчанию — сумма QuestionTypes, TimeLimit — по нормам времени TEST_STRUCTURE.
+type AssessmentTemplateRequest struct {
+	Key              string             `json:"key" binding:"required,min=3,max=100"`
+	Name             string             `json:"name" binding:"required,min=3,max=255"`
+	Description      string             `json:"description"`
+	Type             AssessmentType     `json:"type" binding:"required,oneof=technical behavioral mixed"`
+	TargetLevel      DifficultyLevel    `json:"target_level" binding:"required,oneof=junior middle senior expert"`
+	TimeLimit        int                `json:"time_limit" binding:"omitempty,min=300,max=10800"`
+	TotalQuestions   int                `json:"total_questions" binding:"omitempty,min=1,max=100"`
+	PassingScore     float64            `json:"passing_score" binding:"min=0,max=100"`
+	ShuffleQuestions bool               `json:"shuffle_questions"`
+	ShowExplanation  bool               `json:"show_explanation"`
+	Competencies     []CompetencyWeight `json:"competencies" binding:"required,min=1"`
+	Tags             []string           `json:"tags"`
+
+	ReviewersPerAnswer    int     `json:"reviewers_per_answer" binding:"omitempty,min=1,max=5"`
+	DisagreementThreshold float64 `json:"disagreement_threshold" binding:"omitempty,gt=0,max=1"`
+	AIGrading             bool    `json:"ai_grading"`
+
+	CompetencyWeights map[string]float64      `json:"competency_weights" binding:"omitempty,dive,gt=0,max=5"`
+	QuestionTypes     map[QuestionType]int    `json:"question_types"`
+	LevelDistribution map[DifficultyLevel]int `json:"level_distribution"`
+}
+
+// InstantiateTemplateRequest создание оценки из шаблона; пустые поля берутся из шаблона
+type InstantiateTemplateRequest struct {
+	Title        string          `json:"title" binding:"omitempty,min=3,max=255"`
+	Description  *string         `json:"description"`
+	TargetLevel  DifficultyLevel `json:"target_level" binding:"omitempty,oneof=junior middle senior expert"`
+	TimeLimit    int             `json:"time_limit" binding:"omitempty,min=300,max=10800"`
+	PassingScore *float64        `json:"passing_score" binding:"omitempty,min=0,max=100"`
+	Tags         []string        `json:"tags"` // добавляются к тегам шаблона
+
+	Availability    *AvailabilityWindow `json:"availability"`
+	MaxAttempts     int                 `json:"max_attempts" binding:"omitempty,min=0,max=100"`
+	AttemptCooldown int                 `json:"attempt_cooldown" binding:"omitempty,min=0"`
+}
+
+// CloneAssessmentRequest копия оценки; по умолчанию заголовок «<title> (copy)»
+type CloneAssessmentRequest struct {
+	Title string `json:"title" binding:"omitempty,min=3,max=255"`
+}
@@ -0,0 +1,56 @@
+package models
+
+// CodeEditDelta одно изменение в редакторе кандидата.
+// T — миллисекунды от момента открытия вопроса, P — позиция (в символах),
+// D — сколько символов удалено начиная с P, I — вставленный текст.
+type CodeEditDelta struct {
+	T int64  `json:"t"`
+	P int    `json:"p"`
+	D int    `json:"d,omitempty"`
+	I string `json:"i,omitempty"`
+}
+
+// AnswerEditBatch пачка дельт, присланная клиентом (хранится в сжатом виде)
+type AnswerEditBatch struct {
+	BaseModel
+	SessionID  string `gorm:"type:uuid;not null;uniqueIndex:idx_edit_batch_seq" json:"session_id"`
+	QuestionID string `gorm:"type:uuid;not null;uniqueIndex:idx_edit_batch_seq" json:"question_id"`
+	Seq        int    `gorm:"not null;uniqueIndex:idx_edit_batch_seq" json:"seq"`
+	Encoding   string `gorm:"type:varchar(20);not null;default:'gzip+json'" json:"encoding"`
+	Payload    []byte `gorm:"type:bytea;not null" json:"-"`
+	DeltaCount int    `gorm:"not null" json:"delta_count"`
+	FirstT     int64  `gorm:"not null" json:"first_t"`
+	LastT      int64  `gorm:"not null" json:"last_t"`
+}
+
+// EditHistoryRequest запрос с пачкой дельт.
+//
+// Поддерживает два формата:
+// 1) "deltas": [...] — обычный JSON
+// 2) "encoding":"gzip+base64", "data":"..." — base64(gzip(JSON массива дельт))
+type EditHistoryRequest struct {
+	Seq      int             `json:"seq" binding:"min=0"`
+	Encoding string          `json:"encoding" binding:"omitempty,oneof=json gzip+base64"`
+	Data     string          `json:"data"`
+	Deltas   []CodeEditDelta `json:"deltas"`
+}
+
+// PasteEvent подозрительно большая вставка
+type PasteEvent struct {
+	T      int64  `json:"t"`
+	P      int    `json:"p"`
+	Length int    `json:"length"`
+	Sample string `json:"sample"`
+}
+
+// CodeReplay состояние кода на момент времени At
+type CodeReplay struct {
+	SessionID     string       `json:"session_id"`
+	QuestionID    string       `json:"question_id"`
+	At            int64        `json:"at"`
+	Duration      int64        `json:"duration"`
+	Code          string       `json:"code"`
+	TotalDeltas   int          `json:"total_deltas"`
+	AppliedDeltas int          `json:"applied_deltas"`
+	Pastes        []PasteEvent `json:"pastes"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Виды отзыва по результату
+const (
+	FeedbackTypeSummary        = "summary"
+	FeedbackTypeStrength       = "strength"
+	FeedbackTypeGap            = "gap"
+	FeedbackTypeRecommendation = "recommendation"
+)
+
+// Важность пункта отзыва
+const (
+	FeedbackSeverityInfo     = "info"
+	FeedbackSeverityWarning  = "warning"
+	FeedbackSeverityCritical = "critical"
+)
+
+// Источник пункта отзыва
+const (
+	FeedbackSourceRules = "rules" // правила по разбивке и матрице компетенций
+	FeedbackSourceAI    = "ai"    // дополнено моделью
+)
+
+// Feedback пункт отзыва по результату: сильная сторона, пробел или рекомендация.
+// Формат {type, message, severity} совпадает с results.feedback общей схемы;
+// остальные поля — привязка к матрице компетенций (docs/competency-matrix.md).
+type Feedback struct {
+	Type       string `json:"type"`
+	Message    string `json:"message"`
+	Severity   string `json:"severity"` // info, warning, critical
+	Competency string `json:"competency,omitempty"`
+	Matrix     string `json:"matrix,omitempty"` // строка матрицы компетенций
+	Level      string `json:"level,omitempty"`  // уровень матрицы, к которому относится пункт
+	Source     string `json:"source"`
+
+	// Missed questions behind a gap; staff only, stripped from candidate feedback
+	QuestionIDs []string `json:"question_ids,omitempty"`
+}
+
+// ResultFeedback отзыв по результату
+type ResultFeedback struct {
+	ResultID    string     `json:"result_id"`
+	SessionID   string     `json:"session_id"`
+	Level       string     `json:"level"`
+	Percentage  float64    `json:"percentage"`
+	Feedback    []Feedback `json:"feedback"`
+	GeneratedAt *time.Time `json:"generated_at,omitempty"`
+	AIModel     string     `json:"ai_model,omitempty"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// ItemResponse ответ кандидата на вопрос в завершённой сессии — исходные данные калибровки
+type ItemResponse struct {
+	SessionID  string
+	QuestionID string
+	Correct    bool
+}
+
+// IRTParams параметры 2PL: P(θ) = 1 / (1 + exp(-a(θ - b)))
+type IRTParams struct {
+	QuestionID     string  `json:"question_id"`
+	Discrimination float64 `json:"discrimination"` // a
+	Difficulty     float64 `json:"difficulty"`     // b
+	Responses      int     `json:"responses"`
+	CorrectRate    float64 `json:"correct_rate"`
+}
+
+// AbilityEstimate оценка способности кандидата θ (EAP, априорное N(0, 1))
+type AbilityEstimate struct {
+	Theta    float64 `json:"theta"`
+	StdError float64 `json:"std_error"`
+	Items    int     `json:"items"` // откалиброванных вопросов в оценке
+}
+
+const (
+	CalibrationStatusRunning   = "running"
+	CalibrationStatusCompleted = "completed"
+	CalibrationStatusFailed    = "failed"
+)
+
+// CalibrationRun запуск калибровки банка вопросов
+type CalibrationRun struct {
+	BaseModel
+	Status        string     `gorm:"type:varchar(20);not null" json:"status"`
+	StartedAt     time.Time  `gorm:"type:timestamp;not null" json:"started_at"`
+	FinishedAt    *time.Time `gorm:"type:timestamp" json:"finished_at"`
+	MinResponses  int        `gorm:"not null" json:"min_responses"`
+	Sessions      int        `gorm:"not null;default:0" json:"sessions"`
+	Responses     int        `gorm:"not null;default:0" json:"responses"`
+	Calibrated    int        `gorm:"not null;default:0" json:"calibrated"` // вопросов с новыми параметрами
+	Skipped       int        `gorm:"not null;default:0" json:"skipped"`    // мало ответов
+	Iterations    int        `gorm:"not null;default:0" json:"iterations"`
+	Converged     bool       `gorm:"not null;default:false" json:"converged"`
+	LogLikelihood float64    `gorm:"not null;default:0" json:"log_likelihood"`
+	Error         string     `gorm:"type:text" json:"error,omitempty"`
+	TriggeredBy   *string    `gorm:"type:uuid" json:"triggered_by"`
+
+	Params []IRTParams `gorm:"-" json:"params,omitempty"`
+}
+
+func (CalibrationRun) TableName() string {
+	return "irt_calibrations"
+}
+
+// CalibrationRequest параметры запуска; нули — значения по умолчанию
+type CalibrationRequest struct {
+	MinResponses  int `json:"min_responses"`
+	MaxIterations int `json:"max_iterations"`
+}
@@ -1,28 +1,94 @@
 package models
 
+import "time"
+
 // Question представляет вопрос
 type Question struct {
     BaseModel
-    Title       string           `gorm:"type:varchar(500);not null" json:"title"`
-    Description string           `gorm:"type:text" json:"description"`
-    Type        QuestionType     `gorm:"type:varchar(50);not null" json:"type"`
-    Difficulty  DifficultyLevel  `gorm:"type:varchar(20);not null" json:"difficulty"`
-    Competency  string           `gorm:"type:varchar(100);not null" json:"competency"`
-    Tags        []QuestionTag    `gorm:"foreignKey:QuestionID" json:"tags"`
-    Options     []QuestionOption `gorm:"foreignKey:QuestionID" json:"options"`
-    TestCases   []TestCase       `gorm:"foreignKey:QuestionID" json:"test_cases"`
-    Explanation string           `gorm:"type:text" json:"explanation"`
-    TimeLimit   int              `gorm:"default:300" json:"time_limit"` // в секундах
-    Points      int              `gorm:"default:1" json:"points"`
-    IsActive    bool             `gorm:"default:true" json:"is_active"`
-    CreatedBy   string           `gorm:"type:uuid;not null" json:"created_by"`
-}
-
-// QuestionTag тег вопроса
+    Title       string            `gorm:"type:varchar(500);not null" json:"title"`
+    Description string            `gorm:"type:text" json:"description"`
+    Type        QuestionType      `gorm:"type:varchar(50);not null" json:"type"`
+    Difficulty  DifficultyLevel   `gorm:"type:varchar(20);not null" json:"difficulty"`
+    Competency  string            `gorm:"type:varchar(100);not null" json:"competency"`
+    Tags        []QuestionTag     `gorm:"many2many:question_tag_associations;joinForeignKey:QuestionID;joinReferences:TagID" json:"tags"`
+    Options     []QuestionOption  `gorm:"foreignKey:QuestionID" json:"options"`
+    TestCases   []TestCase        `gorm:"foreignKey:QuestionID" json:"test_cases"`
+    Rubric      []RubricCriterion `gorm:"foreignKey:QuestionID" json:"rubric"`
+    Explanation string            `gorm:"type:text" json:"explanation"`
+    TimeLimit   int               `gorm:"default:300" json:"time_limit"` // в секундах
+    Points      int               `gorm:"default:1" json:"points"`
+    IsActive    bool              `gorm:"default:true" json:"is_active"`
+    CreatedBy   string            `gorm:"type:uuid;not null" json:"created_by"` // автор этой версии
+
+    // Стартовые файлы для coding/debugging: имя файла → содержимое
+    StarterFiles map[string]string `gorm:"type:jsonb;serializer:json" json:"starter_files,omitempty"`
+
+    // Сгенерирован моделью (провайдер/модель в AIModel); до одобрения экспертом не выдаётся
+    AIGenerated bool   `gorm:"not null;default:false;index" json:"ai_generated"`
+    AIModel     string `gorm:"type:varchar(200)" json:"ai_model,omitempty"`
+
+    // Стабильный id для импорта/экспорта банка: общий для всех версий, по умолчанию — RootID
+    ExternalID string `gorm:"type:varchar(200);index" json:"external_id"`
+
+    // Версии: правка создаёт новую строку, старая остаётся неизменной — сессии,
+    // где кандидат её видел, оцениваются по ней. RootID общий для всех версий вопроса.
+    RootID            string  `gorm:"type:uuid;index" json:"root_id"`
+    Version           int     `gorm:"not null;default:1" json:"version"`
+    PreviousVersionID *string `gorm:"type:uuid" json:"previous_version_id"`
+    IsLatest          bool    `gorm:"not null;default:true;index" json:"is_latest"`
+
+    // Валидация: draft → pending (на проверке) → approved; в новые сессии попадают только approved
+    ValidationStatus ValidationStatus `gorm:"type:varchar(50);not null;default:'draft';index" json:"validation_status"`
+    ValidatedBy      *string          `gorm:"type:uuid" json:"validated_by"`
+    ValidatedAt      *time.Time       `gorm:"type:timestamp" json:"validated_at"`
+
+    // Эталонное решение: прогоняется на TestCases в executor при отправке на проверку и одобрении
+    ReferenceSolution string `gorm:"type:text" json:"reference_solution,omitempty"`
+    SolutionLanguage  string `gorm:"type:varchar(20)" json:"solution_language,omitempty"` // go, python, javascript
+
+    // Параметры 2PL IRT из последней калибровки (nil — вопрос ещё не откалиброван)
+    IRTDiscrimination *float64   `gorm:"column:irt_discrimination" json:"irt_discrimination"`
+    IRTDifficulty     *float64   `gorm:"column:irt_difficulty" json:"irt_difficulty"`
+    IRTResponses      int        `gorm:"column:irt_responses;default:0" json:"irt_responses"`
+    IRTCalibratedAt   *time.Time `gorm:"column:irt_calibrated_at;type:timestamp" json:"irt_calibrated_at"`
+
+    // Похожие вопросы банка — предупреждение при создании/импорте (не хранится)
+    Similar []SimilarQuestion `gorm:"-" json:"similar_questions,omitempty"`
+
+    // Полнотекстовый поиск (?q=): ранг и фрагменты с подсветкой <mark>…</mark>; заполняются только в выдаче поиска
+    SearchRank     float64 `gorm:"->;-:migration" json:"search_rank,omitempty"`
+    TitleHighlight string  `gorm:"->;-:migration" json:"title_highlight,omitempty"`
+    Snippet        string  `gorm:"->;-:migration" json:"snippet,omitempty"`
+}
+
+// IsCalibrated есть ли у вопроса параметры IRT
+func (q Question) IsCalibrated() bool {
+    return q.IRTDiscrimination != nil && q.IRTDifficulty != nil
+}
+
+// QuestionTag тег из справочника question_tags; с вопросами связан через question_tag_associations.
+// Теги — метаданные банка, а не содержимое: их можно менять без новой версии вопроса.
 type QuestionTag struct {
-    BaseModel
-    QuestionID string `gorm:"type:uuid;not null;index" json:"question_id"`
-    Tag        string `gorm:"type:varchar(100);not null" json:"tag"`
+    ID          string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+    Name        string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+    Description string    `gorm:"type:text" json:"description,omitempty"`
+    Color       string    `gorm:"type:varchar(7)" json:"color,omitempty"`
+    CreatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+
+    // Число последних версий вопросов с тегом (только в списке тегов)
+    QuestionCount int64 `gorm:"->;-:migration" json:"question_count"`
+}
+
+// QuestionTagRequest создание или изменение тега
+type QuestionTagRequest struct {
+    Name        string `json:"name" binding:"required,max=100"`
+    Description string `json:"description"`
+    Color       string `json:"color" binding:"omitempty,hexcolor,max=7"`
+}
+
+// SetQuestionTagsRequest полная замена тегов вопроса (несуществующие теги создаются)
+type SetQuestionTagsRequest struct {
+    Tags []string `json:"tags"`
 }
 
 // QuestionOption вариант ответа
@@ -43,3 +109,36 @@ type TestCase struct {
     IsHidden    bool   `gorm:"default:false" json:"is_hidden"`
     Order       int    `gorm:"not null" json:"order"`
 }
+
+// QuestionRequest создание вопроса или новая версия (полная замена содержимого)
+type QuestionRequest struct {
+    Title       string                `json:"title" binding:"required,max=500"`
+    Description string                `json:"description"`
+    Type        QuestionType          `json:"type" binding:"required"`
+    Difficulty  DifficultyLevel       `json:"difficulty" binding:"required"`
+    Competency  string                `json:"competency" binding:"required,max=100"`
+    Tags        []string              `json:"tags"`
+    Options     []QuestionOptionInput `json:"options"`
+    TestCases   []TestCaseInput       `json:"test_cases"`
+    Explanation string                `json:"explanation"`
+    TimeLimit   int                   `json:"time_limit" binding:"min=0"`
+    Points      int                   `json:"points" binding:"min=0"`
+    IsActive    *bool                 `json:"is_active"`
+
+    ExternalID   string            `json:"external_id" binding:"max=200"` // только при создании
+    StarterFiles map[string]string `json:"starter_files"`
+
+    ReferenceSolution string `json:"reference_solution"`
+    SolutionLanguage  string `json:"solution_language" binding:"omitempty,oneof=go python javascript"`
+}
+
+type QuestionOptionInput struct {
+    Text      string `json:"text" binding:"required"`
+    IsCorrect bool   `json:"is_correct"`
+}
+
+type TestCaseInput struct {
+    Input    string `json:"input"`
+    Expected string `json:"expected"`
+    IsHidden bool   `json:"is_hidden"`
+}
@@ -0,0 +1,65 @@
+package models
+
+// QuestionAnswerSample ответ на вопрос в завершённой сессии вместе с итоговым процентом сессии
+type QuestionAnswerSample struct {
+	QuestionID      string
+	Answer          string
+	IsCorrect       bool
+	TimeSpent       int
+	Submitted       bool
+	TotalPercentage float64
+}
+
+const (
+	QuestionFlagTooEasy             = "too_easy"
+	QuestionFlagTooHard             = "too_hard"
+	QuestionFlagLowDiscrimination   = "low_discrimination"
+	QuestionFlagNegativeCorrelation = "negative_correlation" // скорее всего ошибка в ключе
+	QuestionFlagDistractorBeatsKey  = "distractor_beats_key" // неверный вариант выбирают сильные кандидаты
+	QuestionFlagUnusedDistractor    = "unused_distractor"
+	QuestionFlagSlow                = "slow"
+)
+
+// QuestionFlag автоматическое замечание к вопросу
+type QuestionFlag struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Broken  bool   `json:"broken"` // вопрос, вероятно, сломан и требует правки
+}
+
+// OptionAnalytics как часто выбирают вариант ответа и кто
+type OptionAnalytics struct {
+	OptionID      string   `json:"option_id"`
+	Text          string   `json:"text"`
+	IsCorrect     bool     `json:"is_correct"`
+	Chosen        int      `json:"chosen"`
+	Rate          float64  `json:"rate"`           // доля от отвеченных
+	MeanTotal     *float64 `json:"mean_total"`     // средний итоговый процент выбравших
+	PointBiserial *float64 `json:"point_biserial"` // корреляция выбора варианта с итоговым процентом
+}
+
+// QuestionAnalytics статистика вопроса по завершённым сессиям
+type QuestionAnalytics struct {
+	QuestionID string          `json:"question_id"`
+	Title      string          `json:"title"`
+	Type       QuestionType    `json:"type"`
+	Difficulty DifficultyLevel `json:"difficulty"`
+	Competency string          `json:"competency"`
+
+	Responses   int     `json:"responses"` // выдано в завершённых сессиях
+	Answered    int     `json:"answered"`
+	Correct     int     `json:"correct"`
+	CorrectRate float64 `json:"correct_rate"` // от выданных: без ответа — неверно
+	SkipRate    float64 `json:"skip_rate"`
+
+	AvgTime    float64 `json:"avg_time"`    // секунды, по отвеченным
+	MedianTime float64 `json:"median_time"` // секунды, по отвеченным
+	TimeLimit  int     `json:"time_limit"`
+
+	PointBiserial *float64 `json:"point_biserial"` // корреляция верности с итоговым процентом сессии
+
+	Options    []OptionAnalytics `json:"options,omitempty"`
+	Unmatched  int               `json:"unmatched,omitempty"` // ответы, не совпавшие ни с одним вариантом
+	Sufficient bool              `json:"sufficient"`          // хватает ли ответов для флагов
+	Flags      []QuestionFlag    `json:"flags"`
+}
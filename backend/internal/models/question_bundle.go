@@ -0,0 +1,106 @@
+package models
+
+import "time"
+
+// Форматы импорта/экспорта банка вопросов. GIFT и QTI переносят только multiple choice.
+const (
+	QuestionFormatYAML = "yaml"
+	QuestionFormatJSON = "json"
+	QuestionFormatGIFT = "gift"
+	QuestionFormatQTI  = "qti"
+)
+
+// QuestionBundleVersion версия схемы бандла; увеличивается при несовместимых изменениях
+const QuestionBundleVersion = 1
+
+// QuestionBundle переносимый набор вопросов (YAML/JSON)
+type QuestionBundle struct {
+	Version    int              `json:"version" yaml:"version"`
+	ExportedAt *time.Time       `json:"exported_at,omitempty" yaml:"exported_at,omitempty"`
+	Questions  []BundleQuestion `json:"questions" yaml:"questions"`
+}
+
+// BundleQuestion вопрос в бандле. ExternalID — ключ идемпотентного импорта:
+// повторный импорт того же содержимого ничего не меняет, изменённого — создаёт новую версию.
+type BundleQuestion struct {
+	ExternalID        string            `json:"external_id" yaml:"external_id"`
+	Title             string            `json:"title" yaml:"title"`
+	Description       string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Type              QuestionType      `json:"type" yaml:"type"`
+	Level             DifficultyLevel   `json:"level" yaml:"level"`
+	Competency        string            `json:"competency" yaml:"competency"`
+	Tags              []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Options           []BundleOption    `json:"options,omitempty" yaml:"options,omitempty"`
+	TestCases         []BundleTestCase  `json:"test_cases,omitempty" yaml:"test_cases,omitempty"`
+	StarterFiles      map[string]string `json:"starter_files,omitempty" yaml:"starter_files,omitempty"`
+	Explanation       string            `json:"explanation,omitempty" yaml:"explanation,omitempty"`
+	TimeLimit         int               `json:"time_limit,omitempty" yaml:"time_limit,omitempty"`
+	Points            int               `json:"points,omitempty" yaml:"points,omitempty"`
+	ReferenceSolution string            `json:"reference_solution,omitempty" yaml:"reference_solution,omitempty"`
+	SolutionLanguage  string            `json:"solution_language,omitempty" yaml:"solution_language,omitempty"`
+}
+
+type BundleOption struct {
+	Text    string `json:"text" yaml:"text"`
+	Correct bool   `json:"correct,omitempty" yaml:"correct,omitempty"`
+}
+
+type BundleTestCase struct {
+	Input    string `json:"input" yaml:"input"`
+	Expected string `json:"expected" yaml:"expected"`
+	Hidden   bool   `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+}
+
+// QuestionImportOptions параметры импорта. Competency и Level подставляются вопросам,
+// где их нет (GIFT без $CATEGORY, QTI); у существующих вопросов сохраняются их значения.
+type QuestionImportOptions struct {
+	Format     string
+	DryRun     bool
+	Competency string
+	Level      DifficultyLevel
+}
+
+// Результат импорта отдельного вопроса
+const (
+	ImportStatusCreated   = "created"
+	ImportStatusUpdated   = "updated" // создана новая версия
+	ImportStatusUnchanged = "unchanged"
+	ImportStatusFailed    = "failed"
+)
+
+// QuestionImportItem итог по одному вопросу бандла (Index — с 1, в порядке файла)
+type QuestionImportItem struct {
+	Index      int      `json:"index"`
+	ExternalID string   `json:"external_id,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Status     string   `json:"status"`
+	QuestionID string   `json:"question_id,omitempty"`
+	Version    int      `json:"version,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+
+	// Near-duplicates already in the bank (or earlier in the same file); a warning, not an error
+	Similar []SimilarQuestion `json:"similar,omitempty"`
+}
+
+// QuestionImportReport отчёт об импорте; при DryRun ничего не записано
+type QuestionImportReport struct {
+	Format    string               `json:"format"`
+	DryRun    bool                 `json:"dry_run"`
+	Total     int                  `json:"total"`
+	Created   int                  `json:"created"`
+	Updated   int                  `json:"updated"`
+	Unchanged int                  `json:"unchanged"`
+	Failed    int                  `json:"failed"`
+	Warnings  int                  `json:"warnings"` // questions with near-duplicates
+	Items     []QuestionImportItem `json:"items"`
+}
+
+// QuestionExport выгрузка банка в одном из форматов
+type QuestionExport struct {
+	Format      string
+	ContentType string
+	FileName    string
+	Data        []byte
+	Exported    int
+	Skipped     int // вопросы, которые формат не поддерживает
+}
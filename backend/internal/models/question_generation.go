@@ -0,0 +1,34 @@
+package models
+
+// GenerateQuestionsRequest генерация вопросов моделью (POST /questions/generate)
+type GenerateQuestionsRequest struct {
+	Competencies  []GenerationTarget `json:"competencies" binding:"required,min=1,dive"`
+	QuestionTypes []QuestionType     `json:"question_types"` // по умолчанию multiple_choice и coding
+	Model         string             `json:"model"`          // модель провайдера вместо AI_MODEL
+	Context       string             `json:"context" binding:"max=2000"`
+}
+
+// GenerationTarget сколько вопросов какой компетенции и уровня нужно
+type GenerationTarget struct {
+	Name  string          `json:"name" binding:"required,max=100"`
+	Level DifficultyLevel `json:"level" binding:"required,oneof=junior middle senior expert"`
+	Count int             `json:"count" binding:"omitempty,min=1,max=10"`
+}
+
+// GenerationFailure вопрос, который модель не смогла выдать в корректном виде
+type GenerationFailure struct {
+	Competency string          `json:"competency"`
+	Level      DifficultyLevel `json:"level"`
+	Type       QuestionType    `json:"type"`
+	Errors     []string        `json:"errors"`
+}
+
+// GenerationResult сохранённые черновики (ai_generated, на проверке) и неудачи
+type GenerationResult struct {
+	Provider         string              `json:"provider"`
+	Model            string              `json:"model"`
+	Questions        []Question          `json:"questions"`
+	Failed           []GenerationFailure `json:"failed"`
+	PromptTokens     int                 `json:"prompt_tokens"`
+	CompletionTokens int                 `json:"completion_tokens"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// QuestionSignature MinHash-сигнатура последней версии вопроса — индекс поиска похожих вопросов.
+// Одна строка на вопрос (RootID); новая версия заменяет сигнатуру.
+type QuestionSignature struct {
+	RootID     string    `gorm:"type:uuid;primaryKey" json:"root_id"`
+	QuestionID string    `gorm:"type:uuid;not null;index" json:"question_id"`
+	Signature  []uint32  `gorm:"type:jsonb;serializer:json;not null" json:"-"`
+	Shingles   int       `gorm:"not null;default:0" json:"shingles"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (QuestionSignature) TableName() string {
+	return "question_signatures"
+}
+
+// QuestionLSHBucket корзина LSH (полоса сигнатуры): вопросы из одной корзины — кандидаты в дубликаты
+type QuestionLSHBucket struct {
+	RootID string `gorm:"type:uuid;primaryKey"`
+	Bucket int64  `gorm:"primaryKey;index"`
+}
+
+func (QuestionLSHBucket) TableName() string {
+	return "question_lsh_buckets"
+}
+
+// SimilarQuestion похожий вопрос банка; Similarity — оценка коэффициента Жаккара по шинглам (0..1)
+type SimilarQuestion struct {
+	QuestionID       string           `json:"question_id"`
+	RootID           string           `json:"root_id"`
+	Title            string           `json:"title"`
+	Type             QuestionType     `json:"type"`
+	Competency       string           `json:"competency"`
+	ValidationStatus ValidationStatus `json:"validation_status"`
+	AIGenerated      bool             `json:"ai_generated"`
+	Similarity       float64          `json:"similarity"`
+}
+
+// DuplicatePair пара похожих вопросов в кластере
+type DuplicatePair struct {
+	A          string  `json:"a"` // question_id
+	B          string  `json:"b"`
+	Similarity float64 `json:"similarity"`
+}
+
+// DuplicateCluster группа вопросов, связанных парами похожести выше порога
+type DuplicateCluster struct {
+	Questions     []SimilarQuestion `json:"questions"` // Similarity — максимальная с другими вопросами кластера
+	Pairs         []DuplicatePair   `json:"pairs"`
+	MaxSimilarity float64           `json:"max_similarity"`
+}
+
+// DuplicateReport кластеры дубликатов в банке
+type DuplicateReport struct {
+	Threshold   float64            `json:"threshold"`
+	Indexed     int                `json:"indexed"`
+	Clusters    []DuplicateCluster `json:"clusters"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Действия над вопросом в процессе проверки
+const (
+	ValidationActionSubmit      = "submit"
+	ValidationActionApprove     = "approve"
+	ValidationActionReject      = "reject"
+	ValidationActionNeedsReview = "needs_review"
+)
+
+// QuestionValidation запись журнала проверки версии вопроса
+type QuestionValidation struct {
+	BaseModel
+	QuestionID    string           `gorm:"type:uuid;not null;index" json:"question_id"`
+	UserID        *string          `gorm:"type:uuid" json:"user_id"`
+	Action        string           `gorm:"type:varchar(20);not null" json:"action"`
+	FromStatus    ValidationStatus `gorm:"type:varchar(50);not null" json:"from_status"`
+	ToStatus      ValidationStatus `gorm:"type:varchar(50);not null" json:"to_status"`
+	Comment       string           `gorm:"type:text" json:"comment"`
+	SolutionCheck *SolutionCheck   `gorm:"type:jsonb;serializer:json" json:"solution_check,omitempty"`
+}
+
+// ValidateQuestionRequest решение проверяющего (POST /questions/{id}/validate)
+type ValidateQuestionRequest struct {
+	Action  string `json:"action" binding:"required,oneof=approve reject needs_review"`
+	Comment string `json:"comment"`
+}
+
+// SubmitQuestionRequest отправка на проверку
+type SubmitQuestionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// SolutionCheck прогон эталонного решения на тест-кейсах вопроса
+type SolutionCheck struct {
+	Passed    bool                 `json:"passed"`
+	Skipped   bool                 `json:"skipped"`
+	Reason    string               `json:"reason,omitempty"`
+	Language  string               `json:"language,omitempty"`
+	Cases     []SolutionCaseResult `json:"cases,omitempty"`
+	CheckedAt time.Time            `json:"checked_at"`
+}
+
+type SolutionCaseResult struct {
+	Order      int    `json:"order"`
+	Hidden     bool   `json:"hidden"`
+	Passed     bool   `json:"passed"`
+	Expected   string `json:"expected"`
+	Actual     string `json:"actual"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+)
+
+// ReviewStatus статус ручной проверки ответа
+type ReviewStatus string
+
+const (
+	ReviewStatusPending   ReviewStatus = "pending"   // ждёт назначения эксперта
+	ReviewStatusAssigned  ReviewStatus = "assigned"  // назначен эксперт
+	ReviewStatusSubmitted ReviewStatus = "submitted" // оценка выставлена
+)
+
+// ReviewKind вид проверки
+type ReviewKind string
+
+const (
+	ReviewKindReview       ReviewKind = "review"       // обычная (слепая) проверка
+	ReviewKindAdjudication ReviewKind = "adjudication" // решение при расхождении экспертов
+)
+
+// AnswerReview ручная проверка ответа техническим экспертом.
+// На один ответ может приходиться несколько проверок (Assessment.ReviewersPerAnswer).
+type AnswerReview struct {
+	BaseModel
+	Kind        ReviewKind   `gorm:"type:varchar(20);not null;default:'review'" json:"kind"`
+	AnswerID    string       `gorm:"type:uuid;not null;index" json:"answer_id"`
+	SessionID   string       `gorm:"type:uuid;not null;index" json:"session_id"`
+	QuestionID  string       `gorm:"type:uuid;not null;index" json:"question_id"`
+	ReviewerID  *string      `gorm:"type:uuid;index" json:"reviewer_id"`
+	Status      ReviewStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Score       *float64     `json:"score"`
+	MaxScore    float64      `gorm:"not null" json:"max_score"`
+	Comment     string       `gorm:"type:text" json:"comment"`
+	AssignedAt  *time.Time   `gorm:"type:timestamp" json:"assigned_at"`
+	SubmittedAt *time.Time   `gorm:"type:timestamp" json:"submitted_at"`
+
+	// Per-criterion scores when the question has a rubric
+	CriterionScores []CriterionScore `gorm:"type:jsonb;serializer:json" json:"criterion_scores"`
+
+	// AI suggestion the expert saw when submitting and whether it was accepted or overridden
+	AISuggestionID *string    `gorm:"type:uuid" json:"ai_suggestion_id,omitempty"`
+	AIDecision     AIDecision `gorm:"type:varchar(20)" json:"ai_decision,omitempty"`
+}
+
+// ReviewDetail проверка вместе с вопросом и ответом кандидата.
+// Peers (оценки других экспертов) скрыты, пока эксперт не выставил свою оценку.
+type ReviewDetail struct {
+	Review   AnswerReview    `json:"review"`
+	Question Question        `json:"question"`
+	Answer   CandidateAnswer `json:"answer"`
+	Peers    []AnswerReview  `json:"peers,omitempty"`
+
+	AISuggestion *AIGradeSuggestion `json:"ai_suggestion,omitempty"`
+}
+
+// AssignReviewRequest назначение эксперта на проверку
+type AssignReviewRequest struct {
+	ReviewerID string `json:"reviewer_id" binding:"required"`
+}
+
+// SubmitReviewRequest оценка эксперта.
+// Если у вопроса есть рубрика, нужны баллы по каждому критерию (criteria), score тогда вычисляется.
+type SubmitReviewRequest struct {
+	Score    float64               `json:"score" binding:"min=0"`
+	Comment  string                `json:"comment"`
+	Criteria []CriterionScoreInput `json:"criteria" binding:"dive"`
+}
+
+// AgreementStats согласованность оценок экспертов.
+// Kappa — Cohen's kappa каждой пары экспертов (оценки разбиты на 4 диапазона, маргиналы свои
+// у каждого эксперта), средняя с весом по числу общих ответов пары.
+// Variance и MeanAbsDiff считаются по нормированным (0..1) оценкам.
+type AgreementStats struct {
+	Answers     int     `json:"answers"`
+	Pairs       int     `json:"pairs"`
+	Kappa       float64 `json:"kappa"`
+	Variance    float64 `json:"variance"`
+	MeanAbsDiff float64 `json:"mean_abs_diff"`
+}
+
+// ReviewerAgreement согласованность эксперта с коллегами
+type ReviewerAgreement struct {
+	ReviewerID string `json:"reviewer_id"`
+	AgreementStats
+	Bias float64 `json:"bias"` // средняя разница с оценками коллег (>0 — завышает)
+}
+
+// QuestionAgreement согласованность экспертов по вопросу
+type QuestionAgreement struct {
+	QuestionID string `json:"question_id"`
+	AgreementStats
+	Adjudicated int `json:"adjudicated"`
+}
+
+// AgreementReport отчёт по калибровке экспертов
+type AgreementReport struct {
+	Overall   AgreementStats      `json:"overall"`
+	Reviewers []ReviewerAgreement `json:"reviewers"`
+	Questions []QuestionAgreement `json:"questions"`
+}
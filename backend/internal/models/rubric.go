@@ -0,0 +1,56 @@
+package models
+
+// Источники баллов по критериям
+const (
+	ScoreSourceAuto   = "auto"
+	ScoreSourceReview = "review"
+	ScoreSourceAI     = "ai"
+)
+
+// RubricCriterion критерий оценки ответа на вопрос
+type RubricCriterion struct {
+	BaseModel
+	QuestionID  string         `gorm:"type:uuid;not null;index" json:"question_id"`
+	Name        string         `gorm:"type:varchar(255);not null" json:"name"`
+	Description string         `gorm:"type:text" json:"description"`
+	MaxPoints   float64        `gorm:"not null" json:"max_points"`
+	Order       int            `gorm:"not null" json:"order"`
+	Anchors     []RubricAnchor `gorm:"type:jsonb;serializer:json" json:"anchors"`
+}
+
+// RubricAnchor описание того, за что ставится конкретный балл по критерию
+type RubricAnchor struct {
+	Points      float64 `json:"points"`
+	Label       string  `json:"label"`
+	Description string  `json:"description"`
+}
+
+// CriterionScore балл ответа по одному критерию
+type CriterionScore struct {
+	CriterionID string  `json:"criterion_id"`
+	Name        string  `json:"name"`
+	Points      float64 `json:"points"`
+	MaxPoints   float64 `json:"max_points"`
+	Comment     string  `json:"comment,omitempty"`
+	Source      string  `json:"source"`
+}
+
+// RubricRequest полная замена рубрики вопроса
+type RubricRequest struct {
+	Criteria []RubricCriterionInput `json:"criteria" binding:"dive"`
+}
+
+// RubricCriterionInput критерий во входящем запросе
+type RubricCriterionInput struct {
+	Name        string         `json:"name" binding:"required,max=255"`
+	Description string         `json:"description"`
+	MaxPoints   float64        `json:"max_points" binding:"gt=0"`
+	Anchors     []RubricAnchor `json:"anchors"`
+}
+
+// CriterionScoreInput балл эксперта по критерию
+type CriterionScoreInput struct {
+	CriterionID string  `json:"criterion_id" binding:"required"`
+	Points      float64 `json:"points" binding:"min=0"`
+	Comment     string  `json:"comment"`
+}
@@ -0,0 +1,227 @@
+package models
+
+import "encoding/json"
+
+// LevelWeight вес уровня сложности (Fibonacci), таблица level_weights
+type LevelWeight struct {
+	Level  string `gorm:"type:varchar(20);primaryKey" json:"level"`
+	Weight int    `gorm:"not null" json:"weight"`
+}
+
+func (LevelWeight) TableName() string {
+	return "level_weights"
+}
+
+// LevelThreshold порог уровня в баллах (Fibonacci), таблица level_thresholds
+type LevelThreshold struct {
+	Level     string `gorm:"type:varchar(20);primaryKey" json:"level"`
+	Threshold int    `gorm:"not null" json:"threshold"`
+}
+
+func (LevelThreshold) TableName() string {
+	return "level_thresholds"
+}
+
+// ScoringConfig формула оценки (см. docs/assessment-framework.md)
+type ScoringConfig struct {
+	LevelWeights            map[string]int     `json:"level_weights"`
+	CompetencyWeights       map[string]float64 `json:"competency_weights"`
+	DefaultCompetencyWeight float64            `json:"default_competency_weight"` // для компетенций вне списка
+	LevelThresholds         map[string]int     `json:"level_thresholds"`          // баллы, level_thresholds
+	LevelPercentages        map[string]float64 `json:"level_percentages"`         // минимальный процент для уровня
+	TimeBonuses             TimeBonusConfig    `json:"time_bonuses"`
+	AntiFarming             AntiFarmingConfig  `json:"anti_farming"`
+	AbilityLevels           AbilityLevelConfig `json:"ability_levels"`
+}
+
+// UnmarshalJSON ключи, появившиеся в формуле позже, в старых версиях не сохранены —
+// для них берутся значения по умолчанию (сохранённые версии не переписываются)
+func (c *ScoringConfig) UnmarshalJSON(data []byte) error {
+	type plain ScoringConfig
+	defaults := DefaultScoringConfig()
+	v := plain{
+		TimeBonuses: TimeBonusConfig{
+			LateGraceSeconds: defaults.TimeBonuses.LateGraceSeconds,
+			LateMultiplier:   defaults.TimeBonuses.LateMultiplier,
+			ZeroCreditRatio:  defaults.TimeBonuses.ZeroCreditRatio,
+		},
+		AbilityLevels: defaults.AbilityLevels,
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = ScoringConfig(v)
+	return nil
+}
+
+// TimeBonusConfig бонусы считаются от Question.TimeLimit (BaselineSeconds — если лимит не задан).
+// Ответ позже лимита (+LateGraceSeconds) получает LateMultiplier, позже ZeroCreditRatio×лимит — ноль.
+type TimeBonusConfig struct {
+	VeryFastRatio    float64 `json:"very_fast_ratio"`    // < 0.3
+	FastRatio        float64 `json:"fast_ratio"`         // < 0.7
+	VeryFastBonus    float64 `json:"very_fast_bonus"`    // 1.2
+	FastBonus        float64 `json:"fast_bonus"`         // 1.1
+	BaselineSeconds  int     `json:"baseline_seconds"`   // 300
+	LateGraceSeconds int     `json:"late_grace_seconds"` // 5
+	LateMultiplier   float64 `json:"late_multiplier"`    // 0.5
+	ZeroCreditRatio  float64 `json:"zero_credit_ratio"`  // 1.5
+}
+
+type AntiFarmingConfig struct {
+	MaxJuniorScorePerDay int     `json:"max_junior_score_per_day"`
+	MaxSameLevelRatio    float64 `json:"max_same_level_ratio"`
+}
+
+// AbilityLevelConfig уровень по оценке способности θ (2PL IRT) вместо процента.
+// Применяется, только если среди ответов не меньше MinCalibratedItems откалиброванных вопросов.
+type AbilityLevelConfig struct {
+	Enabled            bool               `json:"enabled"`
+	Thresholds         map[string]float64 `json:"thresholds"` // минимальная θ для уровня
+	MinCalibratedItems int                `json:"min_calibrated_items"`
+}
+
+// ScoringHistory что кандидат уже набрал в других сессиях (для anti-farming)
+type ScoringHistory struct {
+	JuniorScoreToday float64 `json:"junior_score_today"`
+}
+
+const (
+	AntiFarmingRuleJuniorDailyCap = "junior_daily_cap"
+	AntiFarmingRuleSameLevelRatio = "same_level_ratio"
+)
+
+// AntiFarmingFlag сработавшее ограничение anti-farming
+type AntiFarmingFlag struct {
+	Rule     string  `json:"rule"`
+	Level    string  `json:"level"`
+	Original float64 `json:"original"` // баллы уровня до ограничения
+	Capped   float64 `json:"capped"`   // после
+	Limit    float64 `json:"limit"`    // значение из AntiFarmingConfig
+	Message  string  `json:"message"`
+}
+
+// LevelCheck проверка требований одного уровня
+type LevelCheck struct {
+	Level              string   `json:"level"`
+	Reached            bool     `json:"reached"`
+	RequiredPercentage float64  `json:"required_percentage"`
+	PercentageMet      bool     `json:"percentage_met"`
+	RequiredPoints     int      `json:"required_points"` // level_thresholds
+	PointsMet          bool     `json:"points_met"`
+	PointsApplicable   bool     `json:"points_applicable"` // false, если столько баллов в оценке не набрать
+	SolvedAtOrAbove    int      `json:"solved_at_or_above"`
+	SolvedMet          bool     `json:"solved_met"`
+	RequiredAbility    *float64 `json:"required_ability,omitempty"` // только для Method == "ability"
+	AbilityMet         bool     `json:"ability_met"`
+	Reason             string   `json:"reason"`
+}
+
+// LevelExplanation почему кандидату присвоен уровень (и почему не следующий)
+type LevelExplanation struct {
+	Level          string         `json:"level"`
+	Percentage     float64        `json:"percentage"`
+	TotalScore     float64        `json:"total_score"`
+	MaxPossible    float64        `json:"max_possible"`
+	SolvedByLevel  map[string]int `json:"solved_by_level"`
+	MaxSolvedLevel string         `json:"max_solved_level"`
+	Checks         []LevelCheck   `json:"checks"` // от expert к junior
+	Summary        string         `json:"summary"`
+
+	// Method "percentage" или "ability" (AbilityLevelConfig)
+	Method  string           `json:"method"`
+	Ability *AbilityEstimate `json:"ability,omitempty"`
+}
+
+// ScoringConfigVersion сохранённая версия формулы. Версии не редактируются:
+// изменение — это новая версия, активной может быть только одна.
+type ScoringConfigVersion struct {
+	BaseModel
+	Version   int           `gorm:"not null;uniqueIndex" json:"version"`
+	Config    ScoringConfig `gorm:"type:jsonb;serializer:json;not null" json:"config"`
+	IsActive  bool          `gorm:"not null;default:false;index" json:"is_active"`
+	Comment   string        `gorm:"type:text" json:"comment"`
+	CreatedBy *string       `gorm:"type:uuid" json:"created_by"`
+}
+
+func (ScoringConfigVersion) TableName() string {
+	return "scoring_configs"
+}
+
+// ScoringConfigRequest новая версия формулы
+type ScoringConfigRequest struct {
+	Config   ScoringConfig `json:"config" binding:"required"`
+	Comment  string        `json:"comment"`
+	Activate bool          `json:"activate"`
+}
+
+// DefaultScoringConfig формула из docs/assessment-framework.md — используется,
+// пока в БД нет ни одной версии
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		LevelWeights: map[string]int{
+			"junior": 1,
+			"middle": 2,
+			"senior": 3,
+			"expert": 5,
+		},
+		CompetencyWeights: map[string]float64{
+			"go_fundamentals":    1.0,
+			"data_structures_go": 1.1,
+			"memory_management":  1.1,
+			"concurrency":        1.3,
+			"http_go":            1.0,
+			"system_design":      1.3,
+			"microservices":      1.2,
+			"containerization":   1.1,
+			"reliability":        1.2,
+			"message_brokers":    1.2,
+			"software_design":    1.2,
+			"architecture":       1.3,
+			"quality_assurance":  1.1,
+			"optimization":       1.1,
+			"sdlc":               1.0,
+			"requirements":       1.0,
+			"ci_cd":              1.1,
+			"git":                1.0,
+			"web_security":       1.2,
+			"data_security":      1.3,
+		},
+		DefaultCompetencyWeight: 1.0,
+		LevelThresholds: map[string]int{
+			"junior": 8,
+			"middle": 21,
+			"senior": 55,
+			"expert": 144,
+		},
+		LevelPercentages: map[string]float64{
+			"junior": 40,
+			"middle": 55,
+			"senior": 70,
+			"expert": 85,
+		},
+		TimeBonuses: TimeBonusConfig{
+			VeryFastRatio:    0.3,
+			FastRatio:        0.7,
+			VeryFastBonus:    1.2,
+			FastBonus:        1.1,
+			BaselineSeconds:  300,
+			LateGraceSeconds: 5,
+			LateMultiplier:   0.5,
+			ZeroCreditRatio:  1.5,
+		},
+		AntiFarming: AntiFarmingConfig{
+			MaxJuniorScorePerDay: 20,
+			MaxSameLevelRatio:    0.5,
+		},
+		AbilityLevels: AbilityLevelConfig{
+			Enabled: false,
+			Thresholds: map[string]float64{
+				"junior": -1.0,
+				"middle": 0.0,
+				"senior": 1.0,
+				"expert": 2.0,
+			},
+			MinCalibratedItems: 5,
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// ResultScore процент результата в одном разрезе: вся оценка (Competency == "") или компетенция.
+// По этим строкам считаются ранг и перцентиль.
+type ResultScore struct {
+	BaseModel
+	ResultID     string  `gorm:"type:uuid;not null;index" json:"result_id"`
+	AssessmentID string  `gorm:"type:uuid;not null;index:idx_result_scores_scope" json:"assessment_id"`
+	Competency   string  `gorm:"type:varchar(100);not null;default:'';index:idx_result_scores_scope" json:"competency"`
+	Percentage   float64 `gorm:"not null;index:idx_result_scores_scope" json:"percentage"`
+}
+
+// ScoreDistribution накопленная статистика разреза. Обновляется на каждом новом результате
+// (алгоритм Уэлфорда), без пересчёта по всем результатам.
+type ScoreDistribution struct {
+	AssessmentID string    `gorm:"type:uuid;primaryKey" json:"assessment_id"`
+	Competency   string    `gorm:"type:varchar(100);primaryKey" json:"competency"`
+	Count        int64     `gorm:"not null;default:0" json:"count"`
+	Mean         float64   `gorm:"not null;default:0" json:"mean"`
+	M2           float64   `gorm:"not null;default:0" json:"-"`
+	Min          float64   `gorm:"not null;default:0" json:"min"`
+	Max          float64   `gorm:"not null;default:0" json:"max"`
+	StdDevValue  float64   `gorm:"-" json:"std_dev"`
+	UpdatedAt    time.Time `gorm:"type:timestamp" json:"updated_at"`
+}
+
+// Add учитывает новое значение
+func (d *ScoreDistribution) Add(x float64) {
+	if d.Count == 0 || x < d.Min {
+		d.Min = x
+	}
+	if d.Count == 0 || x > d.Max {
+		d.Max = x
+	}
+	d.Count++
+	delta := x - d.Mean
+	d.Mean += delta / float64(d.Count)
+	d.M2 += delta * (x - d.Mean)
+}
+
+// StdDev стандартное отклонение (по генеральной совокупности)
+func (d ScoreDistribution) StdDev() float64 {
+	if d.Count < 2 {
+		return 0
+	}
+	return math.Sqrt(d.M2 / float64(d.Count))
+}
+
+// ScoreStanding положение результата среди всех завершённых сессий оценки
+type ScoreStanding struct {
+	Competency string  `json:"competency,omitempty"`
+	Percentage float64 `json:"percentage"`
+	Percentile float64 `json:"percentile"`
+	ZScore     float64 `json:"z_score"`
+	Rank       int64   `json:"rank"`
+	Total      int64   `json:"total"`
+	Mean       float64 `json:"mean"`
+	StdDev     float64 `json:"std_dev"`
+}
+
+// ResultStanding нормализованный результат: вся оценка и каждая компетенция
+type ResultStanding struct {
+	ResultID     string          `json:"result_id"`
+	AssessmentID string          `json:"assessment_id"`
+	Overall      ScoreStanding   `json:"overall"`
+	Competencies []ScoreStanding `json:"competencies"`
+}
+
+// ScoreCounts сколько результатов разреза выше/ниже заданного
+type ScoreCounts struct {
+	Above int64
+	Below int64
+	Total int64
+}
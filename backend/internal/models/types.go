@@ -23,10 +23,11 @@ const (
 type SessionStatus string
 
 const (
-    SessionStatusPending     SessionStatus = "pending"
-    SessionStatusInProgress  SessionStatus = "in_progress"
-    SessionStatusCompleted   SessionStatus = "completed"
-    SessionStatusExpired     SessionStatus = "expired"
+    SessionStatusPending       SessionStatus = "pending"
+    SessionStatusInProgress    SessionStatus = "in_progress"
+    SessionStatusPendingReview SessionStatus = "pending_review"
+    SessionStatusCompleted     SessionStatus = "completed"
+    SessionStatusExpired       SessionStatus = "expired"
 )
 
 // AssessmentType тип оценки
@@ -46,6 +47,7 @@ const (
     InvitationStatusSent     InvitationStatus = "sent"
     InvitationStatusOpened   InvitationStatus = "opened"
     InvitationStatusAccepted InvitationStatus = "accepted"
+    InvitationStatusCompleted InvitationStatus = "completed"
     InvitationStatusExpired  InvitationStatus = "expired"
 )
 
@@ -58,3 +60,14 @@ const (
     QuestionTypeArchitecture  QuestionType = "architecture"
     QuestionTypeDebugging     QuestionType = "debugging"
 )
+
+// ValidationStatus статус проверки вопроса
+type ValidationStatus string
+
+const (
+    ValidationStatusDraft       ValidationStatus = "draft"
+    ValidationStatusPending     ValidationStatus = "pending" // отправлен на проверку
+    ValidationStatusApproved    ValidationStatus = "approved"
+    ValidationStatusRejected    ValidationStatus = "rejected"
+    ValidationStatusNeedsReview ValidationStatus = "needs_review" // возвращён автору на доработку
+)
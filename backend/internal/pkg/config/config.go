@@ -68,6 +68,7 @@ type AIConfig struct {
 	Model       string  `mapstructure:"model"`
 	Temperature float64 `mapstructure:"temperature"`
 	MaxTokens   int     `mapstructure:"max_tokens"`
+	BaseURL     string  `mapstructure:"base_url"` // переопределяет адрес API провайдера
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -157,8 +158,9 @@ func loadEnvConfig(path string) (*Config, error) {
 			Provider:    getEnv(envMap, "AI_PROVIDER", "gemini"),
 			APIKey:      getEnv(envMap, "AI_API_KEY", ""),
 			Model:       getEnv(envMap, "AI_MODEL", "gemini-pro"),
-			Temperature: 0.7,
-			MaxTokens:   1000,
+			Temperature: getEnvFloat(envMap, "AI_TEMPERATURE", 0.7),
+			MaxTokens:   getEnvInt(envMap, "AI_MAX_TOKENS", 1000),
+			BaseURL:     getEnv(envMap, "AI_BASE_URL", ""),
 		},
 	}
 	
@@ -186,6 +188,20 @@ func getEnvInt(envMap map[string]string, key string, defaultValue int) int {
 	return result
 }
 
+func getEnvFloat(envMap map[string]string, key string, defaultValue float64) float64 {
+	val := getEnv(envMap, key, "")
+	if val == "" {
+		return defaultValue
+	}
+	
+	var result float64
+	_, err := fmt.Sscanf(val, "%g", &result)
+	if err != nil {
+		return defaultValue
+	}
+	return result
+}
+
 func setDefaults() {
 	viper.SetDefault("server.host", "localhost")
 	viper.SetDefault("server.port", 8080)
@@ -2,40 +2,82 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/easyhire/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AssessmentFilter struct {
-	ID        string
-	CreatedBy string
-	Status    string
-	Search    string
-	Limit     int
-	Offset    int
+	ID          string
+	CreatedBy   string
+	Status      string
+	Search      string
+	Tags        []string // хотя бы один из тегов
+	TargetLevel string
+	CreatedFrom *time.Time // created_at >= CreatedFrom
+	CreatedTo   *time.Time // created_at < CreatedTo
+	Sort        string     // поле из AssessmentSortFields, "-" в начале — по убыванию; по умолчанию -created_at
+	Cursor      string     // next_cursor предыдущей страницы; Offset при нём не учитывается
+	Limit       int
+	Offset      int
 }
 
+// ErrAssessmentStatusConflict статус оценки успели изменить параллельно
+var ErrAssessmentStatusConflict = errors.New("assessment status has changed")
+
+// ErrSessionStatusConflict статус сессии успели изменить параллельно
+var ErrSessionStatusConflict = errors.New("session status has changed")
+
+var (
+	ErrInvalidAssessmentSort   = errors.New("invalid assessment sort")
+	ErrInvalidAssessmentCursor = errors.New("invalid assessment cursor")
+)
+
+// AssessmentSortFields поля сортировки списка оценок
+var AssessmentSortFields = []string{"created_at", "updated_at", "title", "time_limit"}
+
+const defaultAssessmentSort = "-created_at"
+
 type AssessmentRepository interface {
 	// Assessment CRUD
 	CreateAssessment(ctx context.Context, assessment *models.Assessment) error
 	GetAssessmentByID(ctx context.Context, id string) (*models.Assessment, error)
 	GetAssessmentWithQuestions(ctx context.Context, id string) (*models.Assessment, error)
 	UpdateAssessment(ctx context.Context, assessment *models.Assessment) error
+	ChangeAssessmentStatus(ctx context.Context, assessment *models.Assessment, from models.AssessmentStatus) error
 	DeleteAssessment(ctx context.Context, id string) error
-	ListAssessments(ctx context.Context, filter AssessmentFilter) ([]models.Assessment, int64, error)
+	ListAssessments(ctx context.Context, filter AssessmentFilter) ([]models.Assessment, int64, string, error)
+	SetAssessmentTags(ctx context.Context, assessmentID string, names []string) error
 
 	// Competencies
 	CreateAssessmentCompetency(ctx context.Context, c *models.AssessmentCompetency) error
 
+	// Templates
+	ListTemplates(ctx context.Context) ([]models.AssessmentTemplate, error)
+	GetTemplateByID(ctx context.Context, id string) (*models.AssessmentTemplate, error)
+	GetTemplateByKey(ctx context.Context, key string) (*models.AssessmentTemplate, error)
+	CreateTemplate(ctx context.Context, template *models.AssessmentTemplate) error
+	UpdateTemplate(ctx context.Context, template *models.AssessmentTemplate) error
+	DeleteTemplate(ctx context.Context, id string) error
+
 	// Users (for invitations)
 	GetUserIDByEmail(ctx context.Context, email string) (string, error)
 
 	// Sessions
 	CreateSession(ctx context.Context, session *models.AssessmentSession) error
+	WithAttemptLock(ctx context.Context, assessmentID, candidateID string, fn func(repo AssessmentRepository) error) error
+	WithSessionLock(ctx context.Context, sessionID string, fn func(repo AssessmentRepository, session *models.AssessmentSession) error) error
+	ChangeSessionStatus(ctx context.Context, sessionID string, from, to models.SessionStatus) error
 	GetSessionByID(ctx context.Context, sessionID string) (*models.AssessmentSession, error)
 	GetActiveSession(ctx context.Context, assessmentID, candidateID string) (*models.AssessmentSession, error)
+	ListCandidateSessions(ctx context.Context, assessmentID, candidateID string) ([]models.AssessmentSession, error)
 	UpdateSession(ctx context.Context, session *models.AssessmentSession) error
 	GetSessionAnswers(ctx context.Context, sessionID string) ([]models.CandidateAnswer, error)
 
@@ -44,16 +86,28 @@ type AssessmentRepository interface {
 	GetAnswer(ctx context.Context, sessionID, questionID string) (*models.CandidateAnswer, error)
 	UpdateAnswer(ctx context.Context, answer *models.CandidateAnswer) error
 
+	// Edit history (replay)
+	SaveEditBatch(ctx context.Context, batch *models.AnswerEditBatch) error
+	GetEditBatches(ctx context.Context, sessionID, questionID string) ([]models.AnswerEditBatch, error)
+
 	// Results
 	CreateResult(ctx context.Context, result *models.Result) error
 	GetResultBySessionID(ctx context.Context, sessionID string) (*models.Result, error)
+	GetResultByID(ctx context.Context, id string) (*models.Result, error)
+	UpdateResult(ctx context.Context, result *models.Result) error
+	SumCandidateLevelScore(ctx context.Context, candidateID, level string, from, to time.Time) (float64, error)
+
+	// Competencies
+	GetCompetencies(ctx context.Context, names []string) ([]models.Competency, error)
 
 	// Invitations
 	CreateInvitation(ctx context.Context, invitation *models.Invitation) error
 	BulkCreateInvitations(ctx context.Context, invitations []models.Invitation) error
 	GetInvitationByToken(ctx context.Context, token string) (*models.Invitation, error)
+	GetInvitationByID(ctx context.Context, id string) (*models.Invitation, error)
 	UpdateInvitation(ctx context.Context, invitation *models.Invitation) error
 	GetInvitationsByAssessment(ctx context.Context, assessmentID string) ([]models.Invitation, error)
+	GetCandidateInvitation(ctx context.Context, assessmentID, candidateID string) (*models.Invitation, error)
 }
 
 type assessmentRepository struct {
@@ -103,15 +157,44 @@ func (r *assessmentRepository) UpdateAssessment(ctx context.Context, assessment
 	return r.db.WithContext(ctx).Save(assessment).Error
 }
 
+// ChangeAssessmentStatus сохраняет статус и даты жизненного цикла, если статус в базе всё ещё from
+func (r *assessmentRepository) ChangeAssessmentStatus(ctx context.Context, assessment *models.Assessment, from models.AssessmentStatus) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Assessment{}).
+		Where("id = ? AND status = ?", assessment.ID, from).
+		Updates(map[string]interface{}{
+			"status":       assessment.Status,
+			"published_at": assessment.PublishedAt,
+			"archived_at":  assessment.ArchivedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAssessmentStatusConflict
+	}
+	return nil
+}
+
 func (r *assessmentRepository) DeleteAssessment(ctx context.Context, id string) error {
 	// if BaseModel has gorm.DeletedAt + deleted_at exists -> soft delete works
 	return r.db.WithContext(ctx).Delete(&models.Assessment{}, "id = ?", id).Error
 }
 
-func (r *assessmentRepository) ListAssessments(ctx context.Context, filter AssessmentFilter) ([]models.Assessment, int64, error) {
+// ListAssessments страница оценок, общее число подходящих под фильтр и курсор следующей страницы.
+// Страницы по курсору берутся по ключу (поле сортировки, id), поэтому не съезжают при вставках.
+func (r *assessmentRepository) ListAssessments(ctx context.Context, filter AssessmentFilter) ([]models.Assessment, int64, string, error) {
 	var assessments []models.Assessment
 	var total int64
 
+	if filter.Sort == "" {
+		filter.Sort = defaultAssessmentSort
+	}
+	column, desc, err := parseAssessmentSort(filter.Sort)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
 	query := r.db.WithContext(ctx).Model(&models.Assessment{})
 
 	if filter.ID != "" {
@@ -123,32 +206,169 @@ func (r *assessmentRepository) ListAssessments(ctx context.Context, filter Asses
 	if filter.Status != "" {
 		query = query.Where("status = ?", filter.Status)
 	}
+	if filter.TargetLevel != "" {
+		query = query.Where("target_level = ?", filter.TargetLevel)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if tags := tagNames(filter.Tags); len(tags) > 0 {
+		query = query.Where(
+			"id IN (SELECT ata.assessment_id FROM assessment_tag_associations ata "+
+				"JOIN assessment_tags t ON t.id = ata.tag_id WHERE t.name IN ?)",
+			tags,
+		)
+	}
 	if filter.Search != "" {
 		query = query.Where(
-			"title ILIKE ? OR description ILIKE ?",
+			"(title ILIKE ? OR description ILIKE ?)",
 			"%"+filter.Search+"%",
 			"%"+filter.Search+"%",
 		)
 	}
 
 	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
-	}
-	if filter.Offset > 0 {
+	if filter.Cursor != "" {
+		value, id, err := decodeAssessmentCursor(filter.Cursor, filter.Sort, column)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		query = query.Where("("+column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?))", value, value, id)
+	} else if filter.Offset > 0 {
 		query = query.Offset(filter.Offset)
 	}
+	if filter.Limit > 0 {
+		// лишняя строка — признак следующей страницы
+		query = query.Limit(filter.Limit + 1)
+	}
 
-	err := query.
+	direction := " ASC"
+	if desc {
+		direction = " DESC"
+	}
+	err = query.
 		Preload("Competencies").
-		Order("created_at DESC").
+		Preload("Tags").
+		Order(column + direction).
+		Order("id" + direction).
 		Find(&assessments).
 		Error
+	if err != nil {
+		return nil, 0, "", err
+	}
 
-	return assessments, total, err
+	next := ""
+	if filter.Limit > 0 && len(assessments) > filter.Limit {
+		assessments = assessments[:filter.Limit]
+		next = encodeAssessmentCursor(&assessments[len(assessments)-1], filter.Sort, column)
+	}
+	return assessments, total, next, nil
+}
+
+// parseAssessmentSort колонка и направление сортировки
+func parseAssessmentSort(sort string) (string, bool, error) {
+	column := strings.TrimPrefix(sort, "-")
+	for _, field := range AssessmentSortFields {
+		if field == column {
+			return column, strings.HasPrefix(sort, "-"), nil
+		}
+	}
+	return "", false, ErrInvalidAssessmentSort
+}
+
+// assessmentCursor позиция последней оценки страницы; сортировка сохраняется, чтобы курсор
+// нельзя было применить к списку с другим порядком
+type assessmentCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func encodeAssessmentCursor(a *models.Assessment, sort, column string) string {
+	var value string
+	switch column {
+	case "created_at":
+		value = a.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		value = a.UpdatedAt.Format(time.RFC3339Nano)
+	case "title":
+		value = a.Title
+	case "time_limit":
+		value = strconv.Itoa(a.TimeLimit)
+	}
+	raw, _ := json.Marshal(assessmentCursor{Sort: sort, Value: value, ID: a.ID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeAssessmentCursor значение поля сортировки и id из курсора
+func decodeAssessmentCursor(cursor, sort, column string) (interface{}, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidAssessmentCursor
+	}
+	var c assessmentCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" || c.Sort != sort {
+		return nil, "", ErrInvalidAssessmentCursor
+	}
+	switch column {
+	case "created_at", "updated_at":
+		t, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, "", ErrInvalidAssessmentCursor
+		}
+		return t, c.ID, nil
+	case "time_limit":
+		n, err := strconv.Atoi(c.Value)
+		if err != nil {
+			return nil, "", ErrInvalidAssessmentCursor
+		}
+		return n, c.ID, nil
+	default:
+		return c.Value, c.ID, nil
+	}
+}
+
+// =====================
+// Tags
+// =====================
+
+// SetAssessmentTags заменяет теги оценки; отсутствующие в справочнике теги создаются
+func (r *assessmentRepository) SetAssessmentTags(ctx context.Context, assessmentID string, names []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM assessment_tag_associations WHERE assessment_id = ?", assessmentID).Error; err != nil {
+			return err
+		}
+		for _, name := range tagNames(names) {
+			tag := models.AssessmentTag{Name: name}
+			if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+				Create(&tag).Error; err != nil {
+				return err
+			}
+			if tag.ID == "" {
+				// тег уже был в справочнике
+				if err := tx.Where("name = ?", name).First(&tag).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec(
+				"INSERT INTO assessment_tag_associations (assessment_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+				assessmentID, tag.ID,
+			).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // =====================
@@ -159,6 +379,55 @@ func (r *assessmentRepository) CreateAssessmentCompetency(ctx context.Context, c
 	return r.db.WithContext(ctx).Create(c).Error
 }
 
+// =====================
+// Templates
+// =====================
+
+// ListTemplates встроенные шаблоны первыми, затем по названию
+func (r *assessmentRepository) ListTemplates(ctx context.Context) ([]models.AssessmentTemplate, error) {
+	var templates []models.AssessmentTemplate
+	err := r.db.WithContext(ctx).
+		Order("is_builtin DESC, name ASC").
+		Find(&templates).
+		Error
+	return templates, err
+}
+
+func (r *assessmentRepository) GetTemplateByID(ctx context.Context, id string) (*models.AssessmentTemplate, error) {
+	var template models.AssessmentTemplate
+	if err := r.db.WithContext(ctx).First(&template, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *assessmentRepository) GetTemplateByKey(ctx context.Context, key string) (*models.AssessmentTemplate, error) {
+	var template models.AssessmentTemplate
+	if err := r.db.WithContext(ctx).First(&template, "key = ?", key).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *assessmentRepository) CreateTemplate(ctx context.Context, template *models.AssessmentTemplate) error {
+	return r.db.WithContext(ctx).Create(template).Error
+}
+
+func (r *assessmentRepository) UpdateTemplate(ctx context.Context, template *models.AssessmentTemplate) error {
+	return r.db.WithContext(ctx).Save(template).Error
+}
+
+func (r *assessmentRepository) DeleteTemplate(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&models.AssessmentTemplate{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 // =====================
 // Users (for invitations)
 // =====================
@@ -189,6 +458,44 @@ func (r *assessmentRepository) CreateSession(ctx context.Context, session *model
 	return r.db.WithContext(ctx).Create(session).Error
 }
 
+// WithAttemptLock выполняет fn в транзакции под advisory-блокировкой пары (оценка, кандидат):
+// параллельные старты попыток одного кандидата проверяются и создаются по очереди
+func (r *assessmentRepository) WithAttemptLock(ctx context.Context, assessmentID, candidateID string, fn func(repo AssessmentRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", assessmentID+"/"+candidateID).Error; err != nil {
+			return err
+		}
+		return fn(NewAssessmentRepository(tx))
+	})
+}
+
+// WithSessionLock выполняет fn в транзакции, заблокировав строку сессии (SELECT … FOR UPDATE):
+// параллельные подсчёты результата одной сессии идут по очереди
+func (r *assessmentRepository) WithSessionLock(ctx context.Context, sessionID string, fn func(repo AssessmentRepository, session *models.AssessmentSession) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var session models.AssessmentSession
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, "id = ?", sessionID).Error; err != nil {
+			return err
+		}
+		return fn(NewAssessmentRepository(tx), &session)
+	})
+}
+
+// ChangeSessionStatus from → to одним UPDATE; ErrSessionStatusConflict, если сессия уже не в статусе from
+func (r *assessmentRepository) ChangeSessionStatus(ctx context.Context, sessionID string, from, to models.SessionStatus) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.AssessmentSession{}).
+		Where("id = ? AND status = ?", sessionID, from).
+		Update("status", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionStatusConflict
+	}
+	return nil
+}
+
 func (r *assessmentRepository) GetSessionByID(ctx context.Context, sessionID string) (*models.AssessmentSession, error) {
 	var session models.AssessmentSession
 	err := r.db.WithContext(ctx).
@@ -219,6 +526,17 @@ func (r *assessmentRepository) GetActiveSession(ctx context.Context, assessmentI
 	return &session, nil
 }
 
+// ListCandidateSessions попытки кандидата по оценке, последние первыми
+func (r *assessmentRepository) ListCandidateSessions(ctx context.Context, assessmentID, candidateID string) ([]models.AssessmentSession, error) {
+	var sessions []models.AssessmentSession
+	err := r.db.WithContext(ctx).
+		Where("assessment_id = ? AND candidate_id = ?", assessmentID, candidateID).
+		Order("created_at DESC").
+		Find(&sessions).
+		Error
+	return sessions, err
+}
+
 func (r *assessmentRepository) UpdateSession(ctx context.Context, session *models.AssessmentSession) error {
 	return r.db.WithContext(ctx).Save(session).Error
 }
@@ -256,6 +574,28 @@ func (r *assessmentRepository) UpdateAnswer(ctx context.Context, answer *models.
 	return r.db.WithContext(ctx).Save(answer).Error
 }
 
+// =====================
+// Edit history
+// =====================
+
+func (r *assessmentRepository) SaveEditBatch(ctx context.Context, batch *models.AnswerEditBatch) error {
+	// повторная отправка той же пачки (тот же seq) игнорируется
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(batch).
+		Error
+}
+
+func (r *assessmentRepository) GetEditBatches(ctx context.Context, sessionID, questionID string) ([]models.AnswerEditBatch, error) {
+	var batches []models.AnswerEditBatch
+	err := r.db.WithContext(ctx).
+		Where("session_id = ? AND question_id = ?", sessionID, questionID).
+		Order("seq ASC").
+		Find(&batches).
+		Error
+	return batches, err
+}
+
 // =====================
 // Results
 // =====================
@@ -275,6 +615,51 @@ func (r *assessmentRepository) GetResultBySessionID(ctx context.Context, session
 	return &result, nil
 }
 
+func (r *assessmentRepository) GetResultByID(ctx context.Context, id string) (*models.Result, error) {
+	var result models.Result
+	err := r.db.WithContext(ctx).
+		First(&result, "id = ?", id).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *assessmentRepository) UpdateResult(ctx context.Context, result *models.Result) error {
+	return r.db.WithContext(ctx).Save(result).Error
+}
+
+// SumCandidateLevelScore сколько баллов уровня level кандидат получил в результатах за [from, to)
+func (r *assessmentRepository) SumCandidateLevelScore(ctx context.Context, candidateID, level string, from, to time.Time) (float64, error) {
+	var total float64
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT COALESCE(SUM((r.level_scores->>?)::numeric), 0)
+			FROM results r
+			JOIN assessment_sessions s ON s.id = r.session_id
+			WHERE s.candidate_id = ? AND r.completed_at >= ? AND r.completed_at < ? AND r.deleted_at IS NULL`,
+			level, candidateID, from, to).
+		Scan(&total).Error
+	return total, err
+}
+
+// =====================
+// Competencies
+// =====================
+
+func (r *assessmentRepository) GetCompetencies(ctx context.Context, names []string) ([]models.Competency, error) {
+	var competencies []models.Competency
+	if len(names) == 0 {
+		return competencies, nil
+	}
+	err := r.db.WithContext(ctx).
+		Select("name", "category", "description").
+		Where("name IN ?", names).
+		Find(&competencies).
+		Error
+	return competencies, err
+}
+
 // =====================
 // Invitations
 // =====================
@@ -301,6 +686,17 @@ func (r *assessmentRepository) GetInvitationByToken(ctx context.Context, token s
 	return &invitation, nil
 }
 
+func (r *assessmentRepository) GetInvitationByID(ctx context.Context, id string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.db.WithContext(ctx).
+		First(&invitation, "id = ?", id).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
 func (r *assessmentRepository) UpdateInvitation(ctx context.Context, invitation *models.Invitation) error {
 	return r.db.WithContext(ctx).Save(invitation).Error
 }
@@ -314,3 +710,19 @@ func (r *assessmentRepository) GetInvitationsByAssessment(ctx context.Context, a
 		Error
 	return invitations, err
 }
+
+// GetCandidateInvitation последнее неистёкшее приглашение кандидата на оценку: привязанное к нему
+// или отправленное на его email
+func (r *assessmentRepository) GetCandidateInvitation(ctx context.Context, assessmentID, candidateID string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.db.WithContext(ctx).
+		Where("assessment_id = ? AND status <> ?", assessmentID, models.InvitationStatusExpired).
+		Where("candidate_id = ? OR lower(email) = (SELECT lower(email) FROM users WHERE id = ?)", candidateID, candidateID).
+		Order("created_at DESC").
+		First(&invitation).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
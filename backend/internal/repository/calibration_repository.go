@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type CalibrationRepository interface {
+	// Responses of completed sessions
+	GetItemResponses(ctx context.Context) ([]models.ItemResponse, error)
+
+	// IRT parameters on questions
+	SaveParams(ctx context.Context, params []models.IRTParams, calibratedAt time.Time) error
+
+	// Runs
+	CreateRun(ctx context.Context, run *models.CalibrationRun) error
+	UpdateRun(ctx context.Context, run *models.CalibrationRun) error
+	ListRuns(ctx context.Context, limit int) ([]models.CalibrationRun, error)
+}
+
+type calibrationRepository struct {
+	db *gorm.DB
+}
+
+func NewCalibrationRepository(db *gorm.DB) CalibrationRepository {
+	return &calibrationRepository{db: db}
+}
+
+// GetItemResponses верность всех ответов завершённых сессий (неотвеченные выданные вопросы — неверные)
+func (r *calibrationRepository) GetItemResponses(ctx context.Context) ([]models.ItemResponse, error) {
+	var responses []models.ItemResponse
+	err := r.db.WithContext(ctx).
+		Table("candidate_answers ca").
+		Select("ca.session_id, ca.question_id, (ca.is_correct AND ca.submitted_at IS NOT NULL) AS correct").
+		Joins("JOIN assessment_sessions s ON s.id = ca.session_id").
+		Where("s.status = ? AND ca.deleted_at IS NULL AND s.deleted_at IS NULL", models.SessionStatusCompleted).
+		Order("ca.session_id").
+		Scan(&responses).
+		Error
+	return responses, err
+}
+
+// SaveParams записывает параметры в вопросы одной транзакцией
+func (r *calibrationRepository) SaveParams(ctx context.Context, params []models.IRTParams, calibratedAt time.Time) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, p := range params {
+			if err := tx.Model(&models.Question{}).
+				Where("id = ?", p.QuestionID).
+				Updates(map[string]interface{}{
+					"irt_discrimination": p.Discrimination,
+					"irt_difficulty":     p.Difficulty,
+					"irt_responses":      p.Responses,
+					"irt_calibrated_at":  calibratedAt,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// =====================
+// Runs
+// =====================
+
+func (r *calibrationRepository) CreateRun(ctx context.Context, run *models.CalibrationRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *calibrationRepository) UpdateRun(ctx context.Context, run *models.CalibrationRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+func (r *calibrationRepository) ListRuns(ctx context.Context, limit int) ([]models.CalibrationRun, error) {
+	var runs []models.CalibrationRun
+	query := r.db.WithContext(ctx).Order("started_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&runs).Error
+	return runs, err
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
     "context"
+    "errors"
+    "strings"
     
     "github.com/easyhire/backend/internal/models"
     "gorm.io/gorm"
+    "gorm.io/gorm/clause"
 )
 
 type QuestionFilter struct {
@@ -13,11 +16,28 @@ type QuestionFilter struct {
     Level        string
     Type         string
     IsActive     *bool
-    Search       string
+    Search       string   // подстрока в заголовке или условии
+    Query        string   // полнотекстовый поиск (websearch-синтаксис), выдача по рангу
+    Tags         []string // имена тегов
+    TagMatch     string   // TagMatchAny (по умолчанию) или TagMatchAll
+    LatestOnly   bool // только последние версии вопросов
+    Status       string // validation_status
+    Preload      bool   // со связями: теги, варианты, тест-кейсы, рубрика
+    AIGenerated  *bool
     Limit        int
     Offset       int
 }
 
+// Режимы фильтра по тегам
+const (
+    TagMatchAny = "any" // хотя бы один из тегов
+    TagMatchAll = "all" // все теги
+)
+
+// searchConfig конфигурация текстового поиска: без стемминга, одинаково для русского и английского
+// (должна совпадать с questions.search_vector, миграция 025)
+const searchConfig = "simple"
+
 type QuestionRepository interface {
     // Basic CRUD
     CreateQuestion(ctx context.Context, question *models.Question) error
@@ -29,9 +49,59 @@ type QuestionRepository interface {
     // Specialized
     GetRandomQuestions(ctx context.Context, filter QuestionFilter, count int) ([]models.Question, error)
     GetQuestionsByCompetency(ctx context.Context, competencyID string, level string, limit int) ([]models.Question, error)
+    GetQuestionsByIDs(ctx context.Context, ids []string) ([]models.Question, error)
+    GetLatestQuestionByExternalID(ctx context.Context, externalID string) (*models.Question, error)
+    CountApprovedQuestions(ctx context.Context, competency, level string) (int64, error)
+    CountApprovedQuestionsIn(ctx context.Context, competencies []string, questionType, level string) (int64, error)
+    
+    // Versions
+    CreateQuestionVersion(ctx context.Context, previousID string, next *models.Question) error
+    GetQuestionVersions(ctx context.Context, rootID string) ([]models.Question, error)
+    RetireQuestion(ctx context.Context, rootID string) error
+    
+    // Validation workflow
+    ChangeValidationStatus(ctx context.Context, question *models.Question, entry *models.QuestionValidation) error
+    ListValidations(ctx context.Context, questionID string) ([]models.QuestionValidation, error)
+    
+    // Rubric
+    GetRubric(ctx context.Context, questionID string) ([]models.RubricCriterion, error)
     BulkCreateQuestions(ctx context.Context, questions []models.Question) error
+
+    // Analytics
+    GetAnswerSamples(ctx context.Context, questionIDs []string) ([]models.QuestionAnswerSample, error)
+
+    // Tags
+    ListTags(ctx context.Context) ([]models.QuestionTag, error)
+    GetTag(ctx context.Context, id string) (*models.QuestionTag, error)
+    GetTagByName(ctx context.Context, name string) (*models.QuestionTag, error)
+    CreateTag(ctx context.Context, tag *models.QuestionTag) error
+    UpdateTag(ctx context.Context, tag *models.QuestionTag) error
+    DeleteTag(ctx context.Context, id string) error
+    SetQuestionTags(ctx context.Context, questionID string, tags []models.QuestionTag) error
+
+    // Similarity index (MinHash + LSH)
+    SaveQuestionSignature(ctx context.Context, signature *models.QuestionSignature, buckets []int64) error
+    FindSimilarityCandidates(ctx context.Context, buckets []int64, excludeRootID string) ([]SimilarityCandidate, error)
+    ListSimilarityIndex(ctx context.Context) ([]SimilarityCandidate, error)
+    ListUnindexedQuestions(ctx context.Context, limit int) ([]models.Question, error)
 }
 
+// SimilarityCandidate сигнатура вопроса из индекса с полями для предупреждений и отчёта
+type SimilarityCandidate struct {
+    models.QuestionSignature
+    Title            string
+    Type             models.QuestionType
+    Competency       string
+    ValidationStatus models.ValidationStatus
+    AIGenerated      bool
+}
+
+// ErrValidationConflict статус вопроса успели изменить параллельно
+var ErrValidationConflict = errors.New("question validation status has changed")
+
+// ErrQuestionVersionConflict вопрос уже изменили: правка возможна только от последней версии
+var ErrQuestionVersionConflict = errors.New("question has a newer version")
+
 type questionRepository struct {
     db *gorm.DB
 }
@@ -41,16 +111,45 @@ func NewQuestionRepository(db *gorm.DB) QuestionRepository {
 }
 
 func (r *questionRepository) CreateQuestion(ctx context.Context, question *models.Question) error {
-    result := r.db.WithContext(ctx).Create(question)
-    return result.Error
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        return createQuestion(tx, question)
+    })
+}
+
+// createQuestion вставляет вопрос со связями; у первой версии RootID — её собственный id,
+// ExternalID без явного значения — RootID
+func createQuestion(tx *gorm.DB, question *models.Question) error {
+    if question.Version == 0 {
+        question.Version = 1
+    }
+    question.IsLatest = true
+    if err := resolveTags(tx, question.Tags); err != nil {
+        return err
+    }
+    // теги уже есть в справочнике — создаются только связи
+    if err := tx.Omit("Tags.*").Create(question).Error; err != nil {
+        return err
+    }
+    
+    updates := map[string]interface{}{}
+    if question.RootID == "" {
+        question.RootID = question.ID
+        updates["root_id"] = question.RootID
+    }
+    if question.ExternalID == "" {
+        question.ExternalID = question.RootID
+        updates["external_id"] = question.ExternalID
+    }
+    if len(updates) == 0 {
+        return nil
+    }
+    return tx.Model(question).Updates(updates).Error
 }
 
 func (r *questionRepository) GetQuestionByID(ctx context.Context, id string) (*models.Question, error) {
     var question models.Question
     result := r.db.WithContext(ctx).
-        Preload("Tags").
-        Preload("Options").
-        Preload("TestCases").
+        Scopes(preloadQuestion).
         First(&question, "id = ?", id)
     
     if result.Error != nil {
@@ -97,10 +196,37 @@ func (r *questionRepository) ListQuestions(ctx context.Context, filter QuestionF
             "%"+filter.Search+"%", "%"+filter.Search+"%")
     }
     
+    if filter.Query != "" {
+        query = query.Where("search_vector @@ websearch_to_tsquery('"+searchConfig+"', ?)", filter.Query)
+    }
+    
+    if tags := tagNames(filter.Tags); len(tags) > 0 {
+        tagged := r.db.Table("question_tag_associations qta").
+            Select("qta.question_id").
+            Joins("JOIN question_tags t ON t.id = qta.tag_id").
+            Where("t.name IN ?", tags)
+        if filter.TagMatch == TagMatchAll {
+            tagged = tagged.Group("qta.question_id").Having("COUNT(DISTINCT t.name) = ?", len(tags))
+        }
+        query = query.Where("id IN (?)", tagged)
+    }
+    
     if filter.IsActive != nil {
         query = query.Where("is_active = ?", *filter.IsActive)
     }
     
+    if filter.LatestOnly {
+        query = query.Where("is_latest = ?", true)
+    }
+    
+    if filter.Status != "" {
+        query = query.Where("validation_status = ?", filter.Status)
+    }
+    
+    if filter.AIGenerated != nil {
+        query = query.Where("ai_generated = ?", *filter.AIGenerated)
+    }
+    
     // Count total
     if err := query.Count(&total).Error; err != nil {
         return nil, 0, err
@@ -114,8 +240,25 @@ func (r *questionRepository) ListQuestions(ctx context.Context, filter QuestionF
         query = query.Offset(filter.Offset)
     }
     
+    if filter.Preload {
+        query = query.Scopes(preloadQuestion)
+    }
+    
+    if filter.Query != "" {
+        // ранг: совпадения в заголовке весят больше, чем в условии и разборе (веса A/B/C в search_vector)
+        tsquery := "websearch_to_tsquery('" + searchConfig + "', ?)"
+        query = query.
+            Select("questions.*, "+
+                "ts_rank_cd(search_vector, "+tsquery+") AS search_rank, "+
+                "ts_headline('"+searchConfig+"', title, "+tsquery+", 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight, "+
+                "ts_headline('"+searchConfig+"', coalesce(description, '') || ' ' || coalesce(explanation, ''), "+tsquery+", "+
+                "'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter= … ') AS snippet",
+                filter.Query, filter.Query, filter.Query).
+            Order("search_rank DESC")
+    }
+    
     // Execute query
-    result := query.Find(&questions)
+    result := query.Order("created_at DESC").Find(&questions)
     if result.Error != nil {
         return nil, 0, result.Error
     }
@@ -140,8 +283,8 @@ func (r *questionRepository) GetRandomQuestions(ctx context.Context, filter Ques
         query = query.Where("type = ?", filter.Type)
     }
     
-    // Get random questions
-    result := query.Where("is_active = ?", true).
+    // Get random questions (only the current, approved version of each question)
+    result := query.Where("is_active = ? AND is_latest = ? AND validation_status = ?", true, true, models.ValidationStatusApproved).
         Order("RANDOM()").
         Limit(count).
         Find(&questions)
@@ -157,8 +300,8 @@ func (r *questionRepository) GetQuestionsByCompetency(ctx context.Context, compe
     var questions []models.Question
     
     query := r.db.WithContext(ctx).
-        Where("competency = ? AND difficulty = ? AND is_active = ?", 
-            competencyID, level, true)
+        Where("competency = ? AND difficulty = ? AND is_active = ? AND is_latest = ? AND validation_status = ?", 
+            competencyID, level, true, true, models.ValidationStatusApproved)
     
     if limit > 0 {
         query = query.Limit(limit)
@@ -168,6 +311,65 @@ func (r *questionRepository) GetQuestionsByCompetency(ctx context.Context, compe
     return questions, result.Error
 }
 
+func (r *questionRepository) GetQuestionsByIDs(ctx context.Context, ids []string) ([]models.Question, error) {
+    var questions []models.Question
+    if len(ids) == 0 {
+        return questions, nil
+    }
+    
+    result := r.db.WithContext(ctx).
+        Preload("Options").
+        Preload("TestCases", orderByOrder).
+        Preload("Rubric", orderRubric).
+        Where("id IN ?", ids).
+        Find(&questions)
+    return questions, result.Error
+}
+
+// GetLatestQuestionByExternalID последняя версия вопроса с внешним id (gorm.ErrRecordNotFound, если нет)
+func (r *questionRepository) GetLatestQuestionByExternalID(ctx context.Context, externalID string) (*models.Question, error) {
+    var question models.Question
+    result := r.db.WithContext(ctx).
+        Scopes(preloadQuestion).
+        Where("external_id = ? AND is_latest = ?", externalID, true).
+        First(&question)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &question, nil
+}
+
+// CountApprovedQuestions вопросы, которые могут попасть в новую сессию (как в GetRandomQuestions)
+func (r *questionRepository) CountApprovedQuestions(ctx context.Context, competency, level string) (int64, error) {
+    var count int64
+    err := r.db.WithContext(ctx).
+        Model(&models.Question{}).
+        Where("competency = ? AND difficulty = ? AND is_active = ? AND is_latest = ? AND validation_status = ?",
+            competency, level, true, true, models.ValidationStatusApproved).
+        Count(&count).Error
+    return count, err
+}
+
+// CountApprovedQuestionsIn то же по нескольким компетенциям; пустые questionType и level — любые
+func (r *questionRepository) CountApprovedQuestionsIn(ctx context.Context, competencies []string, questionType, level string) (int64, error) {
+    var count int64
+    if len(competencies) == 0 {
+        return 0, nil
+    }
+    query := r.db.WithContext(ctx).
+        Model(&models.Question{}).
+        Where("competency IN ? AND is_active = ? AND is_latest = ? AND validation_status = ?",
+            competencies, true, true, models.ValidationStatusApproved)
+    if questionType != "" {
+        query = query.Where("type = ?", questionType)
+    }
+    if level != "" {
+        query = query.Where("difficulty = ?", level)
+    }
+    err := query.Count(&count).Error
+    return count, err
+}
+
 func (r *questionRepository) BulkCreateQuestions(ctx context.Context, questions []models.Question) error {
     if len(questions) == 0 {
         return nil
@@ -175,10 +377,327 @@ func (r *questionRepository) BulkCreateQuestions(ctx context.Context, questions
     
     return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
         for i := range questions {
-            if err := tx.Create(&questions[i]).Error; err != nil {
+            if err := createQuestion(tx, &questions[i]); err != nil {
                 return err
             }
         }
         return nil
     })
 }
+
+// CreateQuestionVersion сохраняет next как новую версию вопроса previousID.
+// Предыдущая версия перестаёт быть последней, но не меняется и не удаляется.
+func (r *questionRepository) CreateQuestionVersion(ctx context.Context, previousID string, next *models.Question) error {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var prev models.Question
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&prev, "id = ?", previousID).Error; err != nil {
+            return err
+        }
+        if !prev.IsLatest {
+            return ErrQuestionVersionConflict
+        }
+        if err := tx.Model(&prev).Update("is_latest", false).Error; err != nil {
+            return err
+        }
+        
+        next.ID = ""
+        next.RootID = prev.RootID
+        if next.RootID == "" {
+            next.RootID = prev.ID
+        }
+        if prev.ExternalID != "" {
+            next.ExternalID = prev.ExternalID
+        }
+        next.Version = prev.Version + 1
+        next.PreviousVersionID = &prev.ID
+        return createQuestion(tx, next)
+    })
+}
+
+func (r *questionRepository) GetQuestionVersions(ctx context.Context, rootID string) ([]models.Question, error) {
+    var questions []models.Question
+    result := r.db.WithContext(ctx).
+        Where("root_id = ?", rootID).
+        Order("version DESC").
+        Find(&questions)
+    return questions, result.Error
+}
+
+// RetireQuestion снимает с использования все версии вопроса. Строки не удаляются:
+// по ним оцениваются уже пройденные сессии.
+func (r *questionRepository) RetireQuestion(ctx context.Context, rootID string) error {
+    result := r.db.WithContext(ctx).
+        Model(&models.Question{}).
+        Where("root_id = ?", rootID).
+        Update("is_active", false)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return gorm.ErrRecordNotFound
+    }
+    return nil
+}
+
+// ChangeValidationStatus переводит вопрос из entry.FromStatus в entry.ToStatus и пишет журнал
+func (r *questionRepository) ChangeValidationStatus(ctx context.Context, question *models.Question, entry *models.QuestionValidation) error {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        result := tx.Model(&models.Question{}).
+            Where("id = ? AND validation_status = ?", question.ID, entry.FromStatus).
+            Updates(map[string]interface{}{
+                "validation_status": entry.ToStatus,
+                "validated_by":      question.ValidatedBy,
+                "validated_at":      question.ValidatedAt,
+            })
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return ErrValidationConflict
+        }
+        return tx.Create(entry).Error
+    })
+}
+
+func (r *questionRepository) ListValidations(ctx context.Context, questionID string) ([]models.QuestionValidation, error) {
+    var entries []models.QuestionValidation
+    result := r.db.WithContext(ctx).
+        Where("question_id = ?", questionID).
+        Order("created_at ASC").
+        Find(&entries)
+    return entries, result.Error
+}
+
+func (r *questionRepository) GetRubric(ctx context.Context, questionID string) ([]models.RubricCriterion, error) {
+    var criteria []models.RubricCriterion
+    result := r.db.WithContext(ctx).
+        Where("question_id = ?", questionID).
+        Scopes(orderRubric).
+        Find(&criteria)
+    return criteria, result.Error
+}
+
+// ==========================
+// TAGS
+// ==========================
+
+// ListTags справочник тегов с числом вопросов (последние действующие версии)
+func (r *questionRepository) ListTags(ctx context.Context) ([]models.QuestionTag, error) {
+    var tags []models.QuestionTag
+    result := r.db.WithContext(ctx).
+        Model(&models.QuestionTag{}).
+        Select(`question_tags.*, (
+            SELECT COUNT(*) FROM question_tag_associations qta
+            JOIN questions q ON q.id = qta.question_id
+            WHERE qta.tag_id = question_tags.id AND q.is_latest AND q.is_active AND q.deleted_at IS NULL
+        ) AS question_count`).
+        Order("name ASC").
+        Find(&tags)
+    return tags, result.Error
+}
+
+func (r *questionRepository) GetTag(ctx context.Context, id string) (*models.QuestionTag, error) {
+    var tag models.QuestionTag
+    if err := r.db.WithContext(ctx).First(&tag, "id = ?", id).Error; err != nil {
+        return nil, err
+    }
+    return &tag, nil
+}
+
+// GetTagByName тег по имени (gorm.ErrRecordNotFound, если нет)
+func (r *questionRepository) GetTagByName(ctx context.Context, name string) (*models.QuestionTag, error) {
+    var tag models.QuestionTag
+    if err := r.db.WithContext(ctx).First(&tag, "name = ?", name).Error; err != nil {
+        return nil, err
+    }
+    return &tag, nil
+}
+
+func (r *questionRepository) CreateTag(ctx context.Context, tag *models.QuestionTag) error {
+    return r.db.WithContext(ctx).Create(tag).Error
+}
+
+func (r *questionRepository) UpdateTag(ctx context.Context, tag *models.QuestionTag) error {
+    return r.db.WithContext(ctx).
+        Model(tag).
+        Select("name", "description", "color").
+        Updates(tag).Error
+}
+
+// DeleteTag удаляет тег из справочника вместе со связями (ON DELETE CASCADE)
+func (r *questionRepository) DeleteTag(ctx context.Context, id string) error {
+    result := r.db.WithContext(ctx).Delete(&models.QuestionTag{}, "id = ?", id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return gorm.ErrRecordNotFound
+    }
+    return nil
+}
+
+// SetQuestionTags заменяет теги версии вопроса; отсутствующие в справочнике теги создаются
+func (r *questionRepository) SetQuestionTags(ctx context.Context, questionID string, tags []models.QuestionTag) error {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        if err := resolveTags(tx, tags); err != nil {
+            return err
+        }
+        if err := tx.Exec("DELETE FROM question_tag_associations WHERE question_id = ?", questionID).Error; err != nil {
+            return err
+        }
+        for _, tag := range tags {
+            if err := tx.Exec(
+                "INSERT INTO question_tag_associations (question_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+                questionID, tag.ID,
+            ).Error; err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
+
+// resolveTags проставляет id тегам по имени, создавая недостающие в справочнике
+func resolveTags(tx *gorm.DB, tags []models.QuestionTag) error {
+    for i := range tags {
+        if tags[i].ID != "" {
+            continue
+        }
+        if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+            Create(&tags[i]).Error; err != nil {
+            return err
+        }
+        if tags[i].ID != "" {
+            continue
+        }
+        // тег уже был в справочнике
+        if err := tx.Where("name = ?", tags[i].Name).First(&tags[i]).Error; err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// tagNames непустые имена тегов без повторов
+func tagNames(tags []string) []string {
+    seen := map[string]bool{}
+    names := make([]string, 0, len(tags))
+    for _, tag := range tags {
+        tag = strings.ToLower(strings.TrimSpace(tag))
+        if tag == "" || seen[tag] {
+            continue
+        }
+        seen[tag] = true
+        names = append(names, tag)
+    }
+    return names
+}
+
+func orderRubric(db *gorm.DB) *gorm.DB {
+    return db.Order(`"order" ASC`)
+}
+
+func preloadQuestion(db *gorm.DB) *gorm.DB {
+    return db.
+        Preload("Tags").
+        Preload("Options", orderByOrder).
+        Preload("TestCases", orderByOrder).
+        Preload("Rubric", orderRubric)
+}
+
+func orderByOrder(db *gorm.DB) *gorm.DB {
+    return db.Order(`"order" ASC`)
+}
+
+// GetAnswerSamples ответы на вопросы в завершённых сессиях с итоговым процентом сессии
+func (r *questionRepository) GetAnswerSamples(ctx context.Context, questionIDs []string) ([]models.QuestionAnswerSample, error) {
+    var samples []models.QuestionAnswerSample
+    if len(questionIDs) == 0 {
+        return samples, nil
+    }
+
+    result := r.db.WithContext(ctx).
+        Table("candidate_answers ca").
+        Select(`ca.question_id, ca.answer, ca.is_correct, ca.time_spent,
+            ca.submitted_at IS NOT NULL AS submitted,
+            COALESCE(res.percentage, s.percentage, 0) AS total_percentage`).
+        Joins("JOIN assessment_sessions s ON s.id = ca.session_id").
+        Joins("LEFT JOIN results res ON res.session_id = s.id").
+        Where("s.status = ? AND ca.deleted_at IS NULL AND s.deleted_at IS NULL", models.SessionStatusCompleted).
+        Where("ca.question_id IN ?", questionIDs).
+        Scan(&samples)
+    return samples, result.Error
+}
+
+// ==========================
+// SIMILARITY INDEX
+// ==========================
+
+// SaveQuestionSignature заменяет сигнатуру и корзины LSH вопроса (по RootID)
+func (r *questionRepository) SaveQuestionSignature(ctx context.Context, signature *models.QuestionSignature, buckets []int64) error {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(signature).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("root_id = ?", signature.RootID).Delete(&models.QuestionLSHBucket{}).Error; err != nil {
+            return err
+        }
+        if len(buckets) == 0 {
+            return nil
+        }
+        rows := make([]models.QuestionLSHBucket, 0, len(buckets))
+        seen := make(map[int64]bool, len(buckets))
+        for _, b := range buckets {
+            if !seen[b] {
+                seen[b] = true
+                rows = append(rows, models.QuestionLSHBucket{RootID: signature.RootID, Bucket: b})
+            }
+        }
+        return tx.Create(&rows).Error
+    })
+}
+
+// similarityIndex сигнатуры действующих вопросов (последние версии, не снятые и не удалённые)
+func (r *questionRepository) similarityIndex(ctx context.Context) *gorm.DB {
+    return r.db.WithContext(ctx).
+        Table("question_signatures s").
+        Select("s.*, q.title, q.type, q.competency, q.validation_status, q.ai_generated").
+        Joins("JOIN questions q ON q.id = s.question_id AND q.deleted_at IS NULL AND q.is_active = TRUE")
+}
+
+// FindSimilarityCandidates вопросы, попавшие хотя бы в одну из корзин
+func (r *questionRepository) FindSimilarityCandidates(ctx context.Context, buckets []int64, excludeRootID string) ([]SimilarityCandidate, error) {
+    var candidates []SimilarityCandidate
+    if len(buckets) == 0 {
+        return candidates, nil
+    }
+    query := r.similarityIndex(ctx).
+        Where("s.root_id IN (?)", r.db.Table("question_lsh_buckets").Distinct("root_id").Where("bucket IN ?", buckets))
+    if excludeRootID != "" {
+        query = query.Where("s.root_id <> ?", excludeRootID)
+    }
+    err := query.Scan(&candidates).Error
+    return candidates, err
+}
+
+// ListSimilarityIndex весь индекс — для отчёта о дубликатах
+func (r *questionRepository) ListSimilarityIndex(ctx context.Context) ([]SimilarityCandidate, error) {
+    var candidates []SimilarityCandidate
+    err := r.similarityIndex(ctx).Order("s.root_id").Scan(&candidates).Error
+    return candidates, err
+}
+
+// ListUnindexedQuestions последние версии без актуальной сигнатуры (новые или изменённые до появления индекса)
+func (r *questionRepository) ListUnindexedQuestions(ctx context.Context, limit int) ([]models.Question, error) {
+    var questions []models.Question
+    query := r.db.WithContext(ctx).
+        Preload("Options", orderByOrder).
+        Where("is_latest = TRUE").
+        Where("NOT EXISTS (SELECT 1 FROM question_signatures s WHERE s.question_id = questions.id)").
+        Order("created_at ASC")
+    if limit > 0 {
+        query = query.Limit(limit)
+    }
+    err := query.Find(&questions).Error
+    return questions, err
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ResultRepository interface {
+	// Scores of a result per scope + incremental distribution update
+	RecordScores(ctx context.Context, scores []models.ResultScore) error
+	GetResultScores(ctx context.Context, resultID string) ([]models.ResultScore, error)
+	CountScores(ctx context.Context, assessmentID, competency string, percentage float64) (models.ScoreCounts, error)
+
+	// Distributions
+	GetDistribution(ctx context.Context, assessmentID, competency string) (*models.ScoreDistribution, error)
+	ListDistributions(ctx context.Context, assessmentID string) ([]models.ScoreDistribution, error)
+}
+
+type resultRepository struct {
+	db *gorm.DB
+}
+
+func NewResultRepository(db *gorm.DB) ResultRepository {
+	return &resultRepository{db: db}
+}
+
+// RecordScores сохраняет проценты результата и добавляет их в статистику разрезов одной транзакцией
+func (r *resultRepository) RecordScores(ctx context.Context, scores []models.ResultScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&scores).Error; err != nil {
+			return err
+		}
+
+		for _, sc := range scores {
+			// строка статистики может ещё не существовать
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&models.ScoreDistribution{AssessmentID: sc.AssessmentID, Competency: sc.Competency}).Error; err != nil {
+				return err
+			}
+
+			var dist models.ScoreDistribution
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				First(&dist, "assessment_id = ? AND competency = ?", sc.AssessmentID, sc.Competency).Error; err != nil {
+				return err
+			}
+
+			dist.Add(sc.Percentage)
+			dist.UpdatedAt = time.Now()
+			if err := tx.Save(&dist).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *resultRepository) GetResultScores(ctx context.Context, resultID string) ([]models.ResultScore, error) {
+	var scores []models.ResultScore
+	err := r.db.WithContext(ctx).
+		Where("result_id = ?", resultID).
+		Order("competency ASC").
+		Find(&scores).
+		Error
+	return scores, err
+}
+
+func (r *resultRepository) CountScores(ctx context.Context, assessmentID, competency string, percentage float64) (models.ScoreCounts, error) {
+	var counts models.ScoreCounts
+	err := r.db.WithContext(ctx).
+		Model(&models.ResultScore{}).
+		Select("COUNT(*) FILTER (WHERE percentage > ?) AS above, COUNT(*) FILTER (WHERE percentage < ?) AS below, COUNT(*) AS total",
+			percentage, percentage).
+		Where("assessment_id = ? AND competency = ?", assessmentID, competency).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// =====================
+// Distributions
+// =====================
+
+func (r *resultRepository) GetDistribution(ctx context.Context, assessmentID, competency string) (*models.ScoreDistribution, error) {
+	var dist models.ScoreDistribution
+	err := r.db.WithContext(ctx).
+		First(&dist, "assessment_id = ? AND competency = ?", assessmentID, competency).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &dist, nil
+}
+
+func (r *resultRepository) ListDistributions(ctx context.Context, assessmentID string) ([]models.ScoreDistribution, error) {
+	var dists []models.ScoreDistribution
+	err := r.db.WithContext(ctx).
+		Where("assessment_id = ?", assessmentID).
+		Order("competency ASC").
+		Find(&dists).
+		Error
+	return dists, err
+}
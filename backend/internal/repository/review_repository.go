@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ReviewFilter struct {
+	ReviewerID string
+	SessionID  string
+	Status     string
+	Limit      int
+	Offset     int
+}
+
+type ReviewRepository interface {
+	CreateReviews(ctx context.Context, reviews []models.AnswerReview) error
+	GetReviewByID(ctx context.Context, id string) (*models.AnswerReview, error)
+	UpdateReview(ctx context.Context, review *models.AnswerReview) error
+	AssignReview(ctx context.Context, id, reviewerID string, from []models.ReviewStatus, at time.Time) (bool, error)
+	SubmitReview(ctx context.Context, review *models.AnswerReview) (bool, error)
+	ListReviews(ctx context.Context, filter ReviewFilter) ([]models.AnswerReview, int64, error)
+	GetSessionReviews(ctx context.Context, sessionID string) ([]models.AnswerReview, error)
+	GetAnswerReviews(ctx context.Context, answerID string) ([]models.AnswerReview, error)
+	CountOpenReviews(ctx context.Context, sessionID string) (int64, error)
+
+	// Calibration
+	ListSubmittedReviews(ctx context.Context, filter AgreementFilter) ([]models.AnswerReview, error)
+
+	// AI grading
+	CreateAISuggestion(ctx context.Context, suggestion *models.AIGradeSuggestion) error
+	GetLatestAISuggestion(ctx context.Context, answerID string) (*models.AIGradeSuggestion, error)
+
+	// Reviewers
+	IsTechnicalExpert(ctx context.Context, userID string) (bool, error)
+	PickLeastLoadedExpert(ctx context.Context, exclude []string) (string, error)
+}
+
+type AgreementFilter struct {
+	QuestionID string
+	From       *time.Time
+	To         *time.Time
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+func (r *reviewRepository) CreateReviews(ctx context.Context, reviews []models.AnswerReview) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&reviews).Error
+}
+
+func (r *reviewRepository) GetReviewByID(ctx context.Context, id string) (*models.AnswerReview, error) {
+	var review models.AnswerReview
+	err := r.db.WithContext(ctx).First(&review, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *reviewRepository) UpdateReview(ctx context.Context, review *models.AnswerReview) error {
+	return r.db.WithContext(ctx).Save(review).Error
+}
+
+// AssignReview назначает проверку эксперту одним UPDATE: только если она ещё в одном из статусов from
+// и эксперт не проверяет тот же ответ. false — проверку успели забрать или изменить.
+func (r *reviewRepository) AssignReview(ctx context.Context, id, reviewerID string, from []models.ReviewStatus, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.AnswerReview{}).
+		Where("id = ? AND status IN ?", id, from).
+		Where(`NOT EXISTS (SELECT 1 FROM answer_reviews o WHERE o.answer_id = answer_reviews.answer_id
+			AND o.id <> answer_reviews.id AND o.reviewer_id = ? AND o.deleted_at IS NULL)`, reviewerID).
+		Updates(map[string]interface{}{
+			"reviewer_id": reviewerID,
+			"status":      models.ReviewStatusAssigned,
+			"assigned_at": at,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// SubmitReview сохраняет оценку эксперта одним UPDATE: только если проверка всё ещё назначена ему
+// и не сдана. false — оценку уже выставили или проверку переназначили.
+func (r *reviewRepository) SubmitReview(ctx context.Context, review *models.AnswerReview) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.AnswerReview{}).
+		Where("id = ? AND reviewer_id = ? AND status <> ?", review.ID, review.ReviewerID, models.ReviewStatusSubmitted).
+		Select("score", "comment", "criterion_scores", "status", "submitted_at", "ai_suggestion_id", "ai_decision").
+		Updates(review)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *reviewRepository) ListReviews(ctx context.Context, filter ReviewFilter) ([]models.AnswerReview, int64, error) {
+	var reviews []models.AnswerReview
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AnswerReview{})
+
+	if filter.ReviewerID != "" {
+		query = query.Where("reviewer_id = ?", filter.ReviewerID)
+	}
+	if filter.SessionID != "" {
+		query = query.Where("session_id = ?", filter.SessionID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	err := query.Order("created_at ASC").Find(&reviews).Error
+	return reviews, total, err
+}
+
+func (r *reviewRepository) GetSessionReviews(ctx context.Context, sessionID string) ([]models.AnswerReview, error) {
+	var reviews []models.AnswerReview
+	err := r.db.WithContext(ctx).
+		Where("session_id = ?", sessionID).
+		Order("created_at ASC").
+		Find(&reviews).
+		Error
+	return reviews, err
+}
+
+func (r *reviewRepository) GetAnswerReviews(ctx context.Context, answerID string) ([]models.AnswerReview, error) {
+	var reviews []models.AnswerReview
+	err := r.db.WithContext(ctx).
+		Where("answer_id = ?", answerID).
+		Order("created_at ASC").
+		Find(&reviews).
+		Error
+	return reviews, err
+}
+
+func (r *reviewRepository) CountOpenReviews(ctx context.Context, sessionID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.AnswerReview{}).
+		Where("session_id = ? AND status <> ?", sessionID, models.ReviewStatusSubmitted).
+		Count(&count).
+		Error
+	return count, err
+}
+
+// =====================
+// Calibration
+// =====================
+
+// ListSubmittedReviews все выставленные оценки (включая adjudication) для ответов с оценкой в окне
+func (r *reviewRepository) ListSubmittedReviews(ctx context.Context, filter AgreementFilter) ([]models.AnswerReview, error) {
+	var reviews []models.AnswerReview
+
+	query := r.db.WithContext(ctx).
+		Where("status = ?", models.ReviewStatusSubmitted)
+
+	if filter.QuestionID != "" {
+		query = query.Where("question_id = ?", filter.QuestionID)
+	}
+	if filter.From != nil {
+		query = query.Where("submitted_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("submitted_at < ?", *filter.To)
+	}
+
+	err := query.Order("answer_id, submitted_at").Find(&reviews).Error
+	return reviews, err
+}
+
+// =====================
+// AI grading
+// =====================
+
+func (r *reviewRepository) CreateAISuggestion(ctx context.Context, suggestion *models.AIGradeSuggestion) error {
+	return r.db.WithContext(ctx).Create(suggestion).Error
+}
+
+// GetLatestAISuggestion последняя успешная оценка модели по ответу (неудачные попытки остаются только для аудита)
+func (r *reviewRepository) GetLatestAISuggestion(ctx context.Context, answerID string) (*models.AIGradeSuggestion, error) {
+	var suggestion models.AIGradeSuggestion
+	err := r.db.WithContext(ctx).
+		Where("answer_id = ? AND status = ?", answerID, models.AIGradeStatusSuggested).
+		Order("created_at DESC").
+		First(&suggestion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+// =====================
+// Reviewers
+// =====================
+
+func (r *reviewRepository) IsTechnicalExpert(ctx context.Context, userID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Raw("SELECT COUNT(*) FROM users WHERE id = ? AND role = 'technical_expert' AND is_active = TRUE", userID).
+		Scan(&count).Error
+	return count > 0, err
+}
+
+// PickLeastLoadedExpert возвращает активного эксперта с наименьшим числом открытых проверок,
+// пропуская exclude (эксперты, уже проверяющие этот ответ)
+func (r *reviewRepository) PickLeastLoadedExpert(ctx context.Context, exclude []string) (string, error) {
+	type row struct {
+		ID string
+	}
+	var u row
+
+	query := r.db.WithContext(ctx).
+		Table("users u").
+		Select("u.id").
+		Joins("LEFT JOIN answer_reviews ar ON ar.reviewer_id = u.id AND ar.status = ? AND ar.deleted_at IS NULL", models.ReviewStatusAssigned).
+		Where("u.role = 'technical_expert' AND u.is_active = TRUE AND u.deleted_at IS NULL")
+	if len(exclude) > 0 {
+		query = query.Where("u.id NOT IN ?", exclude)
+	}
+
+	err := query.
+		Group("u.id").
+		Order("COUNT(ar.id) ASC, u.id").
+		Limit(1).
+		Scan(&u).Error
+	if err != nil {
+		return "", err
+	}
+	if u.ID == "" {
+		return "", gorm.ErrRecordNotFound
+	}
+	return u.ID, nil
+}
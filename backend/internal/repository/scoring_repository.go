@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/easyhire/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ScoringRepository interface {
+	// Config versions
+	GetActiveConfig(ctx context.Context) (*models.ScoringConfigVersion, error)
+	GetConfigByVersion(ctx context.Context, version int) (*models.ScoringConfigVersion, error)
+	ListConfigs(ctx context.Context) ([]models.ScoringConfigVersion, error)
+	CreateConfig(ctx context.Context, cfg *models.ScoringConfigVersion) error
+	ActivateConfig(ctx context.Context, version int) error
+
+	// Legacy tables (migration 004)
+	GetLevelWeights(ctx context.Context) ([]models.LevelWeight, error)
+	GetLevelThresholds(ctx context.Context) ([]models.LevelThreshold, error)
+	GetCompetencyWeights(ctx context.Context) (map[string]float64, error)
+}
+
+type scoringRepository struct {
+	db *gorm.DB
+}
+
+func NewScoringRepository(db *gorm.DB) ScoringRepository {
+	return &scoringRepository{db: db}
+}
+
+// =====================
+// Config versions
+// =====================
+
+func (r *scoringRepository) GetActiveConfig(ctx context.Context) (*models.ScoringConfigVersion, error) {
+	var cfg models.ScoringConfigVersion
+	err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Order("version DESC").
+		First(&cfg).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (r *scoringRepository) GetConfigByVersion(ctx context.Context, version int) (*models.ScoringConfigVersion, error) {
+	var cfg models.ScoringConfigVersion
+	err := r.db.WithContext(ctx).First(&cfg, "version = ?", version).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (r *scoringRepository) ListConfigs(ctx context.Context) ([]models.ScoringConfigVersion, error) {
+	var configs []models.ScoringConfigVersion
+	err := r.db.WithContext(ctx).Order("version DESC").Find(&configs).Error
+	return configs, err
+}
+
+// CreateConfig сохраняет новую версию под следующим номером; активация — отдельно (ActivateConfig).
+// Гонку двух админов ловит уникальный индекс по version.
+func (r *scoringRepository) CreateConfig(ctx context.Context, cfg *models.ScoringConfigVersion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&models.ScoringConfigVersion{}).
+			Unscoped().
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		cfg.Version = last + 1
+		cfg.IsActive = false
+		return tx.Create(cfg).Error
+	})
+}
+
+// ActivateConfig делает версию активной и синхронизирует level_weights/level_thresholds
+func (r *scoringRepository) ActivateConfig(ctx context.Context, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cfg models.ScoringConfigVersion
+		if err := tx.First(&cfg, "version = ?", version).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.ScoringConfigVersion{}).
+			Where("is_active = ? AND version <> ?", true, version).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&cfg).Update("is_active", true).Error; err != nil {
+			return err
+		}
+
+		for level, weight := range cfg.Config.LevelWeights {
+			row := models.LevelWeight{Level: level, Weight: weight}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "level"}},
+				DoUpdates: clause.AssignmentColumns([]string{"weight"}),
+			}).Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		for level, threshold := range cfg.Config.LevelThresholds {
+			row := models.LevelThreshold{Level: level, Threshold: threshold}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "level"}},
+				DoUpdates: clause.AssignmentColumns([]string{"threshold"}),
+			}).Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// =====================
+// Legacy tables
+// =====================
+
+func (r *scoringRepository) GetLevelWeights(ctx context.Context) ([]models.LevelWeight, error) {
+	var weights []models.LevelWeight
+	err := r.db.WithContext(ctx).Find(&weights).Error
+	return weights, err
+}
+
+func (r *scoringRepository) GetLevelThresholds(ctx context.Context) ([]models.LevelThreshold, error) {
+	var thresholds []models.LevelThreshold
+	err := r.db.WithContext(ctx).Find(&thresholds).Error
+	return thresholds, err
+}
+
+// GetCompetencyWeights base_weight из справочника competencies
+func (r *scoringRepository) GetCompetencyWeights(ctx context.Context) (map[string]float64, error) {
+	var competencies []models.Competency
+	err := r.db.WithContext(ctx).
+		Select("name", "base_weight").
+		Where("base_weight IS NOT NULL").
+		Find(&competencies).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	weights := make(map[string]float64, len(competencies))
+	for _, c := range competencies {
+		weights[c.Name] = c.BaseWeight
+	}
+	return weights, nil
+}
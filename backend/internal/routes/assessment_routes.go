@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/easyhire/backend/internal/handlers"
 	"github.com/easyhire/backend/internal/middleware"
+	"github.com/easyhire/internal/models"
 	"github.com/easyhire/internal/pkg/auth"
 	"github.com/gin-gonic/gin"
 )
@@ -18,12 +19,19 @@ func SetupAssessmentRoutes(router *gin.RouterGroup, jwtService *auth.JWTService,
 		assessments.GET("/:id", assessmentHandler.GetAssessment)
 		assessments.PUT("/:id", middleware.HRorAdmin(), assessmentHandler.UpdateAssessment)
 		assessments.DELETE("/:id", middleware.HRorAdmin(), assessmentHandler.DeleteAssessment)
+		assessments.POST("/:id/clone", middleware.HRorAdmin(), assessmentHandler.CloneAssessment)
+
+		// Lifecycle: draft -> active (published) -> archived
+		assessments.GET("/:id/publish-check", middleware.HRorAdmin(), assessmentHandler.PublishCheck)
+		assessments.POST("/:id/publish", middleware.HRorAdmin(), assessmentHandler.PublishAssessment)
+		assessments.POST("/:id/archive", middleware.HRorAdmin(), assessmentHandler.ArchiveAssessment)
 
 		// Invitations
 		assessments.POST("/:id/invite", middleware.HRorAdmin(), assessmentHandler.InviteCandidate)
 		assessments.POST("/:id/bulk-invite", middleware.HRorAdmin(), assessmentHandler.BulkInvite)
 
-		// Candidate starts session for assessment
+		// Candidate checks the availability window / attempt rules and starts a session
+		assessments.GET("/:id/availability", assessmentHandler.CheckAvailability)
 		assessments.POST("/:id/start", assessmentHandler.StartSession)
 	}
 
@@ -32,13 +40,27 @@ func SetupAssessmentRoutes(router *gin.RouterGroup, jwtService *auth.JWTService,
 	sessions.Use(middleware.AuthMiddleware(jwtService))
 	{
 		sessions.GET("/:session_id", assessmentHandler.GetSession)
+		sessions.GET("/:session_id/questions/:question_id", assessmentHandler.ServeQuestion)
 		sessions.POST("/:session_id/answers", assessmentHandler.SubmitAnswer)
+		sessions.GET("/:session_id/answers",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin),
+			assessmentHandler.GetAnswerDetails,
+		)
 		sessions.POST("/:session_id/complete", assessmentHandler.CompleteSession)
+
+		// Edit history of coding answers (candidate streams deltas, experts replay)
+		sessions.POST("/:session_id/answers/:question_id/edits", assessmentHandler.RecordEdits)
+		sessions.GET("/:session_id/answers/:question_id/replay",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin),
+			assessmentHandler.ReplayAnswer,
+		)
 	}
 
-	// Invitation token lookup (public)
-	invitations := router.Group("/invitations")
+	// Results
+	results := router.Group("/results")
+	results.Use(middleware.AuthMiddleware(jwtService))
+	results.Use(middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin))
 	{
-		invitations.GET("/:token", assessmentHandler.GetInvitation)
+		results.GET("/:id/breakdown", assessmentHandler.GetResultBreakdown)
 	}
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/easyhire/backend/internal/handlers"
+	"github.com/easyhire/backend/internal/middleware"
+	"github.com/easyhire/internal/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAssessmentTemplateRoutes(router *gin.RouterGroup, jwtService *auth.JWTService, templateHandler *handlers.AssessmentTemplateHandler) {
+	// Reusable assessment presets; built-in ones are seeded from TEST_STRUCTURE and read-only
+	templates := router.Group("/assessment-templates")
+	templates.Use(middleware.AuthMiddleware(jwtService))
+	templates.Use(middleware.HRorAdmin())
+	{
+		templates.GET("", templateHandler.ListTemplates)
+		templates.POST("", templateHandler.CreateTemplate)
+		templates.GET("/:id", templateHandler.GetTemplate)
+		templates.PUT("/:id", templateHandler.UpdateTemplate)
+		templates.DELETE("/:id", templateHandler.DeleteTemplate)
+		templates.POST("/:id/instantiate", templateHandler.InstantiateTemplate)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/easyhire/backend/internal/handlers"
+	"github.com/easyhire/backend/internal/middleware"
+	"github.com/easyhire/internal/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupCalibrationRoutes(router *gin.RouterGroup, jwtService *auth.JWTService, calibrationHandler *handlers.CalibrationHandler) {
+	// IRT calibration of the question bank (admin)
+	irt := router.Group("/scoring/irt")
+	irt.Use(middleware.AuthMiddleware(jwtService))
+	irt.Use(middleware.AdminOnly())
+	{
+		irt.POST("/calibrate", calibrationHandler.Calibrate)
+		irt.GET("/runs", calibrationHandler.ListRuns)
+	}
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/easyhire/backend/internal/handlers"
+	"github.com/easyhire/backend/internal/middleware"
+	"github.com/easyhire/internal/models"
+	"github.com/easyhire/internal/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupFeedbackRoutes(router *gin.RouterGroup, jwtService *auth.JWTService, feedbackHandler *handlers.FeedbackHandler) {
+	// Full feedback for staff, optionally enriched by the AI provider
+	results := router.Group("/results")
+	results.Use(middleware.AuthMiddleware(jwtService))
+	results.Use(middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin))
+	{
+		results.GET("/:id/feedback", feedbackHandler.GetResultFeedback)
+		results.POST("/:id/feedback", middleware.HRorAdmin(), feedbackHandler.RegenerateFeedback)
+	}
+
+	// Candidate-safe feedback on the candidate's own session
+	sessions := router.Group("/sessions")
+	sessions.Use(middleware.AuthMiddleware(jwtService))
+	{
+		sessions.GET("/:session_id/feedback", feedbackHandler.GetCandidateFeedback)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/easyhire/backend/internal/handlers"
+	"github.com/easyhire/backend/internal/middleware"
+	"github.com/easyhire/internal/models"
+	"github.com/easyhire/internal/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupGenerationRoutes(router *gin.RouterGroup, jwtService *auth.JWTService, generationHandler *handlers.GenerationHandler) {
+	// AI question generation; results are drafts waiting for expert review
+	questions := router.Group("/questions")
+	questions.Use(middleware.AuthMiddleware(jwtService))
+	{
+		questions.POST("/generate",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			generationHandler.GenerateQuestions,
+		)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/easyhire/backend/internal/handlers"
+	"github.com/easyhire/backend/internal/middleware"
+	"github.com/easyhire/internal/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupInvitationRoutes(router *gin.RouterGroup, jwtService *auth.JWTService, invitationHandler *handlers.InvitationHandler) {
+	// Candidate side of an invitation: the token from the email is the credential
+	invitations := router.Group("/invitations")
+	{
+		invitations.GET("/:token", invitationHandler.GetInvitation)
+		// a signed-in candidate accepts as themselves, otherwise by password or sign-up
+		invitations.POST("/:token/accept", middleware.OptionalAuth(jwtService), invitationHandler.AcceptInvitation)
+	}
+}
@@ -0,0 +1,126 @@
+package routes
+
+import (
+	"github.com/easyhire/backend/internal/handlers"
+	"github.com/easyhire/backend/internal/middleware"
+	"github.com/easyhire/internal/models"
+	"github.com/easyhire/internal/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupQuestionRoutes(router *gin.RouterGroup, jwtService *auth.JWTService, questionHandler *handlers.QuestionHandler) {
+	// Question bank is managed by technical experts and admins
+	questions := router.Group("/questions")
+	questions.Use(middleware.AuthMiddleware(jwtService))
+	{
+		// CRUD; PUT creates a new immutable version, DELETE retires all versions
+		questions.GET("",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin),
+			questionHandler.ListQuestions,
+		)
+		questions.POST("",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.CreateQuestion,
+		)
+		questions.GET("/:id",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin),
+			questionHandler.GetQuestion,
+		)
+		questions.PUT("/:id",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.UpdateQuestion,
+		)
+		questions.DELETE("/:id",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.DeleteQuestion,
+		)
+		questions.GET("/:id/versions",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin),
+			questionHandler.ListVersions,
+		)
+
+		// Tags: catalogue management and per-question tags (not versioned)
+		questions.GET("/tags",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin),
+			questionHandler.ListTags,
+		)
+		questions.POST("/tags",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.CreateTag,
+		)
+		questions.PUT("/tags/:tag_id",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.UpdateTag,
+		)
+		questions.DELETE("/tags/:tag_id",
+			middleware.AdminOnly(),
+			questionHandler.DeleteTag,
+		)
+		questions.PUT("/:id/tags",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.SetQuestionTags,
+		)
+
+		// Import / export: YAML/JSON bundles, Moodle GIFT, IMS QTI 2.1
+		questions.POST("/import",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.ImportQuestions,
+		)
+		questions.GET("/export",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.ExportQuestions,
+		)
+
+		// Validation workflow: draft -> pending -> approved / rejected / needs_review
+		questions.POST("/:id/submit",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.SubmitForReview,
+		)
+		questions.POST("/:id/validate",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.ValidateQuestion,
+		)
+		questions.POST("/:id/check-solution",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.CheckSolution,
+		)
+		questions.GET("/:id/validations",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin),
+			questionHandler.ListValidations,
+		)
+
+		// Rubric (grading criteria)
+		questions.GET("/:id/rubric",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin),
+			questionHandler.GetRubric,
+		)
+		questions.PUT("/:id/rubric",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.SetRubric,
+		)
+
+		// Near-duplicate detection (MinHash similarity index)
+		questions.GET("/duplicates",
+			middleware.AdminOnly(),
+			questionHandler.DuplicateReport,
+		)
+		questions.POST("/duplicates/reindex",
+			middleware.AdminOnly(),
+			questionHandler.ReindexSimilarity,
+		)
+		questions.GET("/:id/similar",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.FindSimilarQuestions,
+		)
+
+		// Answer statistics and auto-flags
+		questions.GET("/analytics",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.ListQuestionAnalytics,
+		)
+		questions.GET("/:id/analytics",
+			middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleAdmin),
+			questionHandler.GetQuestionAnalytics,
+		)
+	}
+}
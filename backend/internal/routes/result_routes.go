@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/easyhire/backend/internal/handlers"
+	"github.com/easyhire/backend/internal/middleware"
+	"github.com/easyhire/internal/models"
+	"github.com/easyhire/internal/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupResultRoutes(router *gin.RouterGroup, jwtService *auth.JWTService, resultHandler *handlers.ResultHandler) {
+	staff := middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin)
+
+	results := router.Group("/results")
+	results.Use(middleware.AuthMiddleware(jwtService))
+	results.Use(staff)
+	{
+		results.GET("/:id/standing", resultHandler.GetResultStanding)
+	}
+
+	assessments := router.Group("/assessments")
+	assessments.Use(middleware.AuthMiddleware(jwtService))
+	assessments.Use(staff)
+	{
+		assessments.GET("/:id/distribution", resultHandler.GetDistributions)
+	}
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/easyhire/backend/internal/handlers"
+	"github.com/easyhire/backend/internal/middleware"
+	"github.com/easyhire/internal/models"
+	"github.com/easyhire/internal/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupReviewRoutes(router *gin.RouterGroup, jwtService *auth.JWTService, reviewHandler *handlers.ReviewHandler) {
+	// Manual review queue: technical experts grade, HR/admin manage assignments
+	reviews := router.Group("/reviews")
+	reviews.Use(middleware.AuthMiddleware(jwtService))
+	reviews.Use(middleware.RoleMiddleware(models.RoleTechnicalExpert, models.RoleHR, models.RoleAdmin))
+	{
+		reviews.GET("", reviewHandler.ListReviews)
+		reviews.GET("/agreement", middleware.HRorAdmin(), reviewHandler.AgreementReport)
+		reviews.GET("/:id", reviewHandler.GetReview)
+
+		reviews.POST("/:id/assign", middleware.HRorAdmin(), reviewHandler.AssignReview)
+		reviews.POST("/:id/claim", middleware.ExpertOnly(), reviewHandler.ClaimReview)
+		reviews.POST("/:id/submit", middleware.ExpertOnly(), reviewHandler.SubmitReview)
+
+		// AI-assisted grading: suggested grade the expert accepts or overrides
+		reviews.POST("/ai-suggestions", middleware.HRorAdmin(), reviewHandler.SuggestSessionGrades)
+		reviews.POST("/:id/ai-suggestion", reviewHandler.SuggestGrade)
+		reviews.POST("/:id/ai-suggestion/accept", middleware.ExpertOnly(), reviewHandler.AcceptAISuggestion)
+	}
+}
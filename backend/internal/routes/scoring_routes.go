@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/easyhire/backend/internal/handlers"
+	"github.com/easyhire/backend/internal/middleware"
+	"github.com/easyhire/internal/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupScoringRoutes(router *gin.RouterGroup, jwtService *auth.JWTService, scoringHandler *handlers.ScoringHandler) {
+	// Scoring formula versions are managed by admins only
+	configs := router.Group("/scoring/configs")
+	configs.Use(middleware.AuthMiddleware(jwtService))
+	configs.Use(middleware.AdminOnly())
+	{
+		configs.GET("", scoringHandler.ListConfigs)
+		configs.POST("", scoringHandler.CreateConfig)
+		configs.GET("/active", scoringHandler.GetActiveConfig)
+		configs.GET("/:version", scoringHandler.GetConfig)
+		configs.POST("/:version/activate", scoringHandler.ActivateConfig)
+	}
+}
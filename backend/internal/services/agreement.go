@@ -0,0 +1,284 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+const (
+	// defaultDisagreementThreshold допустимый разброс оценок экспертов (доля от максимального балла)
+	defaultDisagreementThreshold = 0.25
+	// agreementBands на сколько диапазонов делим нормированную оценку для kappa
+	agreementBands = 4
+)
+
+// scoreSpread разница между максимальной и минимальной оценкой
+func scoreSpread(reviews []models.AnswerReview) float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, r := range reviews {
+		if r.Score == nil {
+			continue
+		}
+		lo = math.Min(lo, *r.Score)
+		hi = math.Max(hi, *r.Score)
+	}
+	if hi < lo {
+		return 0
+	}
+	return hi - lo
+}
+
+// meanReviewScore средняя оценка экспертов и средние баллы по каждому критерию рубрики
+func meanReviewScore(reviews []models.AnswerReview) (float64, []models.CriterionScore) {
+	var total float64
+	var n int
+	var criteria []models.CriterionScore
+	index := map[string]int{}
+	counts := map[string]int{}
+
+	for _, r := range reviews {
+		if r.Score == nil {
+			continue
+		}
+		total += *r.Score
+		n++
+
+		for _, c := range r.CriterionScores {
+			i, ok := index[c.CriterionID]
+			if !ok {
+				i = len(criteria)
+				index[c.CriterionID] = i
+				criteria = append(criteria, models.CriterionScore{
+					CriterionID: c.CriterionID,
+					Name:        c.Name,
+					MaxPoints:   c.MaxPoints,
+					Source:      models.ScoreSourceReview,
+				})
+			}
+			criteria[i].Points += c.Points
+			counts[c.CriterionID]++
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	for i := range criteria {
+		criteria[i].Points /= float64(counts[criteria[i].CriterionID])
+	}
+	return total / float64(n), criteria
+}
+
+// =====================
+// Inter-rater agreement
+// =====================
+
+type ratedAnswer struct {
+	questionID string
+	ratings    []rating
+}
+
+type rating struct {
+	reviewerID string
+	value      float64 // оценка, нормированная в 0..1
+}
+
+// agreementAccumulator копит пары оценок для kappa и разброс по ответам
+type agreementAccumulator struct {
+	answers  int
+	pairs    int
+	tables   map[[2]string]*raterPairTable
+	absDiff  float64
+	variance float64
+}
+
+// raterPairTable таблица сопряжённости двух экспертов: first — эксперт с меньшим id
+type raterPairTable struct {
+	items  int
+	agree  int
+	first  [agreementBands]int
+	second [agreementBands]int
+}
+
+// kappa Cohen's kappa пары: ожидаемое случайное согласие — по маргиналам каждого эксперта
+func (t *raterPairTable) kappa() float64 {
+	po := float64(t.agree) / float64(t.items)
+	var pe float64
+	for b := 0; b < agreementBands; b++ {
+		pe += float64(t.first[b]) / float64(t.items) * float64(t.second[b]) / float64(t.items)
+	}
+	if pe >= 1 {
+		return 1
+	}
+	return (po - pe) / (1 - pe)
+}
+
+func (a *agreementAccumulator) addAnswer(ratings []rating) {
+	if len(ratings) < 2 {
+		return
+	}
+	a.answers++
+
+	var mean float64
+	for _, r := range ratings {
+		mean += r.value
+	}
+	mean /= float64(len(ratings))
+	var v float64
+	for _, r := range ratings {
+		v += (r.value - mean) * (r.value - mean)
+	}
+	a.variance += v / float64(len(ratings))
+}
+
+func (a *agreementAccumulator) addPair(x, y rating) {
+	if y.reviewerID < x.reviewerID {
+		x, y = y, x
+	}
+	key := [2]string{x.reviewerID, y.reviewerID}
+	if a.tables == nil {
+		a.tables = map[[2]string]*raterPairTable{}
+	}
+	t := a.tables[key]
+	if t == nil {
+		t = &raterPairTable{}
+		a.tables[key] = t
+	}
+
+	bx, by := band(x.value), band(y.value)
+	t.items++
+	if bx == by {
+		t.agree++
+	}
+	t.first[bx]++
+	t.second[by]++
+	a.pairs++
+	a.absDiff += math.Abs(x.value - y.value)
+}
+
+func (a *agreementAccumulator) stats() models.AgreementStats {
+	st := models.AgreementStats{Answers: a.answers, Pairs: a.pairs}
+	if a.answers > 0 {
+		st.Variance = round4(a.variance / float64(a.answers))
+	}
+	if a.pairs == 0 {
+		return st
+	}
+	st.MeanAbsDiff = round4(a.absDiff / float64(a.pairs))
+
+	// Cohen's kappa каждой пары экспертов, усреднённая с весом по числу общих ответов
+	var kappa float64
+	for _, t := range a.tables {
+		kappa += t.kappa() * float64(t.items)
+	}
+	st.Kappa = round4(kappa / float64(a.pairs))
+	return st
+}
+
+// buildAgreementReport считает согласованность по выставленным оценкам.
+// Учитываются только обычные проверки; решения арбитров идут в счётчик Adjudicated.
+func buildAgreementReport(reviews []models.AnswerReview) *models.AgreementReport {
+	answers := map[string]*ratedAnswer{}
+	var answerOrder []string
+	adjudicated := map[string]int{}
+
+	for _, r := range reviews {
+		if r.Kind == models.ReviewKindAdjudication {
+			adjudicated[r.QuestionID]++
+			continue
+		}
+		if r.Score == nil || r.ReviewerID == nil || r.MaxScore <= 0 {
+			continue
+		}
+		ans, ok := answers[r.AnswerID]
+		if !ok {
+			ans = &ratedAnswer{questionID: r.QuestionID}
+			answers[r.AnswerID] = ans
+			answerOrder = append(answerOrder, r.AnswerID)
+		}
+		ans.ratings = append(ans.ratings, rating{
+			reviewerID: *r.ReviewerID,
+			value:      *r.Score / r.MaxScore,
+		})
+	}
+
+	var overall agreementAccumulator
+	byReviewer := map[string]*agreementAccumulator{}
+	byQuestion := map[string]*agreementAccumulator{}
+	biasSum := map[string]float64{}
+	biasN := map[string]int{}
+
+	acc := func(m map[string]*agreementAccumulator, key string) *agreementAccumulator {
+		if m[key] == nil {
+			m[key] = &agreementAccumulator{}
+		}
+		return m[key]
+	}
+
+	for _, id := range answerOrder {
+		ans := answers[id]
+		if len(ans.ratings) < 2 {
+			continue
+		}
+
+		overall.addAnswer(ans.ratings)
+		acc(byQuestion, ans.questionID).addAnswer(ans.ratings)
+
+		var sum float64
+		for _, r := range ans.ratings {
+			sum += r.value
+		}
+		for i, r := range ans.ratings {
+			acc(byReviewer, r.reviewerID).addAnswer(ans.ratings)
+
+			others := (sum - r.value) / float64(len(ans.ratings)-1)
+			biasSum[r.reviewerID] += r.value - others
+			biasN[r.reviewerID]++
+
+			for _, peer := range ans.ratings[i+1:] {
+				overall.addPair(r, peer)
+				acc(byQuestion, ans.questionID).addPair(r, peer)
+				acc(byReviewer, r.reviewerID).addPair(r, peer)
+				acc(byReviewer, peer.reviewerID).addPair(r, peer)
+			}
+		}
+	}
+
+	report := &models.AgreementReport{
+		Overall:   overall.stats(),
+		Reviewers: []models.ReviewerAgreement{},
+		Questions: []models.QuestionAgreement{},
+	}
+	for id, a := range byReviewer {
+		report.Reviewers = append(report.Reviewers, models.ReviewerAgreement{
+			ReviewerID:     id,
+			AgreementStats: a.stats(),
+			Bias:           round4(biasSum[id] / float64(biasN[id])),
+		})
+	}
+	// вопросы, где были только арбитражи, тоже попадают в отчёт
+	for id := range adjudicated {
+		acc(byQuestion, id)
+	}
+	for id, a := range byQuestion {
+		report.Questions = append(report.Questions, models.QuestionAgreement{
+			QuestionID:     id,
+			AgreementStats: a.stats(),
+			Adjudicated:    adjudicated[id],
+		})
+	}
+
+	sort.Slice(report.Reviewers, func(i, j int) bool { return report.Reviewers[i].ReviewerID < report.Reviewers[j].ReviewerID })
+	sort.Slice(report.Questions, func(i, j int) bool { return report.Questions[i].QuestionID < report.Questions[j].QuestionID })
+	return report
+}
+
+func band(v float64) int {
+	b := int(v * agreementBands)
+	return clamp(b, 0, agreementBands-1)
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+func submitted(answerID, questionID, reviewerID string, score, max float64) models.AnswerReview {
+	return models.AnswerReview{
+		Kind:       models.ReviewKindReview,
+		AnswerID:   answerID,
+		QuestionID: questionID,
+		ReviewerID: &reviewerID,
+		Status:     models.ReviewStatusSubmitted,
+		Score:      &score,
+		MaxScore:   max,
+	}
+}
+
+func TestAgreementPerfect(t *testing.T) {
+	reviews := []models.AnswerReview{
+		submitted("a1", "q1", "r1", 0, 10), submitted("a1", "q1", "r2", 0, 10),
+		submitted("a2", "q1", "r1", 10, 10), submitted("a2", "q1", "r2", 10, 10),
+		submitted("a3", "q1", "r1", 5, 10), submitted("a3", "q1", "r2", 5, 10),
+	}
+	report := buildAgreementReport(reviews)
+
+	if report.Overall.Kappa != 1 {
+		t.Errorf("kappa = %v, want 1", report.Overall.Kappa)
+	}
+	if report.Overall.MeanAbsDiff != 0 || report.Overall.Variance != 0 {
+		t.Errorf("mean_abs_diff = %v, variance = %v, want 0", report.Overall.MeanAbsDiff, report.Overall.Variance)
+	}
+	if report.Overall.Answers != 3 || report.Overall.Pairs != 3 {
+		t.Errorf("answers = %d, pairs = %d, want 3 and 3", report.Overall.Answers, report.Overall.Pairs)
+	}
+}
+
+// Ожидаемое согласие считается по маргиналам каждого эксперта, а не по общему распределению:
+// r1 ставит 0,0,1,1, r2 — 0,1,1,1; po = 0.75, pe = 0.5·0.25 + 0.5·0.75 = 0.5, kappa = 0.5
+// (с общими маргиналами вышло бы ≈ 0.4667)
+func TestAgreementKappaUsesRaterMarginals(t *testing.T) {
+	reviews := []models.AnswerReview{
+		submitted("a1", "q1", "r1", 0, 1), submitted("a1", "q1", "r2", 0, 1),
+		submitted("a2", "q1", "r1", 0, 1), submitted("a2", "q1", "r2", 1, 1),
+		submitted("a3", "q1", "r1", 1, 1), submitted("a3", "q1", "r2", 1, 1),
+		// порядок экспертов в ответе на таблицу пары не влияет
+		submitted("a4", "q1", "r2", 1, 1), submitted("a4", "q1", "r1", 1, 1),
+	}
+	report := buildAgreementReport(reviews)
+
+	if report.Overall.Kappa != 0.5 {
+		t.Errorf("kappa = %v, want 0.5", report.Overall.Kappa)
+	}
+	if report.Overall.MeanAbsDiff != 0.25 {
+		t.Errorf("mean_abs_diff = %v, want 0.25", report.Overall.MeanAbsDiff)
+	}
+}
+
+// Kappa по нескольким парам — среднее kappa пар с весом по числу общих ответов
+func TestAgreementKappaWeightedByPair(t *testing.T) {
+	reviews := []models.AnswerReview{
+		// r1/r2: полное согласие на двух ответах, kappa = 1
+		submitted("a1", "q1", "r1", 0, 1), submitted("a1", "q1", "r2", 0, 1),
+		submitted("a2", "q1", "r1", 1, 1), submitted("a2", "q1", "r2", 1, 1),
+		// r3/r4: полное несогласие на двух ответах, kappa = -1
+		submitted("a3", "q1", "r3", 0, 1), submitted("a3", "q1", "r4", 1, 1),
+		submitted("a4", "q1", "r3", 1, 1), submitted("a4", "q1", "r4", 0, 1),
+		submitted("a5", "q1", "r3", 0, 1), submitted("a5", "q1", "r4", 1, 1),
+		submitted("a6", "q1", "r3", 1, 1), submitted("a6", "q1", "r4", 0, 1),
+	}
+	report := buildAgreementReport(reviews)
+
+	want := (1*2 + -1*4) / 6.0
+	if math.Abs(report.Overall.Kappa-round4(want)) > 1e-9 {
+		t.Errorf("kappa = %v, want %v", report.Overall.Kappa, round4(want))
+	}
+
+	byReviewer := map[string]models.ReviewerAgreement{}
+	for _, r := range report.Reviewers {
+		byReviewer[r.ReviewerID] = r
+	}
+	if got := byReviewer["r1"].Kappa; got != 1 {
+		t.Errorf("r1 kappa = %v, want 1", got)
+	}
+	if got := byReviewer["r3"].Kappa; got != -1 {
+		t.Errorf("r3 kappa = %v, want -1", got)
+	}
+}
+
+func TestAgreementSkipsAdjudicationAndSingleRatings(t *testing.T) {
+	adjudication := submitted("a1", "q2", "r3", 5, 10)
+	adjudication.Kind = models.ReviewKindAdjudication
+	reviews := []models.AnswerReview{
+		submitted("a1", "q2", "r1", 2, 10),
+		submitted("a1", "q2", "r2", 8, 10),
+		adjudication,
+		// единственная оценка ответа в согласованность не входит
+		submitted("a2", "q3", "r1", 7, 10),
+	}
+	report := buildAgreementReport(reviews)
+
+	if report.Overall.Answers != 1 || report.Overall.Pairs != 1 {
+		t.Fatalf("answers = %d, pairs = %d, want 1 and 1", report.Overall.Answers, report.Overall.Pairs)
+	}
+	if report.Overall.MeanAbsDiff != 0.6 {
+		t.Errorf("mean_abs_diff = %v, want 0.6", report.Overall.MeanAbsDiff)
+	}
+	if len(report.Questions) != 1 || report.Questions[0].QuestionID != "q2" || report.Questions[0].Adjudicated != 1 {
+		t.Errorf("questions = %+v, want q2 with one adjudication", report.Questions)
+	}
+	for _, r := range report.Reviewers {
+		if r.ReviewerID == "r3" {
+			t.Errorf("adjudicator r3 must not appear among reviewers")
+		}
+	}
+}
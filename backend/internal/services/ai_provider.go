@@ -0,0 +1,330 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/easyhire/backend/internal/pkg/config"
+)
+
+// ErrAIUnavailable провайдер не настроен или не отвечает
+var ErrAIUnavailable = errors.New("AI service is unavailable")
+
+// AIProvider языковая модель, возвращающая ответ на промпт.
+// Реализации: gemini, openrouter / openai (chat completions) и stub без сети для тестов и локальной разработки.
+type AIProvider interface {
+	Name() string
+	Model() string
+	Complete(ctx context.Context, req AICompletionRequest) (*AICompletion, error)
+}
+
+// AICompletionRequest запрос к модели; пустые Model/Temperature/MaxTokens — из конфигурации
+type AICompletionRequest struct {
+	System      string
+	Prompt      string
+	Model       string
+	Temperature float64
+	MaxTokens   int
+	JSON        bool // ответ должен быть JSON-объектом
+}
+
+// AICompletion ответ модели
+type AICompletion struct {
+	Text             string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// NewAIProvider провайдер из конфигурации (AI_PROVIDER: gemini, openrouter, openai, stub)
+func NewAIProvider(cfg config.AIConfig) (AIProvider, error) {
+	provider := strings.ToLower(cfg.Provider)
+	if provider != "stub" && cfg.APIKey == "" {
+		return nil, fmt.Errorf("%w: AI_API_KEY is not set", ErrAIUnavailable)
+	}
+	if cfg.MaxTokens <= 0 {
+		cfg.MaxTokens = 1000
+	}
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+	switch provider {
+	case "gemini":
+		return &geminiProvider{cfg: cfg, baseURL: baseURLOr(cfg.BaseURL, "https://generativelanguage.googleapis.com/v1beta"), client: client}, nil
+	case "openrouter":
+		return &chatCompletionsProvider{name: provider, cfg: cfg, baseURL: baseURLOr(cfg.BaseURL, "https://openrouter.ai/api/v1"), client: client}, nil
+	case "openai":
+		return &chatCompletionsProvider{name: provider, cfg: cfg, baseURL: baseURLOr(cfg.BaseURL, "https://api.openai.com/v1"), client: client}, nil
+	case "stub":
+		return NewStubAIProvider(), nil
+	}
+	return nil, fmt.Errorf("unknown AI provider %q", cfg.Provider)
+}
+
+func baseURLOr(baseURL, fallback string) string {
+	if baseURL == "" {
+		baseURL = fallback
+	}
+	return strings.TrimRight(baseURL, "/")
+}
+
+// completionParams параметры запроса с подстановкой значений из конфигурации
+func completionParams(cfg config.AIConfig, req AICompletionRequest) (model string, temperature float64, maxTokens int) {
+	model, temperature, maxTokens = req.Model, req.Temperature, req.MaxTokens
+	if model == "" {
+		model = cfg.Model
+	}
+	if temperature == 0 {
+		temperature = cfg.Temperature
+	}
+	if maxTokens == 0 {
+		maxTokens = cfg.MaxTokens
+	}
+	return model, temperature, maxTokens
+}
+
+// postJSON POST с JSON-телом; ответ не 2xx — ошибка с телом ответа
+func postJSON(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		// *url.Error содержит адрес запроса — в ответ клиенту и в логи он не попадает
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%w: %v", ErrAIUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return fmt.Errorf("read AI response failed: %w", err)
+	}
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: provider returned %d: %s", ErrAIUnavailable, resp.StatusCode, truncate(string(data), 500))
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("AI provider returned %d: %s", resp.StatusCode, truncate(string(data), 500))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode AI response failed: %w", err)
+	}
+	return nil
+}
+
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit] + "…"
+}
+
+// ==========================
+// GEMINI
+// ==========================
+
+type geminiProvider struct {
+	cfg     config.AIConfig
+	baseURL string
+	client  *http.Client
+}
+
+func (p *geminiProvider) Name() string  { return "gemini" }
+func (p *geminiProvider) Model() string { return p.cfg.Model }
+
+func (p *geminiProvider) Complete(ctx context.Context, req AICompletionRequest) (*AICompletion, error) {
+	model, temperature, maxTokens := completionParams(p.cfg, req)
+
+	type part struct {
+		Text string `json:"text"`
+	}
+	type content struct {
+		Role  string `json:"role,omitempty"`
+		Parts []part `json:"parts"`
+	}
+	generationConfig := map[string]interface{}{
+		"temperature":     temperature,
+		"maxOutputTokens": maxTokens,
+	}
+	if req.JSON {
+		generationConfig["responseMimeType"] = "application/json"
+	}
+	body := map[string]interface{}{
+		"contents":         []content{{Role: "user", Parts: []part{{Text: req.Prompt}}}},
+		"generationConfig": generationConfig,
+	}
+	if req.System != "" {
+		body["systemInstruction"] = content{Parts: []part{{Text: req.System}}}
+	}
+
+	var resp struct {
+		Candidates []struct {
+			Content      content `json:"content"`
+			FinishReason string  `json:"finishReason"`
+		} `json:"candidates"`
+		UsageMetadata struct {
+			PromptTokenCount     int `json:"promptTokenCount"`
+			CandidatesTokenCount int `json:"candidatesTokenCount"`
+		} `json:"usageMetadata"`
+	}
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent", p.baseURL, url.PathEscape(model))
+	headers := map[string]string{"x-goog-api-key": p.cfg.APIKey}
+	if err := postJSON(ctx, p.client, endpoint, headers, body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Candidates) == 0 {
+		return nil, fmt.Errorf("AI provider returned no candidates")
+	}
+
+	var text strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return &AICompletion{
+		Text:             text.String(),
+		Model:            model,
+		PromptTokens:     resp.UsageMetadata.PromptTokenCount,
+		CompletionTokens: resp.UsageMetadata.CandidatesTokenCount,
+	}, nil
+}
+
+// ==========================
+// CHAT COMPLETIONS (OpenRouter, OpenAI)
+// ==========================
+
+type chatCompletionsProvider struct {
+	name    string
+	cfg     config.AIConfig
+	baseURL string
+	client  *http.Client
+}
+
+func (p *chatCompletionsProvider) Name() string  { return p.name }
+func (p *chatCompletionsProvider) Model() string { return p.cfg.Model }
+
+func (p *chatCompletionsProvider) Complete(ctx context.Context, req AICompletionRequest) (*AICompletion, error) {
+	model, temperature, maxTokens := completionParams(p.cfg, req)
+
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	var messages []message
+	if req.System != "" {
+		messages = append(messages, message{Role: "system", Content: req.System})
+	}
+	messages = append(messages, message{Role: "user", Content: req.Prompt})
+
+	body := map[string]interface{}{
+		"model":       model,
+		"messages":    messages,
+		"temperature": temperature,
+		"max_tokens":  maxTokens,
+	}
+	if req.JSON {
+		body["response_format"] = map[string]string{"type": "json_object"}
+	}
+
+	var resp struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message message `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	headers := map[string]string{"Authorization": "Bearer " + p.cfg.APIKey}
+	if err := postJSON(ctx, p.client, p.baseURL+"/chat/completions", headers, body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("AI provider returned no choices")
+	}
+	if resp.Model != "" {
+		model = resp.Model
+	}
+	return &AICompletion{
+		Text:             resp.Choices[0].Message.Content,
+		Model:            model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+	}, nil
+}
+
+// ==========================
+// STUB
+// ==========================
+
+// StubAIProvider провайдер без сети: отвечает заданными ответами по очереди,
+// а без них — детерминированным вопросом, который проходит валидацию генерации
+type StubAIProvider struct {
+	mu        sync.Mutex
+	responses []string
+	calls     int
+	Requests  []AICompletionRequest
+}
+
+func NewStubAIProvider(responses ...string) *StubAIProvider {
+	return &StubAIProvider{responses: responses}
+}
+
+func (p *StubAIProvider) Name() string  { return "stub" }
+func (p *StubAIProvider) Model() string { return "stub-1" }
+
+func (p *StubAIProvider) Complete(ctx context.Context, req AICompletionRequest) (*AICompletion, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	p.Requests = append(p.Requests, req)
+	if len(p.responses) > 0 {
+		text := p.responses[0]
+		if len(p.responses) > 1 {
+			p.responses = p.responses[1:]
+		}
+		return &AICompletion{Text: text, Model: p.Model()}, nil
+	}
+	return &AICompletion{Text: stubQuestionJSON(p.calls), Model: p.Model()}, nil
+}
+
+// stubQuestionJSON подходит для любого типа: варианты для multiple choice,
+// тест-кейс и эталонное решение (эхо stdin) для coding/debugging
+func stubQuestionJSON(n int) string {
+	return fmt.Sprintf(`{
+  "title": "Stub question #%d",
+  "description": "Generated by the stub AI provider. Echo the input.",
+  "options": [
+    {"text": "Correct answer", "is_correct": true},
+    {"text": "Wrong answer", "is_correct": false}
+  ],
+  "test_cases": [{"input": "hello", "expected": "hello", "is_hidden": false}],
+  "starter_code": "package main\n\nfunc main() {\n}\n",
+  "reference_solution": "package main\n\nimport (\n\t\"io\"\n\t\"os\"\n)\n\nfunc main() {\n\tio.Copy(os.Stdout, os.Stdin)\n}\n",
+  "solution_language": "go",
+  "explanation": "Stub explanation.",
+  "tags": ["stub"]
+}`, n)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+// applyAntiFarming урезает баллы по уровням сложности (docs/assessment-framework.md, Anti-Farming Protection):
+//   - junior-баллы кандидата за сутки (с учётом уже завершённых сессий) не больше MaxJuniorScorePerDay;
+//   - один уровень даёт не больше MaxSameLevelRatio от общего счёта — только если в оценке есть
+//     вопросы нескольких уровней, иначе правило теряет смысл.
+//
+// Возвращает множитель для баллов каждого уровня, итоговые баллы по уровням и флаги сработавших правил.
+func applyAntiFarming(earned, possible map[string]float64, history models.ScoringHistory, cfg models.AntiFarmingConfig) (map[string]float64, map[string]float64, []models.AntiFarmingFlag) {
+	capped := make(map[string]float64, len(earned))
+	for level, v := range earned {
+		capped[level] = v
+	}
+	flags := []models.AntiFarmingFlag{}
+
+	// 1. Дневной лимит junior-баллов
+	if cfg.MaxJuniorScorePerDay > 0 && capped["junior"] > 0 {
+		remaining := math.Max(0, float64(cfg.MaxJuniorScorePerDay)-history.JuniorScoreToday)
+		if capped["junior"] > remaining {
+			flags = append(flags, models.AntiFarmingFlag{
+				Rule:     models.AntiFarmingRuleJuniorDailyCap,
+				Level:    "junior",
+				Original: round4(capped["junior"]),
+				Capped:   round4(remaining),
+				Limit:    float64(cfg.MaxJuniorScorePerDay),
+				Message: fmt.Sprintf("junior-level score capped at %.2f: %.2f of the daily %d points already earned today",
+					remaining, history.JuniorScoreToday, cfg.MaxJuniorScorePerDay),
+			})
+			capped["junior"] = remaining
+		}
+	}
+
+	// 2. Доля одного уровня в общем счёте
+	levelsInAssessment := 0
+	for _, p := range possible {
+		if p > 0 {
+			levelsInAssessment++
+		}
+	}
+	if cfg.MaxSameLevelRatio > 0 && cfg.MaxSameLevelRatio < 1 && levelsInAssessment > 1 {
+		var total float64
+		for _, v := range capped {
+			total += v
+		}
+		limit := total * cfg.MaxSameLevelRatio
+		for _, level := range scoringLevels {
+			if capped[level] <= limit {
+				continue
+			}
+			flags = append(flags, models.AntiFarmingFlag{
+				Rule:     models.AntiFarmingRuleSameLevelRatio,
+				Level:    level,
+				Original: round4(capped[level]),
+				Capped:   round4(limit),
+				Limit:    cfg.MaxSameLevelRatio,
+				Message: fmt.Sprintf("%s-level score capped at %.0f%% of the total (%.2f of %.2f)",
+					level, cfg.MaxSameLevelRatio*100, capped[level], total),
+			})
+			capped[level] = limit
+		}
+	}
+
+	factors := make(map[string]float64, len(earned))
+	for level, v := range earned {
+		factors[level] = 1
+		if v > 0 {
+			factors[level] = capped[level] / v
+		}
+	}
+	return factors, capped, flags
+}
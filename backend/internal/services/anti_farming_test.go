@@ -0,0 +1,102 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestAntiFarmingNoRulesTriggered(t *testing.T) {
+	earned := map[string]float64{"junior": 10, "middle": 15, "senior": 12}
+	possible := map[string]float64{"junior": 20, "middle": 20, "senior": 20}
+	cfg := models.AntiFarmingConfig{MaxJuniorScorePerDay: 100, MaxSameLevelRatio: 0.6}
+
+	factors, capped, flags := applyAntiFarming(earned, possible, models.ScoringHistory{JuniorScoreToday: 50}, cfg)
+
+	if len(flags) != 0 {
+		t.Fatalf("flags = %+v, want none", flags)
+	}
+	for level, v := range earned {
+		if factors[level] != 1 || capped[level] != v {
+			t.Errorf("%s: factor = %v, capped = %v, want 1 and %v", level, factors[level], capped[level], v)
+		}
+	}
+}
+
+func TestAntiFarmingJuniorDailyCap(t *testing.T) {
+	earned := map[string]float64{"junior": 30}
+	possible := map[string]float64{"junior": 40}
+	cfg := models.AntiFarmingConfig{MaxJuniorScorePerDay: 100}
+
+	factors, capped, flags := applyAntiFarming(earned, possible, models.ScoringHistory{JuniorScoreToday: 80}, cfg)
+
+	if !approx(capped["junior"], 20) || !approx(factors["junior"], 20.0/30) {
+		t.Errorf("junior: capped = %v, factor = %v, want 20 and 2/3", capped["junior"], factors["junior"])
+	}
+	if len(flags) != 1 || flags[0].Rule != models.AntiFarmingRuleJuniorDailyCap || flags[0].Original != 30 || flags[0].Capped != 20 {
+		t.Errorf("flags = %+v, want one junior_daily_cap 30 → 20", flags)
+	}
+
+	// лимит уже выбран другими сессиями — junior-баллы обнуляются
+	factors, capped, _ = applyAntiFarming(earned, possible, models.ScoringHistory{JuniorScoreToday: 120}, cfg)
+	if capped["junior"] != 0 || factors["junior"] != 0 {
+		t.Errorf("junior over the daily cap: capped = %v, factor = %v, want 0", capped["junior"], factors["junior"])
+	}
+}
+
+func TestAntiFarmingSameLevelRatio(t *testing.T) {
+	earned := map[string]float64{"junior": 40, "middle": 10, "senior": 0}
+	possible := map[string]float64{"junior": 50, "middle": 50, "senior": 50}
+	cfg := models.AntiFarmingConfig{MaxSameLevelRatio: 0.6}
+
+	factors, capped, flags := applyAntiFarming(earned, possible, models.ScoringHistory{}, cfg)
+
+	// лимит — 60% от общего счёта 50
+	if !approx(capped["junior"], 30) || !approx(factors["junior"], 0.75) {
+		t.Errorf("junior: capped = %v, factor = %v, want 30 and 0.75", capped["junior"], factors["junior"])
+	}
+	if capped["middle"] != 10 || factors["middle"] != 1 {
+		t.Errorf("middle: capped = %v, factor = %v, want 10 and 1", capped["middle"], factors["middle"])
+	}
+	// уровень без баллов не урезается и сохраняет множитель 1
+	if factors["senior"] != 1 {
+		t.Errorf("senior factor = %v, want 1", factors["senior"])
+	}
+	if len(flags) != 1 || flags[0].Rule != models.AntiFarmingRuleSameLevelRatio || flags[0].Level != "junior" {
+		t.Errorf("flags = %+v, want one same_level_ratio for junior", flags)
+	}
+}
+
+func TestAntiFarmingSameLevelRatioSkippedForSingleLevel(t *testing.T) {
+	earned := map[string]float64{"middle": 40}
+	possible := map[string]float64{"middle": 50, "senior": 0}
+	cfg := models.AntiFarmingConfig{MaxSameLevelRatio: 0.6}
+
+	factors, capped, flags := applyAntiFarming(earned, possible, models.ScoringHistory{}, cfg)
+
+	if len(flags) != 0 || capped["middle"] != 40 || factors["middle"] != 1 {
+		t.Errorf("single-level assessment: capped = %v, factor = %v, flags = %+v, want no capping",
+			capped["middle"], factors["middle"], flags)
+	}
+}
+
+func TestAntiFarmingBothRules(t *testing.T) {
+	earned := map[string]float64{"junior": 50, "middle": 10}
+	possible := map[string]float64{"junior": 60, "middle": 60}
+	cfg := models.AntiFarmingConfig{MaxJuniorScorePerDay: 100, MaxSameLevelRatio: 0.5}
+
+	// дневной лимит: 50 → 30, затем доля: общий счёт 40, лимит 20
+	factors, capped, flags := applyAntiFarming(earned, possible, models.ScoringHistory{JuniorScoreToday: 70}, cfg)
+
+	if !approx(capped["junior"], 20) || !approx(factors["junior"], 0.4) {
+		t.Errorf("junior: capped = %v, factor = %v, want 20 and 0.4", capped["junior"], factors["junior"])
+	}
+	if len(flags) != 2 || flags[0].Rule != models.AntiFarmingRuleJuniorDailyCap || flags[1].Rule != models.AntiFarmingRuleSameLevelRatio {
+		t.Errorf("flags = %+v, want junior_daily_cap then same_level_ratio", flags)
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidAvailability окно доступности или правила попыток заданы неверно
+	ErrInvalidAvailability = errors.New("invalid availability settings")
+
+	ErrAssessmentNotOpen = errors.New("assessment is not open yet")
+	ErrAssessmentClosed  = errors.New("assessment is closed")
+	ErrAttemptsExhausted = errors.New("no attempts left")
+	ErrAttemptCooldown   = errors.New("next attempt is not available yet")
+)
+
+// Причины недоступности (AvailabilityStatus.Reason)
+const (
+	availabilityNotOpen   = "not_open"
+	availabilityClosed    = "closed"
+	availabilityExhausted = "attempts_exhausted"
+	availabilityCooldown  = "cooldown"
+)
+
+var availabilityReasons = map[string]error{
+	availabilityNotOpen:   ErrAssessmentNotOpen,
+	availabilityClosed:    ErrAssessmentClosed,
+	availabilityExhausted: ErrAttemptsExhausted,
+	availabilityCooldown:  ErrAttemptCooldown,
+}
+
+// AvailabilityError кандидат сейчас не может начать новую попытку; Status — подробности для ответа
+type AvailabilityError struct {
+	Status *models.AvailabilityStatus
+}
+
+func (e *AvailabilityError) Error() string {
+	w := e.Status.Window
+	switch e.Status.Reason {
+	case availabilityNotOpen:
+		return fmt.Sprintf("%v: opens at %s", ErrAssessmentNotOpen, formatInWindow(*w.OpensAt, w))
+	case availabilityClosed:
+		return fmt.Sprintf("%v: closed at %s", ErrAssessmentClosed, formatInWindow(*w.ClosesAt, w))
+	case availabilityExhausted:
+		return fmt.Sprintf("%v: %d of %d attempts used", ErrAttemptsExhausted, e.Status.AttemptsUsed, e.Status.MaxAttempts)
+	case availabilityCooldown:
+		return fmt.Sprintf("%v: available at %s", ErrAttemptCooldown, formatInWindow(*e.Status.NextAttemptAt, w))
+	}
+	return "assessment is not available"
+}
+
+func (e *AvailabilityError) Unwrap() error {
+	return availabilityReasons[e.Status.Reason]
+}
+
+// normalizeWindow проверяет окно и приводит границы к UTC; nil — окна нет
+func normalizeWindow(w *models.AvailabilityWindow) (models.AvailabilityWindow, error) {
+	if w == nil {
+		return models.AvailabilityWindow{}, nil
+	}
+	out := models.AvailabilityWindow{Timezone: w.Timezone}
+	if out.Timezone == "" {
+		out.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(out.Timezone); err != nil {
+		return out, fmt.Errorf("%w: unknown timezone %q", ErrInvalidAvailability, w.Timezone)
+	}
+	if w.OpensAt != nil {
+		t := w.OpensAt.UTC()
+		out.OpensAt = &t
+	}
+	if w.ClosesAt != nil {
+		t := w.ClosesAt.UTC()
+		out.ClosesAt = &t
+	}
+	if out.OpensAt != nil && out.ClosesAt != nil && !out.ClosesAt.After(*out.OpensAt) {
+		return out, fmt.Errorf("%w: closes_at must be after opens_at", ErrInvalidAvailability)
+	}
+	if !out.IsSet() {
+		return models.AvailabilityWindow{}, nil
+	}
+	return out, nil
+}
+
+// validateAttemptRules лимит попыток и пауза между ними
+func validateAttemptRules(maxAttempts, cooldown int) error {
+	if maxAttempts < 0 || cooldown < 0 {
+		return fmt.Errorf("%w: max_attempts and attempt_cooldown must not be negative", ErrInvalidAvailability)
+	}
+	return nil
+}
+
+// formatInWindow момент времени в поясе окна
+func formatInWindow(t time.Time, w models.AvailabilityWindow) string {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil || w.Timezone == "" {
+		loc = time.UTC
+	}
+	return t.In(loc).Format("2006-01-02 15:04 MST")
+}
+
+// attemptEnd когда попытка закончилась: завершённые — по CompletedAt, истёкшие — по последнему изменению
+func attemptEnd(s models.AssessmentSession) time.Time {
+	if s.CompletedAt != nil {
+		return *s.CompletedAt
+	}
+	return s.UpdatedAt
+}
+
+// ==========================
+// AVAILABILITY
+// ==========================
+
+// CheckAvailability может ли кандидат сейчас начать новую попытку и, если нет, когда сможет
+func (s *assessmentService) CheckAvailability(ctx context.Context, assessmentID, candidateID string) (*models.AvailabilityStatus, error) {
+	assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, assessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("assessment not found: %w", err)
+	}
+	return s.availability(ctx, assessment, candidateID, nil, time.Now())
+}
+
+// availability окно — персональное из приглашения кандидата, если задано, иначе окно оценки.
+// invitation == nil — берётся последнее приглашение кандидата на оценку.
+// Попыткой считается любая созданная сессия; пауза отсчитывается от конца последней.
+func (s *assessmentService) availability(ctx context.Context, assessment *models.Assessment, candidateID string, invitation *models.Invitation, now time.Time) (*models.AvailabilityStatus, error) {
+	status := &models.AvailabilityStatus{
+		AssessmentID: assessment.ID,
+		Window:       assessment.Availability,
+		MaxAttempts:  assessment.MaxAttempts,
+	}
+
+	if invitation == nil {
+		found, err := s.assessmentRepo.GetCandidateInvitation(ctx, assessment.ID, candidateID)
+		switch {
+		case err == nil:
+			// приглашение на тот же email, но принятое другим аккаунтом, не считается
+			if found.CandidateID == nil || *found.CandidateID == candidateID {
+				invitation = found
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, fmt.Errorf("load invitation failed: %w", err)
+		}
+	}
+	if invitation != nil {
+		status.InvitationID = invitation.ID
+		if invitation.Availability.IsSet() {
+			status.Window = invitation.Availability
+		}
+	}
+
+	sessions, err := s.assessmentRepo.ListCandidateSessions(ctx, assessment.ID, candidateID)
+	if err != nil {
+		return nil, fmt.Errorf("load attempts failed: %w", err)
+	}
+	status.AttemptsUsed = len(sessions)
+
+	w := status.Window
+	switch {
+	case w.OpensAt != nil && now.Before(*w.OpensAt):
+		status.Reason = availabilityNotOpen
+		status.NextAttemptAt = w.OpensAt
+	case w.ClosesAt != nil && !now.Before(*w.ClosesAt):
+		status.Reason = availabilityClosed
+	case assessment.MaxAttempts > 0 && len(sessions) >= assessment.MaxAttempts:
+		status.Reason = availabilityExhausted
+	case assessment.AttemptCooldown > 0 && len(sessions) > 0:
+		next := attemptEnd(sessions[0]).Add(time.Duration(assessment.AttemptCooldown) * time.Second)
+		if now.Before(next) {
+			status.Reason = availabilityCooldown
+			status.NextAttemptAt = &next
+		}
+	}
+	status.Available = status.Reason == ""
+	return status, nil
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidAssessmentTransition переход недопустим из текущего статуса
+	ErrInvalidAssessmentTransition = errors.New("status change is not allowed in the current assessment status")
+	// ErrAssessmentNotPublished сессии и приглашения только для опубликованных (active) оценок
+	ErrAssessmentNotPublished = errors.New("assessment is not published")
+	// ErrAssessmentLocked настройки оценки, влияющие на подсчёт, после публикации не меняются
+	ErrAssessmentLocked = errors.New("scoring settings of a published assessment cannot be changed")
+)
+
+// PublishValidationError оценку нельзя публиковать: в банке не хватает вопросов или настройки противоречивы
+type PublishValidationError struct {
+	Check *models.PublishCheck
+}
+
+func (e *PublishValidationError) Error() string {
+	return "assessment cannot be published: " + strings.Join(e.Check.Problems, "; ")
+}
+
+// assessmentTransitions допустимые переходы: draft → active (публикация) → archived.
+// Черновик можно архивировать сразу; вернуть опубликованную оценку в draft нельзя — для правок её копируют.
+var assessmentTransitions = map[models.AssessmentStatus][]models.AssessmentStatus{
+	models.AssessmentStatusDraft:  {models.AssessmentStatusActive, models.AssessmentStatusArchived},
+	models.AssessmentStatusActive: {models.AssessmentStatusArchived},
+}
+
+func canTransition(from, to models.AssessmentStatus) bool {
+	for _, next := range assessmentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ==========================
+// LIFECYCLE
+// ==========================
+
+// PublishCheck проверка перед публикацией: для каждой компетенции в банке должно быть
+// не меньше min_questions одобренных вопросов её уровня, а вопросы фиксированного набора — одобрены
+func (s *assessmentService) PublishCheck(ctx context.Context, id string) (*models.PublishCheck, error) {
+	assessment, err := s.assessmentRepo.GetAssessmentWithQuestions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("assessment not found: %w", err)
+	}
+	return s.publishCheck(ctx, assessment)
+}
+
+// publishCheck PublishCheck для уже загруженной оценки (с Questions), в том числе ещё не сохранённой
+func (s *assessmentService) publishCheck(ctx context.Context, assessment *models.Assessment) (*models.PublishCheck, error) {
+
+	check := &models.PublishCheck{
+		AssessmentID: assessment.ID,
+		Problems:     []string{},
+		Competencies: []models.CompetencyCoverage{},
+	}
+	if len(assessment.Competencies) == 0 {
+		check.Problems = append(check.Problems, "assessment has no competencies")
+	}
+
+	minTotal := 0
+	for _, c := range assessment.Competencies {
+		available, err := s.questionRepo.CountApprovedQuestions(ctx, c.CompetencyID, c.Level)
+		if err != nil {
+			return nil, fmt.Errorf("count questions failed: %w", err)
+		}
+		coverage := models.CompetencyCoverage{
+			CompetencyID: c.CompetencyID,
+			Level:        c.Level,
+			MinQuestions: c.MinQuestions,
+			Available:    available,
+			Enough:       available >= int64(c.MinQuestions),
+		}
+		if !coverage.Enough {
+			check.Problems = append(check.Problems, fmt.Sprintf("competency %s (%s): %d approved questions, at least %d required",
+				c.CompetencyID, c.Level, available, c.MinQuestions))
+		}
+		check.Competencies = append(check.Competencies, coverage)
+		minTotal += c.MinQuestions
+	}
+	if minTotal > assessment.TotalQuestions {
+		check.Problems = append(check.Problems, fmt.Sprintf("min_questions of competencies add up to %d, total_questions is %d",
+			minTotal, assessment.TotalQuestions))
+	}
+
+	// структура теста: в пуле компетенций оценки хватает вопросов каждого типа и уровня
+	if len(assessment.QuestionTypes) > 0 || len(assessment.LevelDistribution) > 0 {
+		competencies := make([]string, 0, len(assessment.Competencies))
+		for _, c := range assessment.Competencies {
+			competencies = append(competencies, c.CompetencyID)
+		}
+		for qt, n := range assessment.QuestionTypes {
+			available, err := s.questionRepo.CountApprovedQuestionsIn(ctx, competencies, string(qt), "")
+			if err != nil {
+				return nil, fmt.Errorf("count questions failed: %w", err)
+			}
+			if available < int64(n) {
+				check.Problems = append(check.Problems, fmt.Sprintf("question type %s: %d approved questions, %d required", qt, available, n))
+			}
+		}
+		for level, n := range assessment.LevelDistribution {
+			available, err := s.questionRepo.CountApprovedQuestionsIn(ctx, competencies, "", string(level))
+			if err != nil {
+				return nil, fmt.Errorf("count questions failed: %w", err)
+			}
+			if available < int64(n) {
+				check.Problems = append(check.Problems, fmt.Sprintf("level %s: %d approved questions, %d required", level, available, n))
+			}
+		}
+	}
+
+	if len(assessment.Questions) > 0 {
+		ids := make([]string, 0, len(assessment.Questions))
+		for _, q := range assessment.Questions {
+			ids = append(ids, q.QuestionID)
+		}
+		questions, err := s.questionRepo.GetQuestionsByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("load questions failed: %w", err)
+		}
+		approved := map[string]bool{}
+		for _, q := range questions {
+			approved[q.ID] = q.IsActive && q.ValidationStatus == models.ValidationStatusApproved
+		}
+		for _, id := range ids {
+			if !approved[id] {
+				check.Problems = append(check.Problems, fmt.Sprintf("question %s is not approved or was retired", id))
+			}
+		}
+	}
+
+	check.Ready = len(check.Problems) == 0
+	return check, nil
+}
+
+// PublishAssessment draft → active; после этого оценка принимает приглашения и сессии
+func (s *assessmentService) PublishAssessment(ctx context.Context, id string) (*models.Assessment, error) {
+	return s.changeStatus(ctx, id, models.AssessmentStatusActive)
+}
+
+// ArchiveAssessment → archived: новые сессии и приглашения не принимаются, начатые сессии можно завершить
+func (s *assessmentService) ArchiveAssessment(ctx context.Context, id string) (*models.Assessment, error) {
+	return s.changeStatus(ctx, id, models.AssessmentStatusArchived)
+}
+
+func (s *assessmentService) changeStatus(ctx context.Context, id string, to models.AssessmentStatus) (*models.Assessment, error) {
+	assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("assessment not found: %w", err)
+	}
+	from := assessment.Status
+	if from == "" {
+		from = models.AssessmentStatusDraft
+	}
+	if err := s.transitionCheck(ctx, assessment, to); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	assessment.Status = to
+	switch to {
+	case models.AssessmentStatusActive:
+		assessment.PublishedAt = &now
+	case models.AssessmentStatusArchived:
+		assessment.ArchivedAt = &now
+	}
+	if err := s.assessmentRepo.ChangeAssessmentStatus(ctx, assessment, from); err != nil {
+		if errors.Is(err, repository.ErrAssessmentStatusConflict) {
+			return nil, fmt.Errorf("%w: status was changed concurrently", ErrInvalidAssessmentTransition)
+		}
+		return nil, fmt.Errorf("change assessment status failed: %w", err)
+	}
+	return s.assessmentRepo.GetAssessmentByID(ctx, id)
+}
+
+// transitionCheck переход из текущего статуса в to допустим, а для публикации пройдена PublishCheck.
+// Фиксированный набор вопросов (Questions) подгружается, если его нет в assessment.
+func (s *assessmentService) transitionCheck(ctx context.Context, assessment *models.Assessment, to models.AssessmentStatus) error {
+	from := assessment.Status
+	if from == "" {
+		from = models.AssessmentStatusDraft
+	}
+	if !canTransition(from, to) {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidAssessmentTransition, from, to)
+	}
+	if to != models.AssessmentStatusActive {
+		return nil
+	}
+
+	candidate := *assessment
+	if candidate.Questions == nil {
+		stored, err := s.assessmentRepo.GetAssessmentWithQuestions(ctx, assessment.ID)
+		if err != nil {
+			return fmt.Errorf("assessment not found: %w", err)
+		}
+		candidate.Questions = stored.Questions
+	}
+	check, err := s.publishCheck(ctx, &candidate)
+	if err != nil {
+		return err
+	}
+	if !check.Ready {
+		return &PublishValidationError{Check: check}
+	}
+	return nil
+}
+
+// requirePublished оценка принимает приглашения и новые сессии
+func requirePublished(assessment *models.Assessment) error {
+	if assessment.Status != models.AssessmentStatusActive {
+		return ErrAssessmentNotPublished
+	}
+	return nil
+}
+
+// lockedFieldChanges поля запроса, которые меняют подсчёт или проверку результата у уже опубликованной
+// оценки: кандидаты одной оценки должны оцениваться одинаково. show_explanation, shuffle_questions,
+// описание, окно и правила попыток на баллы не влияют и остаются изменяемыми.
+// Повторная отправка текущих значений правкой не считается.
+func lockedFieldChanges(a *models.Assessment, req models.UpdateAssessmentRequest) []string {
+	var fields []string
+	if req.TimeLimit != nil && *req.TimeLimit != a.TimeLimit {
+		fields = append(fields, "time_limit")
+	}
+	if req.PassingScore != nil && *req.PassingScore != a.PassingScore {
+		fields = append(fields, "passing_score")
+	}
+	if req.ReviewersPerAnswer != nil && *req.ReviewersPerAnswer != a.ReviewersPerAnswer {
+		fields = append(fields, "reviewers_per_answer")
+	}
+	if req.DisagreementThreshold != nil && *req.DisagreementThreshold != a.DisagreementThreshold {
+		fields = append(fields, "disagreement_threshold")
+	}
+	// подсказка модели влияет на оценки экспертов
+	if req.AIGrading != nil && *req.AIGrading != a.AIGrading {
+		fields = append(fields, "ai_grading")
+	}
+	if req.CompetencyWeights != nil && !sameWeights(req.CompetencyWeights, a.CompetencyWeights) {
+		fields = append(fields, "competency_weights")
+	}
+	if req.QuestionTypes != nil && !sameStructure(req.QuestionTypes, a.QuestionTypes) {
+		fields = append(fields, "question_types")
+	}
+	if req.LevelDistribution != nil && !sameStructure(req.LevelDistribution, a.LevelDistribution) {
+		fields = append(fields, "level_distribution")
+	}
+	return fields
+}
+
+func sameWeights(a, b map[string]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+func sameStructure[K comparable](a, b map[K]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if n, ok := b[k]; !ok || n != v {
+			return false
+		}
+	}
+	return true
+}
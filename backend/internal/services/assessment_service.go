@@ -4,7 +4,10 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/easyhire/backend/internal/models"
@@ -18,22 +21,48 @@ type AssessmentService interface {
 	GetAssessment(ctx context.Context, id string) (*models.Assessment, error)
 	UpdateAssessment(ctx context.Context, id string, req models.UpdateAssessmentRequest) (*models.Assessment, error)
 	DeleteAssessment(ctx context.Context, id string) error
-	ListAssessments(ctx context.Context, filter repository.AssessmentFilter) ([]models.Assessment, error)
+	ListAssessments(ctx context.Context, filter repository.AssessmentFilter) (*models.AssessmentList, error)
+	CloneAssessment(ctx context.Context, id string, req models.CloneAssessmentRequest, createdBy string) (*models.Assessment, error)
+
+	// Lifecycle: draft → active (published) → archived
+	PublishCheck(ctx context.Context, id string) (*models.PublishCheck, error)
+	PublishAssessment(ctx context.Context, id string) (*models.Assessment, error)
+	ArchiveAssessment(ctx context.Context, id string) (*models.Assessment, error)
 
 	// Invitations
-	InviteCandidate(ctx context.Context, assessmentID, email, invitedBy string) (*models.Invitation, error)
-	GetInvitation(ctx context.Context, token string) (*models.Invitation, error)
+	InviteCandidate(ctx context.Context, assessmentID, email, invitedBy string, window *models.AvailabilityWindow) (*models.Invitation, error)
+
+	// Availability: window, attempt limit and cooldown; enforced in StartSession
+	CheckAvailability(ctx context.Context, assessmentID, candidateID string) (*models.AvailabilityStatus, error)
 
 	// Sessions
 	StartSession(ctx context.Context, assessmentID, candidateID string) (*models.AssessmentSession, error)
+	StartInvitationSession(ctx context.Context, invitation *models.Invitation) (*models.AssessmentSession, error)
 	GetSession(ctx context.Context, sessionID string) (*models.AssessmentSession, error)
 	SubmitAnswer(ctx context.Context, sessionID, questionID string, req models.CandidateAnswerRequest) error
 	CompleteSession(ctx context.Context, sessionID string) (*models.Result, error)
+	FinalizeSession(ctx context.Context, sessionID string) (*models.Result, error)
+	GetResultBreakdown(ctx context.Context, resultID string) (*models.ResultBreakdown, error)
+	GetAnswerDetails(ctx context.Context, sessionID string) ([]models.AnswerDetail, error)
+
+	// Edit history (coding answers)
+	RecordEdits(ctx context.Context, sessionID, questionID string, req models.EditHistoryRequest) error
+	ReplayAnswer(ctx context.Context, sessionID, questionID string, at int64) (*models.CodeReplay, error)
+
+	// Question timing
+	ServeQuestion(ctx context.Context, sessionID, questionID string) (*models.ServedQuestion, error)
 }
 
+// ErrSessionPendingReview сессия завершена кандидатом, но часть ответов ждёт ручной проверки
+var ErrSessionPendingReview = errors.New("session is pending manual review")
+
 type assessmentService struct {
 	assessmentRepo repository.AssessmentRepository
 	questionRepo   repository.QuestionRepository
+	reviewRepo     repository.ReviewRepository
+	scoringService ScoringService
+	resultService  ResultService
+	executor       ExecutorClient
 	emailService   *EmailService
 	db             *gorm.DB
 }
@@ -41,11 +70,19 @@ type assessmentService struct {
 func NewAssessmentService(
 	assessmentRepo repository.AssessmentRepository,
 	questionRepo repository.QuestionRepository,
+	reviewRepo repository.ReviewRepository,
+	scoringService ScoringService,
+	resultService ResultService,
+	executorClient ExecutorClient,
 	db *gorm.DB,
 ) AssessmentService {
 	return &assessmentService{
 		assessmentRepo: assessmentRepo,
 		questionRepo:   questionRepo,
+		reviewRepo:     reviewRepo,
+		scoringService: scoringService,
+		resultService:  resultService,
+		executor:       executorClient,
 		emailService:   NewEmailService(),
 		db:             db,
 	}
@@ -65,6 +102,16 @@ func (s *assessmentService) CreateAssessment(ctx context.Context, req models.Cre
 	if len(req.Competencies) == 0 {
 		return nil, fmt.Errorf("at least one competency is required")
 	}
+	window, err := normalizeWindow(req.Availability)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAttemptRules(req.MaxAttempts, req.AttemptCooldown); err != nil {
+		return nil, err
+	}
+	if errs := structureErrors(req.TotalQuestions, req.QuestionTypes, req.LevelDistribution); len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "; "))
+	}
 
 	assessment := &models.Assessment{
 		Title:            req.Title,
@@ -78,12 +125,29 @@ func (s *assessmentService) CreateAssessment(ctx context.Context, req models.Cre
 		ShowExplanation:  req.ShowExplanation,
 		CreatedBy:        createdBy,
 		Status:           models.AssessmentStatus("draft"),
+
+		ReviewersPerAnswer:    req.ReviewersPerAnswer,
+		DisagreementThreshold: req.DisagreementThreshold,
+		AIGrading:             req.AIGrading,
+		CompetencyWeights:     req.CompetencyWeights,
+		QuestionTypes:         req.QuestionTypes,
+		LevelDistribution:     req.LevelDistribution,
+
+		Availability:    window,
+		MaxAttempts:     req.MaxAttempts,
+		AttemptCooldown: req.AttemptCooldown,
+	}
+	if assessment.ReviewersPerAnswer <= 0 {
+		assessment.ReviewersPerAnswer = 1
+	}
+	if assessment.DisagreementThreshold <= 0 {
+		assessment.DisagreementThreshold = defaultDisagreementThreshold
 	}
 
 	// One transaction: assessment + competencies
 	returnAssessment := (*models.Assessment)(nil)
 
-	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Use repo via tx? simplest: direct create for assessment, then repo methods for others are on base DB.
 		// To keep it consistent, we create assessment with tx directly.
 		if err := tx.Create(assessment).Error; err != nil {
@@ -117,6 +181,12 @@ func (s *assessmentService) CreateAssessment(ctx context.Context, req models.Cre
 			}
 		}
 
+		if len(req.Tags) > 0 {
+			if err := repository.NewAssessmentRepository(tx).SetAssessmentTags(ctx, assessment.ID, req.Tags); err != nil {
+				return fmt.Errorf("save tags failed: %w", err)
+			}
+		}
+
 		// Reload with preloads
 		var out models.Assessment
 		if err := tx.Preload("Competencies").Preload("Tags").First(&out, "id = ?", assessment.ID).Error; err != nil {
@@ -136,11 +206,18 @@ func (s *assessmentService) GetAssessment(ctx context.Context, id string) (*mode
 	return s.assessmentRepo.GetAssessmentByID(ctx, id)
 }
 
+// UpdateAssessment правка настроек. У опубликованной и архивной оценки настройки подсчёта заморожены;
+// смена статуса идёт через те же переходы, что и /publish, /archive.
 func (s *assessmentService) UpdateAssessment(ctx context.Context, id string, req models.UpdateAssessmentRequest) (*models.Assessment, error) {
 	assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("assessment not found: %w", err)
 	}
+	if assessment.Status != models.AssessmentStatusDraft && assessment.Status != "" {
+		if fields := lockedFieldChanges(assessment, req); len(fields) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrAssessmentLocked, strings.Join(fields, ", "))
+		}
+	}
 
 	if req.Title != nil {
 		assessment.Title = *req.Title
@@ -148,9 +225,6 @@ func (s *assessmentService) UpdateAssessment(ctx context.Context, id string, req
 	if req.Description != nil {
 		assessment.Description = *req.Description
 	}
-	if req.Status != nil {
-		assessment.Status = *req.Status
-	}
 	if req.TimeLimit != nil {
 		assessment.TimeLimit = *req.TimeLimit
 	}
@@ -163,10 +237,65 @@ func (s *assessmentService) UpdateAssessment(ctx context.Context, id string, req
 	if req.ShowExplanation != nil {
 		assessment.ShowExplanation = *req.ShowExplanation
 	}
+	if req.ReviewersPerAnswer != nil {
+		assessment.ReviewersPerAnswer = *req.ReviewersPerAnswer
+	}
+	if req.DisagreementThreshold != nil {
+		assessment.DisagreementThreshold = *req.DisagreementThreshold
+	}
+	if req.AIGrading != nil {
+		assessment.AIGrading = *req.AIGrading
+	}
+	if req.CompetencyWeights != nil {
+		assessment.CompetencyWeights = req.CompetencyWeights
+	}
+	if req.QuestionTypes != nil || req.LevelDistribution != nil {
+		if req.QuestionTypes != nil {
+			assessment.QuestionTypes = req.QuestionTypes
+		}
+		if req.LevelDistribution != nil {
+			assessment.LevelDistribution = req.LevelDistribution
+		}
+		if errs := structureErrors(assessment.TotalQuestions, assessment.QuestionTypes, assessment.LevelDistribution); len(errs) > 0 {
+			return nil, errors.New(strings.Join(errs, "; "))
+		}
+	}
+	// окно и правила попыток можно менять и у опубликованной оценки — например, продлить набор
+	if req.Availability != nil {
+		window, err := normalizeWindow(req.Availability)
+		if err != nil {
+			return nil, err
+		}
+		assessment.Availability = window
+	}
+	if req.MaxAttempts != nil {
+		assessment.MaxAttempts = *req.MaxAttempts
+	}
+	if req.AttemptCooldown != nil {
+		assessment.AttemptCooldown = *req.AttemptCooldown
+	}
+	if err := validateAttemptRules(assessment.MaxAttempts, assessment.AttemptCooldown); err != nil {
+		return nil, err
+	}
+	// переход проверяется до сохранения: отклонённая публикация не должна оставлять правки в базе
+	if req.Status != nil && *req.Status != assessment.Status {
+		if err := s.transitionCheck(ctx, assessment, *req.Status); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.assessmentRepo.UpdateAssessment(ctx, assessment); err != nil {
 		return nil, fmt.Errorf("update assessment failed: %w", err)
 	}
+	// теги не влияют на подсчёт и меняются в любом статусе
+	if req.Tags != nil {
+		if err := s.assessmentRepo.SetAssessmentTags(ctx, id, *req.Tags); err != nil {
+			return nil, fmt.Errorf("update tags failed: %w", err)
+		}
+	}
+	if req.Status != nil && *req.Status != assessment.Status {
+		return s.changeStatus(ctx, id, *req.Status)
+	}
 	return s.assessmentRepo.GetAssessmentByID(ctx, id)
 }
 
@@ -174,16 +303,25 @@ func (s *assessmentService) DeleteAssessment(ctx context.Context, id string) err
 	return s.assessmentRepo.DeleteAssessment(ctx, id)
 }
 
-func (s *assessmentService) ListAssessments(ctx context.Context, filter repository.AssessmentFilter) ([]models.Assessment, error) {
-	list, _, err := s.assessmentRepo.ListAssessments(ctx, filter)
-	return list, err
+// ListAssessments страница списка с общим числом оценок под фильтром
+func (s *assessmentService) ListAssessments(ctx context.Context, filter repository.AssessmentFilter) (*models.AssessmentList, error) {
+	list, total, next, err := s.assessmentRepo.ListAssessments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list assessments failed: %w", err)
+	}
+	if list == nil {
+		list = []models.Assessment{}
+	}
+	return &models.AssessmentList{Assessments: list, Total: total, Limit: filter.Limit, NextCursor: next}, nil
 }
 
 // ==========================
 // INVITATIONS
 // ==========================
 
-func (s *assessmentService) InviteCandidate(ctx context.Context, assessmentID, email, invitedBy string) (*models.Invitation, error) {
+// InviteCandidate приглашение на опубликованную оценку. Персональное окно заменяет окно оценки
+// для этого кандидата; приглашение истекает вместе с окном.
+func (s *assessmentService) InviteCandidate(ctx context.Context, assessmentID, email, invitedBy string, window *models.AvailabilityWindow) (*models.Invitation, error) {
 	if assessmentID == "" {
 		return nil, fmt.Errorf("assessment_id is required")
 	}
@@ -194,11 +332,18 @@ func (s *assessmentService) InviteCandidate(ctx context.Context, assessmentID, e
 		return nil, fmt.Errorf("invited_by is required")
 	}
 
-	// Ensure assessment exists
+	// Ensure assessment exists and is published
 	assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, assessmentID)
 	if err != nil {
 		return nil, fmt.Errorf("assessment not found: %w", err)
 	}
+	if err := requirePublished(assessment); err != nil {
+		return nil, err
+	}
+	personal, err := normalizeWindow(window)
+	if err != nil {
+		return nil, err
+	}
 
 	token := generateInvitationToken()
 
@@ -210,6 +355,10 @@ func (s *assessmentService) InviteCandidate(ctx context.Context, assessmentID, e
 		Status:       models.InvitationStatus("pending"),
 		InvitedBy:    invitedBy,
 		ExpiresAt:    time.Now().Add(7 * 24 * time.Hour),
+		Availability: personal,
+	}
+	if personal.ClosesAt != nil {
+		inv.ExpiresAt = *personal.ClosesAt
 	}
 
 	if err := s.assessmentRepo.CreateInvitation(ctx, inv); err != nil {
@@ -221,42 +370,89 @@ func (s *assessmentService) InviteCandidate(ctx context.Context, assessmentID, e
 	return inv, nil
 }
 
-func (s *assessmentService) GetInvitation(ctx context.Context, token string) (*models.Invitation, error) {
-	return s.assessmentRepo.GetInvitationByToken(ctx, token)
-}
-
 // ==========================
 // SESSIONS
 // ==========================
 
+// StartSession новая попытка кандидата (или уже идущая). Приглашение кандидата на оценку, если оно есть,
+// привязывается к сессии и переходит в accepted.
 func (s *assessmentService) StartSession(ctx context.Context, assessmentID, candidateID string) (*models.AssessmentSession, error) {
+	return s.startSession(ctx, assessmentID, candidateID, nil)
+}
+
+// StartInvitationSession сессия по принятому приглашению — для кандидата, привязанного к нему
+func (s *assessmentService) StartInvitationSession(ctx context.Context, invitation *models.Invitation) (*models.AssessmentSession, error) {
+	if invitation.CandidateID == nil {
+		return nil, fmt.Errorf("invitation is not linked to a candidate")
+	}
+	return s.startSession(ctx, invitation.AssessmentID, *invitation.CandidateID, invitation)
+}
+
+// startSession invitation == nil — приглашение ищется по кандидату
+func (s *assessmentService) startSession(ctx context.Context, assessmentID, candidateID string, invitation *models.Invitation) (*models.AssessmentSession, error) {
 	if assessmentID == "" || candidateID == "" {
 		return nil, fmt.Errorf("assessment_id and candidate_id are required")
 	}
 
 	// Ensure assessment exists
-	_, err := s.assessmentRepo.GetAssessmentByID(ctx, assessmentID)
+	assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, assessmentID)
 	if err != nil {
 		return nil, fmt.Errorf("assessment not found: %w", err)
 	}
 
-	// If there's an active session - return it
-	active, err := s.assessmentRepo.GetActiveSession(ctx, assessmentID, candidateID)
-	if err == nil && active != nil {
-		return active, nil
-	}
+	// Checks and creation run under a per-candidate lock: otherwise two parallel starts
+	// both pass the attempt/cooldown rules and both create a session
+	var session *models.AssessmentSession
+	var created bool
+	var now time.Time
+	err = s.assessmentRepo.WithAttemptLock(ctx, assessmentID, candidateID, func(repo repository.AssessmentRepository) error {
+		// If there's an active session - return it (even if the assessment was archived meanwhile)
+		active, err := repo.GetActiveSession(ctx, assessmentID, candidateID)
+		if err == nil && active != nil {
+			if invitation != nil && active.InvitationID == nil {
+				active.InvitationID = &invitation.ID
+				if err := repo.UpdateSession(ctx, active); err != nil {
+					return fmt.Errorf("update session failed: %w", err)
+				}
+			}
+			session = active
+			return nil
+		}
 
-	now := time.Now()
-	session := &models.AssessmentSession{
-		AssessmentID: assessmentID,
-		CandidateID:  candidateID,
-		Status:       models.SessionStatus("in_progress"),
-		StartedAt:    &now,
-		TimeSpent:    0,
-	}
+		// New sessions only for published assessments, inside the window and within attempt rules
+		if err := requirePublished(assessment); err != nil {
+			return err
+		}
+		now = time.Now()
+		availability, err := s.availability(ctx, assessment, candidateID, invitation, now)
+		if err != nil {
+			return err
+		}
+		if !availability.Available {
+			return &AvailabilityError{Status: availability}
+		}
 
-	if err := s.assessmentRepo.CreateSession(ctx, session); err != nil {
-		return nil, fmt.Errorf("create session failed: %w", err)
+		session = &models.AssessmentSession{
+			AssessmentID: assessmentID,
+			CandidateID:  candidateID,
+			Status:       models.SessionStatus("in_progress"),
+			StartedAt:    &now,
+			TimeSpent:    0,
+		}
+		if availability.InvitationID != "" {
+			session.InvitationID = &availability.InvitationID
+		}
+		if err := repo.CreateSession(ctx, session); err != nil {
+			return fmt.Errorf("create session failed: %w", err)
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		s.acceptInvitation(ctx, session, now)
 	}
 	return session, nil
 }
@@ -274,31 +470,41 @@ func (s *assessmentService) SubmitAnswer(ctx context.Context, sessionID, questio
 	if err != nil {
 		return fmt.Errorf("session not found: %w", err)
 	}
-	if string(session.Status) == "completed" {
-		return fmt.Errorf("session already completed")
+	// сданная на проверку или завершённая сессия ответов не принимает
+	if session.Status != models.SessionStatusInProgress {
+		return ErrSessionNotInProgress
+	}
+
+	// ответ принимается только на вопрос оценки, по которой идёт сессия
+	question, err := s.sessionQuestion(ctx, session, questionID)
+	if err != nil {
+		return err
 	}
 
 	now := time.Now()
+	bonuses := activeTimeBonuses(ctx, s.scoringService)
 
-	// Upsert by (session_id, question_id) if repository supports it
+	// Upsert by (session_id, question_id); StartedAt is set when the question is served
 	existing, err := s.assessmentRepo.GetAnswer(ctx, sessionID, questionID)
 	if err == nil && existing != nil {
 		existing.Answer = req.Answer
 		existing.Code = req.Code
-		existing.TimeSpent = req.TimeSpent
+		existing.ClientTimeSpent = req.TimeSpent
 		existing.SubmittedAt = &now
+		measureAnswerTime(existing, question, now, bonuses)
 		return s.assessmentRepo.UpdateAnswer(ctx, existing)
 	}
 
 	ans := &models.CandidateAnswer{
-		SessionID:   sessionID,
-		QuestionID:  questionID,
-		Answer:      req.Answer,
-		Code:        req.Code,
-		TimeSpent:   req.TimeSpent,
-		StartedAt:   now, // MVP: real StartedAt can be tracked by client
-		SubmittedAt: &now,
-	}
+		SessionID:       sessionID,
+		QuestionID:      questionID,
+		Answer:          req.Answer,
+		Code:            req.Code,
+		ClientTimeSpent: req.TimeSpent,
+		StartedAt:       s.implicitServeTime(ctx, session, now),
+		SubmittedAt:     &now,
+	}
+	measureAnswerTime(ans, question, now, bonuses)
 	return s.assessmentRepo.SaveAnswer(ctx, ans)
 }
 
@@ -315,45 +521,312 @@ func (s *assessmentService) CompleteSession(ctx context.Context, sessionID strin
 			return existing, nil
 		}
 	}
+	if session.Status == models.SessionStatusPendingReview {
+		return nil, ErrSessionPendingReview
+	}
+	if session.Status != models.SessionStatusInProgress {
+		return nil, ErrSessionNotInProgress
+	}
 
-	answers, _ := s.assessmentRepo.GetSessionAnswers(ctx, sessionID)
+	// Сессия переводится в pending_review до проверки ответов: параллельный вызов
+	// не создаст второй комплект проверок
+	if err := s.assessmentRepo.ChangeSessionStatus(ctx, sessionID, models.SessionStatusInProgress, models.SessionStatusPendingReview); err != nil {
+		if errors.Is(err, repository.ErrSessionStatusConflict) {
+			return nil, ErrSessionPendingReview
+		}
+		return nil, fmt.Errorf("update session failed: %w", err)
+	}
+	session.Status = models.SessionStatusPendingReview
 
-	timeSpent := 0
-	for _, a := range answers {
-		timeSpent += a.TimeSpent
+	result, reviews, err := s.gradeSession(ctx, session)
+	if err != nil && reviews == 0 {
+		// ни одной проверки не создано — кандидат может завершить сессию ещё раз
+		if err := s.assessmentRepo.ChangeSessionStatus(ctx, sessionID, models.SessionStatusPendingReview, models.SessionStatusInProgress); err != nil {
+			log.Printf("⚠️ reopen session failed: %v (session %s)", err, sessionID)
+		}
 	}
+	return result, err
+}
 
-	now := time.Now()
+// gradeSession проверяет ответы уже закрытой сессии: автоматически — сразу, остальные отдаёт экспертам.
+// Без ручных проверок сразу считает результат. reviews — сколько проверок создано.
+func (s *assessmentService) gradeSession(ctx context.Context, session *models.AssessmentSession) (*models.Result, int, error) {
+	sessionID := session.ID
+	assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, session.AssessmentID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("assessment not found: %w", err)
+	}
+	reviewersPerAnswer := assessment.ReviewersPerAnswer
+	if reviewersPerAnswer <= 0 {
+		reviewersPerAnswer = 1
+	}
 
-	// MVP scoring: пока без code-exec (#10) и автоматической проверки,
-	// оставляем score/percentage = 0, но фиксируем completion и время.
-	result := &models.Result{
-		SessionID:   sessionID,
-		TotalScore:  0,
-		Percentage:  0,
-		Level:       "TRAINEE",
-		TimeSpent:   timeSpent,
-		CompletedAt: now,
+	answers, _ := s.assessmentRepo.GetSessionAnswers(ctx, sessionID)
+	questions, err := s.questionRepo.GetQuestionsByIDs(ctx, answerQuestionIDs(answers))
+	if err != nil {
+		return nil, 0, fmt.Errorf("load questions failed: %w", err)
+	}
+	byID := make(map[string]models.Question, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
 	}
 
-	if err := s.assessmentRepo.CreateResult(ctx, result); err != nil {
-		return nil, fmt.Errorf("create result failed: %w", err)
+	// Auto-grade what we can, send the rest to manual review
+	now := time.Now()
+	reviews := 0
+	for i := range answers {
+		q, ok := byID[answers[i].QuestionID]
+		if !ok {
+			continue
+		}
+		if answers[i].SubmittedAt == nil {
+			// served but never answered - no credit, nothing to review
+			answers[i].IsCorrect = false
+			answers[i].Score = 0
+			if err := s.assessmentRepo.UpdateAnswer(ctx, &answers[i]); err != nil {
+				return nil, reviews, fmt.Errorf("update answer failed: %w", err)
+			}
+			continue
+		}
+		manual := requiresManualReview(q)
+		if !manual && q.Type == models.QuestionTypeCoding {
+			// без тест-кейсов или при недоступном executor решение проверяют эксперты
+			graded, err := gradeCoding(ctx, s.executor, &answers[i], q)
+			if err != nil {
+				log.Printf("⚠️ grade coding answer failed: %v (answer %s)", err, answers[i].ID)
+			}
+			manual = !graded
+		} else if !manual {
+			autoGrade(&answers[i], q)
+		}
+		if manual {
+			// N blind reviews per answer, each handed to a different expert if possible.
+			// Each review is saved right away so the next pick sees it in the expert's load.
+			var taken []string
+			for n := 0; n < reviewersPerAnswer; n++ {
+				review := models.AnswerReview{
+					Kind:       models.ReviewKindReview,
+					AnswerID:   answers[i].ID,
+					SessionID:  sessionID,
+					QuestionID: q.ID,
+					Status:     models.ReviewStatusPending,
+					MaxScore:   float64(q.Points),
+				}
+				if reviewerID, err := s.reviewRepo.PickLeastLoadedExpert(ctx, taken); err == nil {
+					taken = append(taken, reviewerID)
+					review.ReviewerID = &reviewerID
+					review.Status = models.ReviewStatusAssigned
+					review.AssignedAt = &now
+				}
+				if err := s.reviewRepo.CreateReviews(ctx, []models.AnswerReview{review}); err != nil {
+					return nil, reviews, fmt.Errorf("create reviews failed: %w", err)
+				}
+				reviews++
+			}
+			continue
+		}
+		if err := s.assessmentRepo.UpdateAnswer(ctx, &answers[i]); err != nil {
+			return nil, reviews, fmt.Errorf("update answer failed: %w", err)
+		}
 	}
 
-	session.Status = models.SessionStatus("completed")
-	session.CompletedAt = &now
-	session.TimeSpent = timeSpent
-	session.Score = result.TotalScore
-	session.Percentage = result.Percentage
-	session.Level = result.Level
+	if reviews == 0 {
+		result, err := s.FinalizeSession(ctx, sessionID)
+		return result, 0, err
+	}
 
+	session.Status = models.SessionStatusPendingReview
+	session.CompletedAt = &now
+	session.TimeSpent = sumTimeSpent(answers)
 	if err := s.assessmentRepo.UpdateSession(ctx, session); err != nil {
-		return nil, fmt.Errorf("update session failed: %w", err)
+		return nil, reviews, fmt.Errorf("update session failed: %w", err)
+	}
+	// кандидат своё сделал — приглашение выполнено, хотя результат ждёт проверки
+	s.completeInvitation(ctx, session, now)
+
+	return nil, reviews, ErrSessionPendingReview
+}
+
+// FinalizeSession считает итоговый результат по уже проверенным ответам.
+// Вызывается сразу из CompleteSession либо после последней ручной проверки. Строка сессии
+// блокируется на время подсчёта; у уже завершённой сессии возвращается сохранённый результат.
+func (s *assessmentService) FinalizeSession(ctx context.Context, sessionID string) (*models.Result, error) {
+	var session *models.AssessmentSession
+	var result *models.Result
+	finalized := false
+	now := time.Now()
+
+	err := s.assessmentRepo.WithSessionLock(ctx, sessionID, func(repo repository.AssessmentRepository, locked *models.AssessmentSession) error {
+		session = locked
+		if session.Status == models.SessionStatusCompleted {
+			existing, err := repo.GetResultBySessionID(ctx, sessionID)
+			if err != nil {
+				return fmt.Errorf("load result failed: %w", err)
+			}
+			result = existing
+			return nil
+		}
+
+		answers, err := repo.GetSessionAnswers(ctx, sessionID)
+		if err != nil {
+			return fmt.Errorf("load answers failed: %w", err)
+		}
+		questions, err := s.questionRepo.GetQuestionsByIDs(ctx, answerQuestionIDs(answers))
+		if err != nil {
+			return fmt.Errorf("load questions failed: %w", err)
+		}
+
+		assessment, err := repo.GetAssessmentByID(ctx, session.AssessmentID)
+		if err != nil {
+			return fmt.Errorf("assessment not found: %w", err)
+		}
+		cfg, cfgVersion, err := s.scoringService.EffectiveConfig(ctx, assessment)
+		if err != nil {
+			return fmt.Errorf("load scoring config failed: %w", err)
+		}
+
+		history, err := s.scoringHistory(ctx, session.CandidateID, now)
+		if err != nil {
+			return err
+		}
+
+		score, err := s.scoringService.CalculateFinalScore(answers, questions, cfg, history)
+		if err != nil {
+			return fmt.Errorf("scoring failed: %w", err)
+		}
+
+		timeSpent := sumTimeSpent(answers)
+
+		result = &models.Result{
+			SessionID:   sessionID,
+			TotalScore:  score.TotalScore,
+			Percentage:  score.Percentage,
+			Level:       score.Level,
+			TimeSpent:   timeSpent,
+			CompletedAt: now,
+
+			CompetencyBreakdown:  score.CompetencyBreakdown,
+			ScoringConfigVersion: cfgVersion,
+			CompetencyWeights:    cloneWeights(assessment.CompetencyWeights),
+			LevelExplanation:     score.LevelExplanation,
+			LevelScores:          score.LevelScores,
+			AntiFarmingFlags:     score.AntiFarmingFlags,
+			FarmingCapped:        len(score.AntiFarmingFlags) > 0,
+		}
+		result.Feedback = buildFeedback(result, assessment.TargetLevel, answers, questions)
+		result.FeedbackGeneratedAt = &now
+
+		if err := repo.CreateResult(ctx, result); err != nil {
+			return fmt.Errorf("create result failed: %w", err)
+		}
+
+		session.Status = models.SessionStatusCompleted
+		if session.CompletedAt == nil {
+			session.CompletedAt = &now
+		}
+		session.TimeSpent = timeSpent
+		session.Score = result.TotalScore
+		session.Percentage = result.Percentage
+		session.Level = result.Level
+
+		if err := repo.UpdateSession(ctx, session); err != nil {
+			return fmt.Errorf("update session failed: %w", err)
+		}
+		finalized = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !finalized {
+		return result, nil
+	}
+	s.completeInvitation(ctx, session, now)
+
+	// Распределение оценки; при ошибке результат будет учтён при первом запросе standing
+	if err := s.resultService.RecordResult(ctx, result, session.AssessmentID); err != nil {
+		log.Printf("⚠️ %v (result %s)", err, result.ID)
 	}
 
 	return result, nil
 }
 
+// scoringHistory что кандидат набрал раньше в те же сутки (UTC) — для дневного лимита junior-баллов
+func (s *assessmentService) scoringHistory(ctx context.Context, candidateID string, at time.Time) (models.ScoringHistory, error) {
+	at = at.UTC()
+	dayStart := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	junior, err := s.assessmentRepo.SumCandidateLevelScore(ctx, candidateID, string(models.DifficultyJunior), dayStart, at)
+	if err != nil {
+		return models.ScoringHistory{}, fmt.Errorf("load scoring history failed: %w", err)
+	}
+	return models.ScoringHistory{JuniorScoreToday: junior}, nil
+}
+
+// GetAnswerDetails ответы сессии с правильными ответами и баллами по критериям
+func (s *assessmentService) GetAnswerDetails(ctx context.Context, sessionID string) ([]models.AnswerDetail, error) {
+	answers, err := s.assessmentRepo.GetSessionAnswers(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("load answers failed: %w", err)
+	}
+	questions, err := s.questionRepo.GetQuestionsByIDs(ctx, answerQuestionIDs(answers))
+	if err != nil {
+		return nil, fmt.Errorf("load questions failed: %w", err)
+	}
+	byID := make(map[string]models.Question, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+
+	details := make([]models.AnswerDetail, 0, len(answers))
+	for _, a := range answers {
+		q := byID[a.QuestionID]
+		answer := a.Answer
+		if a.Code != "" {
+			answer = a.Code
+		}
+		details = append(details, models.AnswerDetail{
+			QuestionID:      a.QuestionID,
+			QuestionTitle:   q.Title,
+			QuestionType:    string(q.Type),
+			Answer:          answer,
+			CorrectAnswer:   correctAnswerText(q),
+			IsCorrect:       a.IsCorrect,
+			PointsEarned:    a.Score,
+			TimeSpent:       a.TimeSpent,
+			Explanation:     q.Explanation,
+			CriterionScores: a.CriterionScores,
+		})
+	}
+	return details, nil
+}
+
+func correctAnswerText(q models.Question) string {
+	var correct []string
+	for _, opt := range q.Options {
+		if opt.IsCorrect {
+			correct = append(correct, opt.Text)
+		}
+	}
+	return strings.Join(correct, "; ")
+}
+
+func answerQuestionIDs(answers []models.CandidateAnswer) []string {
+	ids := make([]string, 0, len(answers))
+	for _, a := range answers {
+		ids = append(ids, a.QuestionID)
+	}
+	return ids
+}
+
+func sumTimeSpent(answers []models.CandidateAnswer) int {
+	total := 0
+	for _, a := range answers {
+		total += a.TimeSpent
+	}
+	return total
+}
+
 // 32 hex chars token
 func generateInvitationToken() string {
 	b := make([]byte, 16)
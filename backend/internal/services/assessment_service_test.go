@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyhire/backend/internal/executor"
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+)
+
+// fakeAssessmentRepo сессия с ответами в памяти; методы, которые тест не ожидает, паникуют
+type fakeAssessmentRepo struct {
+	repository.AssessmentRepository
+	assessment *models.Assessment
+	session    *models.AssessmentSession
+	stale      *models.AssessmentSession // что видит GetSessionByID, если сессию успели изменить
+	answers    []models.CandidateAnswer
+	result     *models.Result
+	edits      []models.AnswerEditBatch
+}
+
+func (r *fakeAssessmentRepo) GetAssessmentByID(ctx context.Context, id string) (*models.Assessment, error) {
+	a := *r.assessment
+	return &a, nil
+}
+
+func (r *fakeAssessmentRepo) GetAssessmentWithQuestions(ctx context.Context, id string) (*models.Assessment, error) {
+	return r.GetAssessmentByID(ctx, id)
+}
+
+func (r *fakeAssessmentRepo) GetSessionByID(ctx context.Context, id string) (*models.AssessmentSession, error) {
+	s := *r.session
+	if r.stale != nil {
+		s = *r.stale
+	}
+	return &s, nil
+}
+
+func (r *fakeAssessmentRepo) ChangeSessionStatus(ctx context.Context, sessionID string, from, to models.SessionStatus) error {
+	if r.session.Status != from {
+		return repository.ErrSessionStatusConflict
+	}
+	r.session.Status = to
+	return nil
+}
+
+func (r *fakeAssessmentRepo) WithSessionLock(ctx context.Context, sessionID string, fn func(repo repository.AssessmentRepository, session *models.AssessmentSession) error) error {
+	s := *r.session
+	return fn(r, &s)
+}
+
+func (r *fakeAssessmentRepo) UpdateSession(ctx context.Context, session *models.AssessmentSession) error {
+	s := *session
+	r.session = &s
+	return nil
+}
+
+func (r *fakeAssessmentRepo) GetSessionAnswers(ctx context.Context, sessionID string) ([]models.CandidateAnswer, error) {
+	return append([]models.CandidateAnswer(nil), r.answers...), nil
+}
+
+func (r *fakeAssessmentRepo) UpdateAnswer(ctx context.Context, answer *models.CandidateAnswer) error {
+	for i := range r.answers {
+		if r.answers[i].ID == answer.ID {
+			r.answers[i] = *answer
+		}
+	}
+	return nil
+}
+
+func (r *fakeAssessmentRepo) CreateResult(ctx context.Context, result *models.Result) error {
+	if r.result != nil {
+		return errors.New("duplicate key value violates unique constraint on results.session_id")
+	}
+	r.result = result
+	return nil
+}
+
+func (r *fakeAssessmentRepo) GetResultBySessionID(ctx context.Context, sessionID string) (*models.Result, error) {
+	if r.result == nil {
+		return nil, errors.New("record not found")
+	}
+	return r.result, nil
+}
+
+func (r *fakeAssessmentRepo) SaveEditBatch(ctx context.Context, batch *models.AnswerEditBatch) error {
+	r.edits = append(r.edits, *batch)
+	return nil
+}
+
+func (r *fakeAssessmentRepo) SumCandidateLevelScore(ctx context.Context, candidateID, level string, from, to time.Time) (float64, error) {
+	return 0, nil
+}
+
+type fakeQuestionRepo struct {
+	repository.QuestionRepository
+	questions []models.Question
+}
+
+func (r *fakeQuestionRepo) GetQuestionByID(ctx context.Context, id string) (*models.Question, error) {
+	for i := range r.questions {
+		if r.questions[i].ID == id {
+			q := r.questions[i]
+			return &q, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeQuestionRepo) GetQuestionsByIDs(ctx context.Context, ids []string) ([]models.Question, error) {
+	return r.questions, nil
+}
+
+type fakeReviewRepo struct {
+	repository.ReviewRepository
+	reviews []models.AnswerReview
+}
+
+func (r *fakeReviewRepo) PickLeastLoadedExpert(ctx context.Context, exclude []string) (string, error) {
+	return "expert-1", nil
+}
+
+func (r *fakeReviewRepo) CreateReviews(ctx context.Context, reviews []models.AnswerReview) error {
+	r.reviews = append(r.reviews, reviews...)
+	return nil
+}
+
+// fakeScoring формула по умолчанию без anti-farming
+type fakeScoring struct {
+	ScoringService
+}
+
+func (fakeScoring) EffectiveConfig(ctx context.Context, assessment *models.Assessment) (models.ScoringConfig, int, error) {
+	cfg := models.DefaultScoringConfig()
+	cfg.AntiFarming = models.AntiFarmingConfig{}
+	return cfg, 1, nil
+}
+
+func (fakeScoring) CalculateFinalScore(answers []models.CandidateAnswer, questions []models.Question, cfg models.ScoringConfig, history models.ScoringHistory) (*models.ScoreResult, error) {
+	return (&scoringService{}).CalculateFinalScore(answers, questions, cfg, history)
+}
+
+type fakeResults struct {
+	ResultService
+}
+
+func (fakeResults) RecordResult(ctx context.Context, result *models.Result, assessmentID string) error {
+	return nil
+}
+
+// fakeExecutor «запускает» код кандидата: double печатает удвоенное число из stdin, остальное — stdin как есть
+type fakeExecutor struct {
+	err   error
+	calls int
+}
+
+func (e *fakeExecutor) Execute(ctx context.Context, req executor.ExecuteRequest) (*executor.ExecuteResponse, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+	out := req.Stdin
+	if strings.Contains(req.Files["main.py"], "double") {
+		n, _ := strconv.Atoi(strings.TrimSpace(req.Stdin))
+		out = strconv.Itoa(n*2) + "\n"
+	}
+	return &executor.ExecuteResponse{OK: true, Stdout: out}, nil
+}
+
+func codingSession(exec ExecutorClient) (*assessmentService, *fakeAssessmentRepo, *fakeReviewRepo) {
+	var questions []models.Question
+	var answers []models.CandidateAnswer
+	now := time.Now()
+	for i, code := range []string{"print(double(int(input())))", "print(input())"} {
+		q := models.Question{
+			Type:             models.QuestionTypeCoding,
+			Difficulty:       models.DifficultyMiddle,
+			Competency:       "go_fundamentals",
+			Points:           10,
+			SolutionLanguage: "python",
+			TestCases:        []models.TestCase{{Input: "2", Expected: "4"}, {Input: "3", Expected: "6"}},
+		}
+		q.ID = "q" + strconv.Itoa(i+1)
+		questions = append(questions, q)
+
+		a := models.CandidateAnswer{SessionID: "s1", QuestionID: q.ID, Code: code, SubmittedAt: &now}
+		a.ID = "a" + strconv.Itoa(i+1)
+		answers = append(answers, a)
+	}
+
+	session := &models.AssessmentSession{AssessmentID: "as1", CandidateID: "c1", Status: models.SessionStatusInProgress, StartedAt: &now}
+	session.ID = "s1"
+	assessment := &models.Assessment{ReviewersPerAnswer: 1}
+	assessment.ID = "as1"
+
+	repo := &fakeAssessmentRepo{assessment: assessment, session: session, answers: answers}
+	reviews := &fakeReviewRepo{}
+	s := &assessmentService{
+		assessmentRepo: repo,
+		questionRepo:   &fakeQuestionRepo{questions: questions},
+		reviewRepo:     reviews,
+		scoringService: fakeScoring{},
+		resultService:  fakeResults{},
+		executor:       exec,
+	}
+	return s, repo, reviews
+}
+
+func TestCompleteSessionGradesCodingAnswers(t *testing.T) {
+	exec := &fakeExecutor{}
+	s, repo, reviews := codingSession(exec)
+
+	result, err := s.CompleteSession(context.Background(), "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exec.calls != 4 {
+		t.Errorf("executor calls = %d, want 4 (2 answers × 2 test cases)", exec.calls)
+	}
+	if len(reviews.reviews) != 0 {
+		t.Errorf("%d reviews created, want none", len(reviews.reviews))
+	}
+
+	solved, failed := repo.answers[0], repo.answers[1]
+	if !solved.IsCorrect || solved.Score != 10 {
+		t.Errorf("passing answer: correct = %v, score = %v, want true and 10", solved.IsCorrect, solved.Score)
+	}
+	if failed.IsCorrect || failed.Score != 0 {
+		t.Errorf("failing answer: correct = %v, score = %v, want false and 0", failed.IsCorrect, failed.Score)
+	}
+
+	// одна из двух задач решена: половина возможных баллов
+	if !approx(result.Percentage, 50) {
+		t.Errorf("percentage = %v, want 50", result.Percentage)
+	}
+	if repo.session.Status != models.SessionStatusCompleted {
+		t.Errorf("session status = %s, want completed", repo.session.Status)
+	}
+}
+
+func TestCompleteSessionCodingWithoutExecutorGoesToReview(t *testing.T) {
+	exec := &fakeExecutor{err: errors.New("executor unavailable")}
+	s, repo, reviews := codingSession(exec)
+
+	if _, err := s.CompleteSession(context.Background(), "s1"); !errors.Is(err, ErrSessionPendingReview) {
+		t.Fatalf("err = %v, want ErrSessionPendingReview", err)
+	}
+	if len(reviews.reviews) != 2 {
+		t.Errorf("%d reviews created, want one per coding answer", len(reviews.reviews))
+	}
+	if repo.session.Status != models.SessionStatusPendingReview || repo.result != nil {
+		t.Errorf("session status = %s, result = %v, want pending_review without result", repo.session.Status, repo.result)
+	}
+}
+
+// Параллельный CompleteSession: сессию уже закрыли, второй вызов не создаёт проверок
+func TestCompleteSessionConcurrentCall(t *testing.T) {
+	exec := &fakeExecutor{err: errors.New("executor unavailable")}
+	s, repo, reviews := codingSession(exec)
+	stale := *repo.session
+	repo.stale = &stale
+	repo.session.Status = models.SessionStatusPendingReview
+
+	if _, err := s.CompleteSession(context.Background(), "s1"); !errors.Is(err, ErrSessionPendingReview) {
+		t.Fatalf("err = %v, want ErrSessionPendingReview", err)
+	}
+	if len(reviews.reviews) != 0 || exec.calls != 0 {
+		t.Errorf("reviews = %d, executor calls = %d, want none", len(reviews.reviews), exec.calls)
+	}
+}
+
+// Последние проверки сессии сдают одновременно: повторный подсчёт возвращает уже сохранённый результат
+func TestFinalizeSessionIsIdempotent(t *testing.T) {
+	s, repo, _ := codingSession(&fakeExecutor{})
+	repo.session.Status = models.SessionStatusPendingReview
+
+	first, err := s.FinalizeSession(context.Background(), "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.FinalizeSession(context.Background(), "s1")
+	if err != nil {
+		t.Fatalf("second finalize: %v", err)
+	}
+	if second != first {
+		t.Errorf("second finalize returned a new result")
+	}
+}
@@ -0,0 +1,421 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// questionTypeMinutes нормы времени на вопрос из TEST_STRUCTURE (docs/assessment-framework.md)
+var questionTypeMinutes = map[models.QuestionType]float64{
+	models.QuestionTypeMultipleChoice: 1.5,
+	models.QuestionTypeCoding:         5.0,
+	models.QuestionTypeArchitecture:   7.0,
+	models.QuestionTypeDebugging:      4.0,
+}
+
+const (
+	defaultTemplateQuestions = 20   // TEST_STRUCTURE: 20 вопросов
+	defaultTemplateTimeLimit = 3600 // секунды, если структура по типам не задана
+)
+
+var (
+	// ErrBuiltinTemplate встроенные шаблоны засеяны миграцией и через API не меняются
+	ErrBuiltinTemplate = errors.New("built-in templates cannot be changed")
+	// ErrTemplateKeyExists шаблон с таким key уже есть
+	ErrTemplateKeyExists = errors.New("assessment template with this key already exists")
+)
+
+// AssessmentTemplateService шаблоны оценок: встроенные (по TEST_STRUCTURE) и созданные HR
+type AssessmentTemplateService interface {
+	ListTemplates(ctx context.Context) ([]models.AssessmentTemplate, error)
+	GetTemplate(ctx context.Context, id string) (*models.AssessmentTemplate, error)
+	CreateTemplate(ctx context.Context, req models.AssessmentTemplateRequest, createdBy string) (*models.AssessmentTemplate, error)
+	UpdateTemplate(ctx context.Context, id string, req models.AssessmentTemplateRequest) (*models.AssessmentTemplate, error)
+	DeleteTemplate(ctx context.Context, id string) error
+
+	// InstantiateTemplate новая оценка (draft) с настройками и компетенциями шаблона
+	InstantiateTemplate(ctx context.Context, id string, req models.InstantiateTemplateRequest, createdBy string) (*models.Assessment, error)
+}
+
+type assessmentTemplateService struct {
+	assessmentRepo    repository.AssessmentRepository
+	assessmentService AssessmentService
+}
+
+func NewAssessmentTemplateService(assessmentRepo repository.AssessmentRepository, assessmentService AssessmentService) AssessmentTemplateService {
+	return &assessmentTemplateService{
+		assessmentRepo:    assessmentRepo,
+		assessmentService: assessmentService,
+	}
+}
+
+// ==========================
+// TEMPLATES
+// ==========================
+
+func (s *assessmentTemplateService) ListTemplates(ctx context.Context) ([]models.AssessmentTemplate, error) {
+	templates, err := s.assessmentRepo.ListTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list templates failed: %w", err)
+	}
+	return templates, nil
+}
+
+func (s *assessmentTemplateService) GetTemplate(ctx context.Context, id string) (*models.AssessmentTemplate, error) {
+	template, err := s.assessmentRepo.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("template not found: %w", err)
+	}
+	return template, nil
+}
+
+func (s *assessmentTemplateService) CreateTemplate(ctx context.Context, req models.AssessmentTemplateRequest, createdBy string) (*models.AssessmentTemplate, error) {
+	template := &models.AssessmentTemplate{}
+	if err := applyTemplateRequest(template, req); err != nil {
+		return nil, err
+	}
+	if err := s.ensureKeyFree(ctx, template.Key, ""); err != nil {
+		return nil, err
+	}
+	if createdBy != "" {
+		template.CreatedBy = &createdBy
+	}
+
+	if err := s.assessmentRepo.CreateTemplate(ctx, template); err != nil {
+		return nil, fmt.Errorf("create template failed: %w", err)
+	}
+	return template, nil
+}
+
+func (s *assessmentTemplateService) UpdateTemplate(ctx context.Context, id string, req models.AssessmentTemplateRequest) (*models.AssessmentTemplate, error) {
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if template.IsBuiltin {
+		return nil, ErrBuiltinTemplate
+	}
+	if err := applyTemplateRequest(template, req); err != nil {
+		return nil, err
+	}
+	if err := s.ensureKeyFree(ctx, template.Key, template.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.assessmentRepo.UpdateTemplate(ctx, template); err != nil {
+		return nil, fmt.Errorf("update template failed: %w", err)
+	}
+	return template, nil
+}
+
+func (s *assessmentTemplateService) DeleteTemplate(ctx context.Context, id string) error {
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if template.IsBuiltin {
+		return ErrBuiltinTemplate
+	}
+	if err := s.assessmentRepo.DeleteTemplate(ctx, template.ID); err != nil {
+		return fmt.Errorf("delete template failed: %w", err)
+	}
+	return nil
+}
+
+// InstantiateTemplate создаёт оценку обычным CreateAssessment — с теми же проверками и значениями по умолчанию
+func (s *assessmentTemplateService) InstantiateTemplate(ctx context.Context, id string, req models.InstantiateTemplateRequest, createdBy string) (*models.Assessment, error) {
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	create := models.CreateAssessmentRequest{
+		Title:            template.Name,
+		Description:      template.Description,
+		Type:             models.AssessmentType(template.Type),
+		TargetLevel:      models.DifficultyLevel(template.TargetLevel),
+		TimeLimit:        template.TimeLimit,
+		TotalQuestions:   template.TotalQuestions,
+		PassingScore:     template.PassingScore,
+		ShuffleQuestions: template.ShuffleQuestions,
+		ShowExplanation:  template.ShowExplanation,
+		Competencies:     append([]models.CompetencyWeight(nil), template.Competencies...),
+		Tags:             mergeTags(template.Tags, req.Tags),
+		Availability:     req.Availability,
+		MaxAttempts:      req.MaxAttempts,
+		AttemptCooldown:  req.AttemptCooldown,
+
+		ReviewersPerAnswer:    template.ReviewersPerAnswer,
+		DisagreementThreshold: template.DisagreementThreshold,
+		AIGrading:             template.AIGrading,
+		CompetencyWeights:     cloneWeights(template.CompetencyWeights),
+
+		QuestionTypes:     cloneStructure(template.QuestionTypes),
+		LevelDistribution: cloneStructure(template.LevelDistribution),
+	}
+	if req.Title != "" {
+		create.Title = req.Title
+	}
+	if req.Description != nil {
+		create.Description = *req.Description
+	}
+	if req.TargetLevel != "" {
+		create.TargetLevel = req.TargetLevel
+	}
+	if req.TimeLimit > 0 {
+		create.TimeLimit = req.TimeLimit
+	}
+	if req.PassingScore != nil {
+		create.PassingScore = *req.PassingScore
+	}
+
+	return s.assessmentService.CreateAssessment(ctx, create, createdBy)
+}
+
+func (s *assessmentTemplateService) ensureKeyFree(ctx context.Context, key, templateID string) error {
+	existing, err := s.assessmentRepo.GetTemplateByKey(ctx, key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("lookup template failed: %w", err)
+	}
+	if existing.ID != templateID {
+		return ErrTemplateKeyExists
+	}
+	return nil
+}
+
+// applyTemplateRequest проверяет структуру теста и переносит запрос в шаблон
+func applyTemplateRequest(t *models.AssessmentTemplate, req models.AssessmentTemplateRequest) error {
+	typed := 0
+	for _, n := range req.QuestionTypes {
+		typed += n
+	}
+	total := req.TotalQuestions
+	if total == 0 {
+		total = typed
+	}
+	if total == 0 {
+		total = defaultTemplateQuestions
+	}
+	errs := structureErrors(total, req.QuestionTypes, req.LevelDistribution)
+
+	for _, c := range req.Competencies {
+		if c.MaxQuestions > 0 && c.MaxQuestions < c.MinQuestions {
+			errs = append(errs, fmt.Sprintf("competency %s: max_questions must be >= min_questions", c.CompetencyID))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	timeLimit := req.TimeLimit
+	if timeLimit == 0 {
+		timeLimit = structureTimeLimit(req.QuestionTypes)
+	}
+
+	t.Key = strings.TrimSpace(req.Key)
+	t.Name = strings.TrimSpace(req.Name)
+	t.Description = req.Description
+	t.Type = string(req.Type)
+	t.TargetLevel = string(req.TargetLevel)
+	t.TimeLimit = timeLimit
+	t.TotalQuestions = total
+	t.PassingScore = req.PassingScore
+	t.ShuffleQuestions = req.ShuffleQuestions
+	t.ShowExplanation = req.ShowExplanation
+	t.ReviewersPerAnswer = req.ReviewersPerAnswer
+	if t.ReviewersPerAnswer <= 0 {
+		t.ReviewersPerAnswer = 1
+	}
+	t.DisagreementThreshold = req.DisagreementThreshold
+	if t.DisagreementThreshold <= 0 {
+		t.DisagreementThreshold = defaultDisagreementThreshold
+	}
+	t.AIGrading = req.AIGrading
+	t.Competencies = req.Competencies
+	t.CompetencyWeights = req.CompetencyWeights
+	t.Tags = mergeTags(req.Tags, nil)
+	t.QuestionTypes = req.QuestionTypes
+	t.LevelDistribution = req.LevelDistribution
+	return nil
+}
+
+// structureErrors проверяет структуру теста: известные типы и уровни, суммы равны total
+func structureErrors(total int, types map[models.QuestionType]int, levels map[models.DifficultyLevel]int) []string {
+	var errs []string
+
+	typed := 0
+	for qt, n := range types {
+		if _, ok := questionTypeMinutes[qt]; !ok {
+			errs = append(errs, fmt.Sprintf("unknown question type %q", qt))
+		}
+		if n < 0 {
+			errs = append(errs, fmt.Sprintf("question_types.%s must not be negative", qt))
+		}
+		typed += n
+	}
+	if len(types) > 0 && typed != total {
+		errs = append(errs, fmt.Sprintf("question_types add up to %d, total_questions is %d", typed, total))
+	}
+
+	leveled := 0
+	for level, n := range levels {
+		if levelIndex(level) < 0 {
+			errs = append(errs, fmt.Sprintf("unknown level %q", level))
+		}
+		if n < 0 {
+			errs = append(errs, fmt.Sprintf("level_distribution.%s must not be negative", level))
+		}
+		leveled += n
+	}
+	if len(levels) > 0 && leveled != total {
+		errs = append(errs, fmt.Sprintf("level_distribution adds up to %d, total_questions is %d", leveled, total))
+	}
+	return errs
+}
+
+// cloneStructure копия карты структуры теста (nil остаётся nil)
+func cloneStructure[K comparable](m map[K]int) map[K]int {
+	if m == nil {
+		return nil
+	}
+	out := make(map[K]int, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// structureTimeLimit время на тест по нормам TEST_STRUCTURE, с округлением вверх до 5 минут
+func structureTimeLimit(types map[models.QuestionType]int) int {
+	minutes := 0.0
+	for qt, n := range types {
+		minutes += questionTypeMinutes[qt] * float64(n)
+	}
+	if minutes == 0 {
+		return defaultTemplateTimeLimit
+	}
+	return int(math.Ceil(minutes/5)) * 5 * 60
+}
+
+// mergeTags теги без пустых и повторов, в исходном порядке
+func mergeTags(base, extra []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, tag := range append(append([]string(nil), base...), extra...) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+func cloneWeights(weights map[string]float64) map[string]float64 {
+	if weights == nil {
+		return nil
+	}
+	out := make(map[string]float64, len(weights))
+	for k, v := range weights {
+		out[k] = v
+	}
+	return out
+}
+
+// ==========================
+// CLONE
+// ==========================
+
+// CloneAssessment глубокая копия оценки: настройки, компетенции, теги и набор вопросов.
+// Копия всегда draft и принадлежит тому, кто её создал; сессии и приглашения не копируются.
+func (s *assessmentService) CloneAssessment(ctx context.Context, id string, req models.CloneAssessmentRequest, createdBy string) (*models.Assessment, error) {
+	if createdBy == "" {
+		return nil, fmt.Errorf("created_by is required")
+	}
+	source, err := s.assessmentRepo.GetAssessmentWithQuestions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("assessment not found: %w", err)
+	}
+
+	clone := &models.Assessment{
+		Title:            req.Title,
+		Description:      source.Description,
+		Type:             source.Type,
+		TargetLevel:      source.TargetLevel,
+		TimeLimit:        source.TimeLimit,
+		TotalQuestions:   source.TotalQuestions,
+		PassingScore:     source.PassingScore,
+		ShuffleQuestions: source.ShuffleQuestions,
+		ShowExplanation:  source.ShowExplanation,
+		CreatedBy:        createdBy,
+		Status:           models.AssessmentStatusDraft,
+
+		ReviewersPerAnswer:    source.ReviewersPerAnswer,
+		DisagreementThreshold: source.DisagreementThreshold,
+		AIGrading:             source.AIGrading,
+		CompetencyWeights:     cloneWeights(source.CompetencyWeights),
+		QuestionTypes:         cloneStructure(source.QuestionTypes),
+		LevelDistribution:     cloneStructure(source.LevelDistribution),
+
+		// правила попыток переносятся, окно — нет: у копии будет свой набор
+		Availability:    models.AvailabilityWindow{Timezone: source.Availability.Timezone},
+		MaxAttempts:     source.MaxAttempts,
+		AttemptCooldown: source.AttemptCooldown,
+	}
+	if clone.Title == "" {
+		clone.Title = source.Title + " (copy)"
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(clone).Error; err != nil {
+			return fmt.Errorf("create assessment failed: %w", err)
+		}
+
+		for _, c := range source.Competencies {
+			comp := &models.AssessmentCompetency{
+				AssessmentID: clone.ID,
+				CompetencyID: c.CompetencyID,
+				Level:        c.Level,
+				Weight:       c.Weight,
+				MinQuestions: c.MinQuestions,
+				MaxQuestions: c.MaxQuestions,
+			}
+			if err := tx.Omit(clause.Associations).Create(comp).Error; err != nil {
+				return fmt.Errorf("copy competency failed: %w", err)
+			}
+		}
+		if len(source.Tags) > 0 {
+			names := make([]string, 0, len(source.Tags))
+			for _, t := range source.Tags {
+				names = append(names, t.Name)
+			}
+			if err := repository.NewAssessmentRepository(tx).SetAssessmentTags(ctx, clone.ID, names); err != nil {
+				return fmt.Errorf("copy tags failed: %w", err)
+			}
+		}
+		// та же версия каждого вопроса, что и в исходной оценке
+		for _, q := range source.Questions {
+			aq := &models.AssessmentQuestion{AssessmentID: clone.ID, QuestionID: q.QuestionID, Order: q.Order}
+			if err := tx.Omit(clause.Associations).Create(aq).Error; err != nil {
+				return fmt.Errorf("copy question failed: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.assessmentRepo.GetAssessmentWithQuestions(ctx, clone.ID)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+const (
+	// strengthPercentage компетенция с таким процентом и выше считается сильной стороной
+	strengthPercentage = 70.0
+	// improvementPercentage ниже этого процента — зона роста
+	improvementPercentage = 50.0
+)
+
+// GetResultBreakdown разбивка результата по компетенциям с названиями из справочника.
+// Для результатов, посчитанных до появления разбивки, она вычисляется по ответам и сохраняется.
+func (s *assessmentService) GetResultBreakdown(ctx context.Context, resultID string) (*models.ResultBreakdown, error) {
+	result, err := s.assessmentRepo.GetResultByID(ctx, resultID)
+	if err != nil {
+		return nil, fmt.Errorf("result not found: %w", err)
+	}
+
+	if len(result.CompetencyBreakdown) == 0 {
+		answers, err := s.assessmentRepo.GetSessionAnswers(ctx, result.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("load answers failed: %w", err)
+		}
+		questions, err := s.questionRepo.GetQuestionsByIDs(ctx, answerQuestionIDs(answers))
+		if err != nil {
+			return nil, fmt.Errorf("load questions failed: %w", err)
+		}
+		cfg, err := s.resultScoringConfig(ctx, result)
+		if err != nil {
+			return nil, err
+		}
+		session, err := s.assessmentRepo.GetSessionByID(ctx, result.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("session not found: %w", err)
+		}
+		history, err := s.scoringHistory(ctx, session.CandidateID, result.CompletedAt)
+		if err != nil {
+			return nil, err
+		}
+		score, err := s.scoringService.CalculateFinalScore(answers, questions, cfg, history)
+		if err != nil {
+			return nil, fmt.Errorf("scoring failed: %w", err)
+		}
+		result.CompetencyBreakdown = score.CompetencyBreakdown
+		if err := s.assessmentRepo.UpdateResult(ctx, result); err != nil {
+			return nil, fmt.Errorf("update result failed: %w", err)
+		}
+	}
+
+	scores := make([]models.CompetencyScore, len(result.CompetencyBreakdown))
+	copy(scores, result.CompetencyBreakdown)
+
+	names := make([]string, 0, len(scores))
+	for _, c := range scores {
+		names = append(names, c.CompetencyID)
+	}
+	competencies, err := s.assessmentRepo.GetCompetencies(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("load competencies failed: %w", err)
+	}
+	byName := make(map[string]models.Competency, len(competencies))
+	for _, c := range competencies {
+		byName[c.Name] = c
+	}
+
+	breakdown := &models.ResultBreakdown{
+		ResultID:            result.ID,
+		SessionID:           result.SessionID,
+		TotalScore:          result.TotalScore,
+		Percentage:          result.Percentage,
+		Level:               result.Level,
+		Strengths:           []string{},
+		AreasForImprovement: []string{},
+	}
+	for i := range scores {
+		c := &scores[i]
+		c.CompetencyName = c.CompetencyID
+		if comp, ok := byName[c.CompetencyID]; ok {
+			c.CompetencyName = comp.Name
+			c.Category = comp.Category
+			c.Description = comp.Description
+		}
+
+		switch {
+		case c.Percentage >= strengthPercentage:
+			breakdown.Strengths = append(breakdown.Strengths, c.CompetencyName)
+		case c.Percentage < improvementPercentage:
+			breakdown.AreasForImprovement = append(breakdown.AreasForImprovement, c.CompetencyName)
+		}
+	}
+	breakdown.CompetencyScores = scores
+
+	return breakdown, nil
+}
+
+// resultScoringConfig формула, по которой был посчитан результат: сохранённая версия
+// с переопределениями оценки на момент подсчёта (Result.CompetencyWeights)
+func (s *assessmentService) resultScoringConfig(ctx context.Context, result *models.Result) (models.ScoringConfig, error) {
+	if result.ScoringConfigVersion == 0 {
+		// результаты до версионирования формулы объясняются активной версией
+		active, err := s.scoringService.ActiveConfig(ctx)
+		if err != nil {
+			return models.ScoringConfig{}, fmt.Errorf("load scoring config failed: %w", err)
+		}
+		return withWeightOverrides(active.Config, result.CompetencyWeights), nil
+	}
+
+	stored, err := s.scoringService.GetConfig(ctx, result.ScoringConfigVersion)
+	if err != nil {
+		return models.ScoringConfig{}, err
+	}
+	return withWeightOverrides(stored.Config, result.CompetencyWeights), nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+)
+
+// CalibrationService калибровка банка вопросов по модели 2PL IRT.
+// Запускается пакетно: из cmd/calibrate или админом через API.
+type CalibrationService interface {
+	Calibrate(ctx context.Context, req models.CalibrationRequest, triggeredBy string) (*models.CalibrationRun, error)
+	ListRuns(ctx context.Context, limit int) ([]models.CalibrationRun, error)
+}
+
+type calibrationService struct {
+	calibrationRepo repository.CalibrationRepository
+}
+
+func NewCalibrationService(calibrationRepo repository.CalibrationRepository) CalibrationService {
+	return &calibrationService{calibrationRepo: calibrationRepo}
+}
+
+// Calibrate подбирает параметры по всем завершённым сессиям и сохраняет их в вопросы.
+// Вопросы с числом ответов меньше MinResponses пропускаются (старые параметры остаются).
+func (s *calibrationService) Calibrate(ctx context.Context, req models.CalibrationRequest, triggeredBy string) (*models.CalibrationRun, error) {
+	if req.MinResponses <= 0 {
+		req.MinResponses = defaultCalibrationMinResponses
+	}
+	if req.MaxIterations <= 0 {
+		req.MaxIterations = defaultCalibrationMaxIterations
+	}
+
+	run := &models.CalibrationRun{
+		Status:       models.CalibrationStatusRunning,
+		StartedAt:    time.Now(),
+		MinResponses: req.MinResponses,
+	}
+	if triggeredBy != "" {
+		run.TriggeredBy = &triggeredBy
+	}
+	if err := s.calibrationRepo.CreateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("create calibration run failed: %w", err)
+	}
+
+	if err := s.calibrate(ctx, run, req); err != nil {
+		now := time.Now()
+		run.Status = models.CalibrationStatusFailed
+		run.FinishedAt = &now
+		run.Error = err.Error()
+		if uerr := s.calibrationRepo.UpdateRun(ctx, run); uerr != nil {
+			return nil, fmt.Errorf("update calibration run failed: %w", uerr)
+		}
+		return run, err
+	}
+	return run, nil
+}
+
+func (s *calibrationService) calibrate(ctx context.Context, run *models.CalibrationRun, req models.CalibrationRequest) error {
+	responses, err := s.calibrationRepo.GetItemResponses(ctx)
+	if err != nil {
+		return fmt.Errorf("load responses failed: %w", err)
+	}
+
+	fit := calibrate2PL(responses, req.MinResponses, req.MaxIterations)
+
+	now := time.Now()
+	if err := s.calibrationRepo.SaveParams(ctx, fit.params, now); err != nil {
+		return fmt.Errorf("save irt params failed: %w", err)
+	}
+
+	run.Status = models.CalibrationStatusCompleted
+	run.FinishedAt = &now
+	run.Sessions = fit.sessions
+	run.Responses = len(responses)
+	run.Calibrated = len(fit.params)
+	run.Skipped = fit.skipped
+	run.Iterations = fit.iterations
+	run.Converged = fit.converged
+	run.LogLikelihood = fit.logLikelihood
+	run.Params = fit.params
+
+	if err := s.calibrationRepo.UpdateRun(ctx, run); err != nil {
+		return fmt.Errorf("update calibration run failed: %w", err)
+	}
+	return nil
+}
+
+func (s *calibrationService) ListRuns(ctx context.Context, limit int) ([]models.CalibrationRun, error) {
+	runs, err := s.calibrationRepo.ListRuns(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("load calibration runs failed: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+// matrixEntry строка матрицы компетенций (docs/competency-matrix.md): что ожидается на каждом уровне
+type matrixEntry struct {
+	Name    string
+	Section string
+	Levels  map[models.DifficultyLevel]string
+}
+
+// matrixLevels уровни матрицы по возрастанию
+var matrixLevels = []models.DifficultyLevel{
+	models.DifficultyJunior,
+	models.DifficultyMiddle,
+	models.DifficultySenior,
+	models.DifficultyExpert,
+}
+
+func matrixRow(name, section, junior, middle, senior, expert string) matrixEntry {
+	return matrixEntry{
+		Name:    name,
+		Section: section,
+		Levels: map[models.DifficultyLevel]string{
+			models.DifficultyJunior: junior,
+			models.DifficultyMiddle: middle,
+			models.DifficultySenior: senior,
+			models.DifficultyExpert: expert,
+		},
+	}
+}
+
+// competencyMatrix строки матрицы по коду компетенции; при изменении docs/competency-matrix.md обновлять вместе
+var competencyMatrix = map[string]matrixEntry{
+	"go_fundamentals": matrixRow("Go Syntax & Basics", "Core Go Development",
+		"basic syntax, variables, data types, operators, conditions, loops",
+		"methods, interfaces, packages, modules, error handling",
+		"reflection, testing, documentation, meta-programming",
+		"performance optimization, profiling, mentoring"),
+	"data_structures_go": matrixRow("Data Structures", "Core Go Development",
+		"arrays, slices, maps creation and iteration",
+		"structs, embedding, custom data structures",
+		"trees, graphs, caching, complex structures",
+		"parallel data structures, optimization"),
+	"memory_management": matrixRow("Memory Management", "Core Go Development",
+		"GC principles, pointers, nil pointers",
+		"memory optimization, object pools, profiling",
+		"GC tuning, lifecycle management, thread-safe memory",
+		"runtime analysis, advanced tools"),
+	"concurrency": matrixRow("Concurrency", "Core Go Development",
+		"goroutines, channels, basic synchronization",
+		"Mutex, WaitGroup, concurrency patterns, context",
+		"profiling, optimization, distributed systems",
+		"runtime optimization, library development"),
+	"http_go": matrixRow("HTTP & Web", "Advanced Go",
+		"basic HTTP server, routing, requests",
+		"middlewares, JSON, forms, context",
+		"optimization, security, testing",
+		"microservices, API gateway, load balancing"),
+	"testing": matrixRow("Quality Assurance", "Advanced Go",
+		"unit testing, coverage, basic docs",
+		"mocking, integration tests, linting",
+		"CI/CD, profiling, code quality",
+		"test automation, metrics"),
+	"os_interaction": matrixRow("OS Interaction", "Advanced Go",
+		"file I/O, command execution, paths",
+		"environment, signals, permissions",
+		"cross-platform code, syscalls, performance",
+		"optimization, system tools"),
+	"grpc": matrixRow("gRPC", "Advanced Go",
+		"basic concepts, .proto files, simple implementation",
+		"streaming, interceptors, testing",
+		"security, performance, monitoring",
+		"microservice patterns"),
+	"system_design": matrixRow("System Design", "System Design & Architecture",
+		"component architecture, monolith vs microservices",
+		"synchronous and asynchronous communication",
+		"scalable component architectures",
+		"complex system architecture, technology trends"),
+	"microservices": matrixRow("Microservices", "System Design & Architecture",
+		"pros and cons of microservices",
+		"practical experience building microservices",
+		"development with trade-offs in mind",
+		"design and optimization of complex systems"),
+	"containerization": matrixRow("Containerization", "System Design & Architecture",
+		"basic orchestration concepts",
+		"kubectl, docker, scaling principles",
+		"high-load system management",
+		"orchestrator architecture and principles"),
+	"reliability": matrixRow("Reliability", "System Design & Architecture",
+		"basic distributed transactions, scalability",
+		"transaction optimization, fault tolerance",
+		"transaction patterns, auto-scaling",
+		"advanced transactions, load balancing"),
+	"performance": matrixRow("Performance", "Performance & Scalability",
+		"basic optimization, memory management",
+		"profiler usage, application optimization",
+		"database optimization, scalable design",
+		"scalable architectures, cloud computing"),
+	"latency_throughput": matrixRow("Latency & Throughput", "Performance & Scalability",
+		"basic concepts and their impact",
+		"analysis and optimization",
+		"network optimization, resource management",
+		"fine-tuning distributed systems"),
+	"availability_consistency": matrixRow("Availability & Consistency", "Performance & Scalability",
+		"concurrency basics, simple consistency",
+		"transactions, ACID, state consistency",
+		"data consistency in distributed systems",
+		"architectural patterns, failover design"),
+	"ci_cd": matrixRow("CI/CD", "Software Engineering Practices",
+		"principles and benefits",
+		"pipeline setup and maintenance",
+		"complex delivery processes",
+		"automation and optimization in large projects"),
+	"git": matrixRow("Git & Version Control", "Software Engineering Practices",
+		"basic concepts",
+		"basic techniques, GitFlow",
+		"advanced workflows, merge strategies",
+		"team workflow standardization"),
+	"best_practices": matrixRow("Coding Best Practices", "Software Design",
+		"maintainability, readability, formatting",
+		"informative comments, descriptive naming",
+		"OOP principles, dependency minimization",
+		"architectural patterns, performance optimization"),
+	"design_principles": matrixRow("Design Principles", "Software Design",
+		"DRY, KISS, SOLID",
+		"applying SOLID and DRY",
+		"building high-performance systems",
+		"architectural cleanliness, effective solutions"),
+	"design_patterns": matrixRow("Design Patterns", "Software Design",
+		"basic patterns in theory",
+		"pattern differences and practical use",
+		"combining patterns",
+		"designing new patterns"),
+	"auth": matrixRow("Authentication & Authorization", "Security",
+		"basic concepts, protocols, sessions",
+		"multi-factor auth, secure processes, RBAC",
+		"data security, system integration",
+		"secure mechanism design"),
+	"data_security": matrixRow("Data Security", "Security",
+		"basic threats, encryption methods",
+		"encryption techniques, fixing vulnerabilities",
+		"advanced methods in distributed systems",
+		"organizational security strategy"),
+	"web_security": matrixRow("OWASP Risks", "Security",
+		"OWASP Top 10",
+		"secure architecture",
+		"specific threats, risk assessment",
+		"best practices, team leadership"),
+}
+
+// competencyAliases коды из справочника competencies, которым соответствует другая строка матрицы
+var competencyAliases = map[string]string{
+	"algorithms":      "data_structures_go",
+	"architecture":    "system_design",
+	"database_design": "availability_consistency",
+	"databases":       "availability_consistency",
+	"debugging":       "performance",
+	"security":        "auth",
+	"http":            "http_go",
+}
+
+// lookupMatrix строка матрицы для компетенции (по коду или алиасу)
+func lookupMatrix(competency string) (matrixEntry, bool) {
+	code := strings.ToLower(strings.TrimSpace(competency))
+	if alias, ok := competencyAliases[code]; ok {
+		code = alias
+	}
+	entry, ok := competencyMatrix[code]
+	return entry, ok
+}
+
+// nextMatrixLevel следующий уровень матрицы (для expert — он сам)
+func nextMatrixLevel(level models.DifficultyLevel) models.DifficultyLevel {
+	for i, l := range matrixLevels {
+		if l == level && i+1 < len(matrixLevels) {
+			return matrixLevels[i+1]
+		}
+	}
+	return level
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+const (
+	// maxEditBatchBytes ограничение на распакованный размер одной пачки
+	maxEditBatchBytes = 1 << 20 // 1MB
+	// maxEditBatchDeltas ограничение на число дельт в одной пачке
+	maxEditBatchDeltas = 10000
+	// largePasteMinChars вставка от этого размера считается «большой»
+	largePasteMinChars = 80
+	pasteSampleChars   = 120
+)
+
+// ErrInvalidEditBatch пачка дельт пустая, слишком большая или не разбирается
+var ErrInvalidEditBatch = errors.New("invalid edit batch")
+
+// RecordEdits сохраняет пачку дельт редактора для вопроса типа coding
+func (s *assessmentService) RecordEdits(ctx context.Context, sessionID, questionID string, req models.EditHistoryRequest) error {
+	if sessionID == "" || questionID == "" {
+		return fmt.Errorf("session_id and question_id are required")
+	}
+
+	session, err := s.assessmentRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("session not found: %w", err)
+	}
+	if session.Status != models.SessionStatusInProgress {
+		return ErrSessionNotInProgress
+	}
+
+	question, err := s.sessionQuestion(ctx, session, questionID)
+	if err != nil {
+		return err
+	}
+	if question.Type != models.QuestionTypeCoding {
+		return fmt.Errorf("%w: edit history is only supported for coding questions", ErrInvalidEditBatch)
+	}
+
+	deltas, err := decodeEditRequest(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEditBatch, err)
+	}
+	if len(deltas) == 0 {
+		return fmt.Errorf("%w: empty edit batch", ErrInvalidEditBatch)
+	}
+	if len(deltas) > maxEditBatchDeltas {
+		return fmt.Errorf("%w: more than %d deltas", ErrInvalidEditBatch, maxEditBatchDeltas)
+	}
+
+	// клиент может прислать дельты не по порядку: границы пачки берём после сортировки
+	sort.SliceStable(deltas, func(i, j int) bool { return deltas[i].T < deltas[j].T })
+
+	// предел размера тот же, что и для распакованной gzip-пачки
+	data, err := json.Marshal(deltas)
+	if err != nil {
+		return fmt.Errorf("encode deltas failed: %w", err)
+	}
+	if len(data) > maxEditBatchBytes {
+		return fmt.Errorf("%w: edit batch exceeds %d bytes", ErrInvalidEditBatch, maxEditBatchBytes)
+	}
+
+	payload, err := gzipBytes(data)
+	if err != nil {
+		return fmt.Errorf("compress deltas failed: %w", err)
+	}
+
+	batch := &models.AnswerEditBatch{
+		SessionID:  sessionID,
+		QuestionID: questionID,
+		Seq:        req.Seq,
+		Encoding:   "gzip+json",
+		Payload:    payload,
+		DeltaCount: len(deltas),
+		FirstT:     deltas[0].T,
+		LastT:      deltas[len(deltas)-1].T,
+	}
+	return s.assessmentRepo.SaveEditBatch(ctx, batch)
+}
+
+// ReplayAnswer восстанавливает код на момент at (мс от начала записи).
+// at < 0 — финальное состояние.
+func (s *assessmentService) ReplayAnswer(ctx context.Context, sessionID, questionID string, at int64) (*models.CodeReplay, error) {
+	batches, err := s.assessmentRepo.GetEditBatches(ctx, sessionID, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("load edit history failed: %w", err)
+	}
+	if len(batches) == 0 {
+		return nil, fmt.Errorf("no edit history for this answer")
+	}
+
+	var deltas []models.CodeEditDelta
+	for _, b := range batches {
+		chunk, err := decompressDeltas(b.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("edit batch %d is corrupted: %w", b.Seq, err)
+		}
+		deltas = append(deltas, chunk...)
+	}
+	// пачки уже идут по seq, но дельты дополнительно упорядочиваем по времени
+	sort.SliceStable(deltas, func(i, j int) bool { return deltas[i].T < deltas[j].T })
+
+	duration := deltas[len(deltas)-1].T
+	if at < 0 || at > duration {
+		at = duration
+	}
+
+	code, applied := applyDeltas("", deltas, at)
+
+	return &models.CodeReplay{
+		SessionID:     sessionID,
+		QuestionID:    questionID,
+		At:            at,
+		Duration:      duration,
+		Code:          code,
+		TotalDeltas:   len(deltas),
+		AppliedDeltas: applied,
+		Pastes:        detectPastes(deltas),
+	}, nil
+}
+
+func decodeEditRequest(req models.EditHistoryRequest) ([]models.CodeEditDelta, error) {
+	if req.Encoding != "gzip+base64" {
+		return req.Deltas, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(req.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 data: %w", err)
+	}
+	return decompressDeltas(raw)
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressDeltas(payload []byte) ([]models.CodeEditDelta, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip data: %w", err)
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(io.LimitReader(zr, maxEditBatchBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip data: %w", err)
+	}
+	if len(data) > maxEditBatchBytes {
+		return nil, fmt.Errorf("edit batch exceeds %d bytes", maxEditBatchBytes)
+	}
+
+	var deltas []models.CodeEditDelta
+	if err := json.Unmarshal(data, &deltas); err != nil {
+		return nil, fmt.Errorf("invalid deltas json: %w", err)
+	}
+	return deltas, nil
+}
+
+// applyDeltas применяет дельты с T <= at. Некорректные позиции обрезаются по границам текста.
+// Текст правится на месте в одном буфере: дельта сдвигает только хвост после позиции.
+func applyDeltas(initial string, deltas []models.CodeEditDelta, at int64) (string, int) {
+	text := []rune(initial)
+	applied := 0
+
+	for _, d := range deltas {
+		if d.T > at {
+			break
+		}
+
+		pos := clamp(d.P, 0, len(text))
+		end := clamp(pos+d.D, pos, len(text))
+		ins := []rune(d.I)
+
+		tail := len(text) - end
+		size := pos + len(ins) + tail
+		if size > len(text) {
+			text = append(text, make([]rune, size-len(text))...)
+		}
+		copy(text[pos+len(ins):], text[end:end+tail])
+		copy(text[pos:], ins)
+		text = text[:size]
+		applied++
+	}
+	return string(text), applied
+}
+
+func detectPastes(deltas []models.CodeEditDelta) []models.PasteEvent {
+	pastes := []models.PasteEvent{}
+	for _, d := range deltas {
+		ins := []rune(d.I)
+		if len(ins) < largePasteMinChars {
+			continue
+		}
+		sample := ins
+		if len(sample) > pasteSampleChars {
+			sample = sample[:pasteSampleChars]
+		}
+		pastes = append(pastes, models.PasteEvent{
+			T:      d.T,
+			P:      d.P,
+			Length: len(ins),
+			Sample: string(sample),
+		})
+	}
+	return pastes
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+func TestApplyDeltas(t *testing.T) {
+	deltas := []models.CodeEditDelta{
+		{T: 10, P: 0, I: "func main() {}"},
+		{T: 20, P: 13, I: "\n\tprintln(\"hi\")\n"},
+		{T: 30, P: 12, D: 1, I: "{ // start"},   // замена: удаление и вставка
+		{T: 40, P: 0, D: 4, I: "fn"},            // укорачивание в начале
+		{T: 50, P: 1000, I: "\n// end"},         // позиция за концом текста
+		{T: 60, P: 2, D: 1000, I: " main() {}"}, // удаление до конца
+	}
+
+	tests := []struct {
+		at      int64
+		want    string
+		applied int
+	}{
+		{0, "", 0},
+		{10, "func main() {}", 1},
+		{20, "func main() {\n\tprintln(\"hi\")\n}", 2},
+		{30, "func main() { // start\n\tprintln(\"hi\")\n}", 3},
+		{40, "fn main() { // start\n\tprintln(\"hi\")\n}", 4},
+		{50, "fn main() { // start\n\tprintln(\"hi\")\n}\n// end", 5},
+		{60, "fn main() {}", 6},
+	}
+	for _, tt := range tests {
+		got, applied := applyDeltas("", deltas, tt.at)
+		if got != tt.want || applied != tt.applied {
+			t.Errorf("at %d: %q (%d applied), want %q (%d)", tt.at, got, applied, tt.want, tt.applied)
+		}
+	}
+
+	// позиции считаются в символах, а не в байтах
+	if got, _ := applyDeltas("привет", []models.CodeEditDelta{{P: 3, D: 1, I: "ЕЕ"}}, 0); got != "приЕЕет" {
+		t.Errorf("unicode: %q, want %q", got, "приЕЕет")
+	}
+}
+
+func TestApplyDeltasLongSession(t *testing.T) {
+	// посимвольный набор длинного решения с правками в середине
+	var deltas []models.CodeEditDelta
+	var want []rune
+	for i := 0; i < 20000; i++ {
+		pos := len(want) / 2
+		ch := rune('a' + i%26)
+		deltas = append(deltas, models.CodeEditDelta{T: int64(i), P: pos, I: string(ch)})
+		want = append(want[:pos], append([]rune{ch}, want[pos:]...)...)
+	}
+	got, applied := applyDeltas("", deltas, int64(len(deltas)))
+	if got != string(want) || applied != len(deltas) {
+		t.Errorf("replay of %d deltas differs from expected text (%d applied)", len(deltas), applied)
+	}
+}
+
+func TestRecordEditsBatchBoundsAfterSort(t *testing.T) {
+	s, repo, _ := codingSession(&fakeExecutor{})
+	repo.assessment.Questions = []models.AssessmentQuestion{{QuestionID: "q1"}}
+
+	req := models.EditHistoryRequest{Seq: 1, Deltas: []models.CodeEditDelta{
+		{T: 300, P: 5, I: "!"},
+		{T: 100, P: 0, I: "hello"},
+		{T: 200, P: 5, I: " world"},
+	}}
+	if err := s.RecordEdits(context.Background(), "s1", "q1", req); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.edits) != 1 {
+		t.Fatalf("%d batches saved, want 1", len(repo.edits))
+	}
+	batch := repo.edits[0]
+	if batch.FirstT != 100 || batch.LastT != 300 || batch.DeltaCount != 3 {
+		t.Errorf("first_t/last_t/count = %d/%d/%d, want 100/300/3", batch.FirstT, batch.LastT, batch.DeltaCount)
+	}
+
+	deltas, err := decompressDeltas(batch.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// дельты сохраняются в порядке времени
+	if got, _ := applyDeltas("", deltas, batch.LastT); got != "hello! world" {
+		t.Errorf("stored deltas replay to %q, want %q", got, "hello! world")
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/easyhire/backend/internal/executor"
+)
+
+// ExecutorClient запуск кода в сервисе executor (cmd/executor)
+type ExecutorClient interface {
+	Execute(ctx context.Context, req executor.ExecuteRequest) (*executor.ExecuteResponse, error)
+}
+
+type httpExecutorClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewExecutorClient адрес executor берётся из EXECUTOR_URL (по умолчанию http://localhost:8090)
+func NewExecutorClient() ExecutorClient {
+	baseURL := os.Getenv("EXECUTOR_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8090"
+	}
+	return &httpExecutorClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+func (c *httpExecutorClient) Execute(ctx context.Context, req executor.ExecuteRequest) (*executor.ExecuteResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/execute", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("executor unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 408 — таймаут выполнения, тело всё равно содержит результат
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusRequestTimeout {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return nil, fmt.Errorf("executor returned %d: %s", resp.StatusCode, apiErr.Error)
+	}
+
+	var result executor.ExecuteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode executor response failed: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+)
+
+// criticalPercentage ниже этого процента пробел по компетенции считается критичным
+const criticalPercentage = 25.0
+
+// maxAIRecommendations рекомендаций модели в отзыве
+const maxAIRecommendations = 6
+
+const feedbackSystemPrompt = `You write short, constructive feedback for candidates of Go backend engineer assessments.
+Base every point on the competency results and the competency matrix you are given.
+Never mention specific questions, answers or scores of other people. Write in second person.
+Answer with a single JSON object and nothing else.`
+
+// ErrFeedbackHidden отзыв не показывается кандидату: в оценке выключен ShowExplanation
+var ErrFeedbackHidden = errors.New("feedback is not available for this assessment")
+
+// FeedbackService отзыв по результату: сильные стороны, пробелы и рекомендации по матрице компетенций.
+// Отзыв по правилам создаётся при подсчёте результата; модель может дополнить его рекомендациями.
+type FeedbackService interface {
+	GetResultFeedback(ctx context.Context, resultID string) (*models.ResultFeedback, error)
+	RegenerateFeedback(ctx context.Context, resultID string, useAI bool) (*models.ResultFeedback, error)
+	GetCandidateFeedback(ctx context.Context, sessionID, candidateID string) (*models.ResultFeedback, error)
+}
+
+type feedbackService struct {
+	assessmentRepo repository.AssessmentRepository
+	questionRepo   repository.QuestionRepository
+	provider       AIProvider
+}
+
+// NewFeedbackService provider может быть nil — тогда доступен только отзыв по правилам
+func NewFeedbackService(assessmentRepo repository.AssessmentRepository, questionRepo repository.QuestionRepository, provider AIProvider) FeedbackService {
+	return &feedbackService{
+		assessmentRepo: assessmentRepo,
+		questionRepo:   questionRepo,
+		provider:       provider,
+	}
+}
+
+// GetResultFeedback полный отзыв для HR и экспертов; для старых результатов создаётся при первом запросе
+func (s *feedbackService) GetResultFeedback(ctx context.Context, resultID string) (*models.ResultFeedback, error) {
+	result, err := s.assessmentRepo.GetResultByID(ctx, resultID)
+	if err != nil {
+		return nil, fmt.Errorf("result not found: %w", err)
+	}
+	if result.FeedbackGeneratedAt == nil {
+		return s.regenerate(ctx, result, false)
+	}
+	return resultFeedback(result, false), nil
+}
+
+// RegenerateFeedback пересчитывает отзыв по правилам, с useAI — дополняет рекомендациями модели
+func (s *feedbackService) RegenerateFeedback(ctx context.Context, resultID string, useAI bool) (*models.ResultFeedback, error) {
+	result, err := s.assessmentRepo.GetResultByID(ctx, resultID)
+	if err != nil {
+		return nil, fmt.Errorf("result not found: %w", err)
+	}
+	return s.regenerate(ctx, result, useAI)
+}
+
+// GetCandidateFeedback отзыв для кандидата по его сессии, без ссылок на вопросы.
+// Доступен, только если в оценке включён ShowExplanation.
+func (s *feedbackService) GetCandidateFeedback(ctx context.Context, sessionID, candidateID string) (*models.ResultFeedback, error) {
+	session, err := s.assessmentRepo.GetSessionByID(ctx, sessionID)
+	if err != nil || session.CandidateID != candidateID {
+		return nil, fmt.Errorf("session not found")
+	}
+	assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, session.AssessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("assessment not found: %w", err)
+	}
+	if !assessment.ShowExplanation {
+		return nil, ErrFeedbackHidden
+	}
+
+	result, err := s.assessmentRepo.GetResultBySessionID(ctx, sessionID)
+	if err != nil {
+		if session.Status == models.SessionStatusPendingReview {
+			return nil, ErrSessionPendingReview
+		}
+		return nil, fmt.Errorf("result not found: %w", err)
+	}
+	if result.FeedbackGeneratedAt == nil {
+		if _, err := s.regenerate(ctx, result, false); err != nil {
+			return nil, err
+		}
+	}
+	return resultFeedback(result, true), nil
+}
+
+func (s *feedbackService) regenerate(ctx context.Context, result *models.Result, useAI bool) (*models.ResultFeedback, error) {
+	if useAI && s.provider == nil {
+		return nil, ErrAIUnavailable
+	}
+
+	session, err := s.assessmentRepo.GetSessionByID(ctx, result.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, session.AssessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("assessment not found: %w", err)
+	}
+	answers, err := s.assessmentRepo.GetSessionAnswers(ctx, result.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("load answers failed: %w", err)
+	}
+	questions, err := s.questionRepo.GetQuestionsByIDs(ctx, answerQuestionIDs(answers))
+	if err != nil {
+		return nil, fmt.Errorf("load questions failed: %w", err)
+	}
+
+	feedback := buildFeedback(result, assessment.TargetLevel, answers, questions)
+	aiModel := ""
+	if useAI {
+		enriched, model, err := s.enrich(ctx, result, assessment.TargetLevel, feedback)
+		if err != nil {
+			return nil, err
+		}
+		feedback, aiModel = enriched, model
+	}
+
+	now := time.Now()
+	result.Feedback = feedback
+	result.FeedbackGeneratedAt = &now
+	result.FeedbackAIModel = aiModel
+	if err := s.assessmentRepo.UpdateResult(ctx, result); err != nil {
+		return nil, fmt.Errorf("update result failed: %w", err)
+	}
+	return resultFeedback(result, false), nil
+}
+
+// enrich добавляет к отзыву итог и рекомендации модели. Модель видит только проценты по компетенциям
+// и матрицу, не вопросы — поэтому её текст безопасно показывать кандидату.
+func (s *feedbackService) enrich(ctx context.Context, result *models.Result, targetLevel string, feedback []models.Feedback) ([]models.Feedback, string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Assessment result: %.0f%%, assessed level %s, target level %s.\n\nCompetencies:\n",
+		result.Percentage, strings.ToLower(result.Level), feedbackLevel(targetLevel))
+	known := map[string]bool{}
+	for _, c := range result.CompetencyBreakdown {
+		known[c.CompetencyID] = true
+		fmt.Fprintf(&b, "- %s: %.0f%% (%d of %d correct)", c.CompetencyID, c.Percentage, c.Correct, c.Questions)
+		if entry, ok := lookupMatrix(c.CompetencyID); ok {
+			fmt.Fprintf(&b, "; matrix \"%s\":", entry.Name)
+			for _, level := range matrixLevels {
+				fmt.Fprintf(&b, " %s — %s;", level, entry.Levels[level])
+			}
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nRule-based feedback already given:\n")
+	for _, f := range feedback {
+		fmt.Fprintf(&b, "- [%s] %s\n", f.Type, f.Message)
+	}
+	fmt.Fprintf(&b, `
+Write a two-sentence summary and at most %d concrete study recommendations (topics, practice ideas) that go beyond the rule-based feedback.
+Return JSON:
+{"summary": "...", "recommendations": [{"competency": "competency id from the list", "message": "...", "severity": "info|warning"}]}`, maxAIRecommendations)
+
+	completion, err := s.provider.Complete(ctx, AICompletionRequest{
+		System: feedbackSystemPrompt,
+		Prompt: b.String(),
+		JSON:   true,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	var resp struct {
+		Summary         string `json:"summary"`
+		Recommendations []struct {
+			Competency string `json:"competency"`
+			Message    string `json:"message"`
+			Severity   string `json:"severity"`
+		} `json:"recommendations"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(completion.Text)), &resp); err != nil {
+		return nil, "", fmt.Errorf("AI feedback is not a valid JSON object: %w", err)
+	}
+
+	enriched := append([]models.Feedback(nil), feedback...)
+	if summary := strings.TrimSpace(resp.Summary); summary != "" {
+		enriched = append(enriched, models.Feedback{
+			Type:     models.FeedbackTypeSummary,
+			Message:  summary,
+			Severity: models.FeedbackSeverityInfo,
+			Source:   models.FeedbackSourceAI,
+		})
+	}
+	added := 0
+	for _, r := range resp.Recommendations {
+		message := strings.TrimSpace(r.Message)
+		if message == "" || added == maxAIRecommendations {
+			continue
+		}
+		severity := models.FeedbackSeverityInfo
+		if r.Severity == models.FeedbackSeverityWarning {
+			severity = models.FeedbackSeverityWarning
+		}
+		item := models.Feedback{
+			Type:     models.FeedbackTypeRecommendation,
+			Message:  message,
+			Severity: severity,
+			Source:   models.FeedbackSourceAI,
+		}
+		// компетенции не из результата не принимаем — модель могла её придумать
+		if known[r.Competency] {
+			item.Competency = r.Competency
+			if entry, ok := lookupMatrix(r.Competency); ok {
+				item.Matrix = entry.Name
+			}
+		}
+		enriched = append(enriched, item)
+		added++
+	}
+
+	model := completion.Model
+	if model == "" {
+		model = s.provider.Model()
+	}
+	return enriched, s.provider.Name() + "/" + model, nil
+}
+
+// resultFeedback отзыв результата; candidateSafe убирает ссылки на вопросы банка
+func resultFeedback(result *models.Result, candidateSafe bool) *models.ResultFeedback {
+	feedback := make([]models.Feedback, 0, len(result.Feedback))
+	for _, f := range result.Feedback {
+		if candidateSafe {
+			f.QuestionIDs = nil
+		}
+		feedback = append(feedback, f)
+	}
+	out := &models.ResultFeedback{
+		ResultID:    result.ID,
+		SessionID:   result.SessionID,
+		Level:       result.Level,
+		Percentage:  result.Percentage,
+		Feedback:    feedback,
+		GeneratedAt: result.FeedbackGeneratedAt,
+	}
+	if !candidateSafe {
+		out.AIModel = result.FeedbackAIModel
+	}
+	return out
+}
+
+// ==========================
+// RULES
+// ==========================
+
+// missedCompetency неверные ответы по компетенции
+type missedCompetency struct {
+	questionIDs []string
+	lowestLevel models.DifficultyLevel
+}
+
+// buildFeedback отзыв по разбивке результата: сильные стороны (>= strengthPercentage),
+// пробелы (< improvementPercentage) с рекомендациями по матрице и шаг к следующему уровню для остальных
+func buildFeedback(result *models.Result, targetLevel string, answers []models.CandidateAnswer, questions []models.Question) []models.Feedback {
+	target := models.DifficultyLevel(feedbackLevel(targetLevel))
+
+	byID := make(map[string]models.Question, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+	missed := map[string]*missedCompetency{}
+	for _, a := range answers {
+		q, ok := byID[a.QuestionID]
+		if !ok || a.IsCorrect {
+			continue
+		}
+		m, ok := missed[q.Competency]
+		if !ok {
+			m = &missedCompetency{lowestLevel: q.Difficulty}
+			missed[q.Competency] = m
+		}
+		m.questionIDs = append(m.questionIDs, q.ID)
+		if i := levelIndex(q.Difficulty); i >= 0 && (levelIndex(m.lowestLevel) < 0 || i < levelIndex(m.lowestLevel)) {
+			m.lowestLevel = q.Difficulty
+		}
+	}
+
+	scores := append([]models.CompetencyScore(nil), result.CompetencyBreakdown...)
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Percentage > scores[j].Percentage })
+
+	feedback := []models.Feedback{{
+		Type:     models.FeedbackTypeSummary,
+		Message:  fmt.Sprintf("Overall result %.0f%%, assessed level: %s (target: %s).", result.Percentage, strings.ToLower(result.Level), target),
+		Severity: summarySeverity(result.Level, target),
+		Level:    string(target),
+		Source:   models.FeedbackSourceRules,
+	}}
+
+	var gaps, recommendations []models.Feedback
+	for _, c := range scores {
+		entry, mapped := lookupMatrix(c.CompetencyID)
+		name := c.CompetencyID
+		if mapped {
+			name = entry.Name
+		}
+		item := models.Feedback{
+			Competency: c.CompetencyID,
+			Matrix:     entry.Name,
+			Source:     models.FeedbackSourceRules,
+		}
+
+		switch {
+		case c.Percentage >= strengthPercentage:
+			level := models.DifficultyLevel(feedbackLevel(c.Level))
+			item.Type = models.FeedbackTypeStrength
+			item.Severity = models.FeedbackSeverityInfo
+			item.Level = string(level)
+			item.Message = fmt.Sprintf("%s: %.0f%% — strong result.", name, c.Percentage)
+			if mapped {
+				item.Message = fmt.Sprintf("%s: %.0f%% — confident with %s (%s level).", name, c.Percentage, entry.Levels[level], level)
+			}
+			feedback = append(feedback, item)
+
+		case c.Percentage < improvementPercentage:
+			item.Type = models.FeedbackTypeGap
+			item.Severity = models.FeedbackSeverityWarning
+			if c.Percentage < criticalPercentage {
+				item.Severity = models.FeedbackSeverityCritical
+			}
+			item.Level = string(target)
+			item.Message = fmt.Sprintf("%s: %.0f%% — below expectations (%d of %d correct).", name, c.Percentage, c.Correct, c.Questions)
+			if mapped {
+				item.Message = fmt.Sprintf("%s: %.0f%% — below expectations for the %s level: %s.", name, c.Percentage, target, entry.Levels[target])
+			}
+			if m, ok := missed[c.CompetencyID]; ok {
+				item.QuestionIDs = m.questionIDs
+			}
+			gaps = append(gaps, item)
+
+			// учиться стоит с самого простого уровня, на котором были ошибки
+			study := target
+			if m, ok := missed[c.CompetencyID]; ok && levelIndex(m.lowestLevel) >= 0 && levelIndex(m.lowestLevel) < levelIndex(target) {
+				study = m.lowestLevel
+			}
+			rec := item
+			rec.Type = models.FeedbackTypeRecommendation
+			rec.Severity = models.FeedbackSeverityInfo
+			rec.Level = string(study)
+			rec.QuestionIDs = nil
+			rec.Message = fmt.Sprintf("Review the fundamentals of %s.", name)
+			if mapped {
+				rec.Message = fmt.Sprintf("Study %s (%s level): %s.", name, study, entry.Levels[study])
+			}
+			recommendations = append(recommendations, rec)
+
+		default:
+			if !mapped {
+				continue
+			}
+			next := nextMatrixLevel(models.DifficultyLevel(feedbackLevel(c.Level)))
+			item.Type = models.FeedbackTypeRecommendation
+			item.Severity = models.FeedbackSeverityInfo
+			item.Level = string(next)
+			item.Message = fmt.Sprintf("%s: %.0f%%. To grow towards the %s level, practice %s.", name, c.Percentage, next, entry.Levels[next])
+			recommendations = append(recommendations, item)
+		}
+	}
+
+	feedback = append(feedback, gaps...)
+	return append(feedback, recommendations...)
+}
+
+// feedbackLevel уровень матрицы из уровня результата/оценки (MIDDLE → middle); trainee и неизвестные — junior
+func feedbackLevel(level string) string {
+	l := models.DifficultyLevel(strings.ToLower(strings.TrimSpace(level)))
+	if levelIndex(l) < 0 {
+		return string(models.DifficultyJunior)
+	}
+	return string(l)
+}
+
+func levelIndex(level models.DifficultyLevel) int {
+	for i, l := range matrixLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+func summarySeverity(level string, target models.DifficultyLevel) string {
+	achieved := levelIndex(models.DifficultyLevel(strings.ToLower(level)))
+	if achieved >= levelIndex(target) {
+		return models.FeedbackSeverityInfo
+	}
+	return models.FeedbackSeverityWarning
+}
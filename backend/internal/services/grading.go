@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/easyhire/backend/internal/executor"
+	"github.com/easyhire/backend/internal/models"
+)
+
+// reviewPassRatio доля баллов, начиная с которой ответ после ручной проверки считается верным
+const reviewPassRatio = 0.5
+
+// requiresManualReview вопросы, которые нельзя проверить автоматически
+func requiresManualReview(q models.Question) bool {
+	return q.Type == models.QuestionTypeArchitecture || q.Type == models.QuestionTypeDebugging
+}
+
+// autoGrade проверяет ответ на multiple_choice: кандидат присылает id или текст варианта.
+// Coding проверяется gradeCoding, остальные типы — экспертами.
+func autoGrade(answer *models.CandidateAnswer, q models.Question) {
+	if q.Type != models.QuestionTypeMultipleChoice {
+		return
+	}
+
+	answer.IsCorrect = false
+	answer.Score = 0
+
+	if opt := chosenOption(answer.Answer, q.Options); opt != nil && opt.IsCorrect {
+		answer.IsCorrect = true
+		answer.Score = float64(q.Points)
+	}
+
+	ratio := 0.0
+	if answer.IsCorrect {
+		ratio = 1
+	}
+	answer.CriterionScores = fillRubric(q.Rubric, ratio, models.ScoreSourceAuto)
+}
+
+// gradeCoding прогоняет код кандидата на тест-кейсах вопроса (stdin → stdout), как эталонное решение
+// при валидации. Баллы — доля пройденных тестов, верным ответ считается, если пройдены все.
+// false — автоматически не проверить (нет тест-кейсов или языка); ошибка — executor недоступен.
+func gradeCoding(ctx context.Context, exec ExecutorClient, answer *models.CandidateAnswer, q models.Question) (bool, error) {
+	if exec == nil || len(q.TestCases) == 0 {
+		return false, nil
+	}
+	code := answer.Code
+	if strings.TrimSpace(code) == "" {
+		code = answer.Answer
+	}
+
+	passed := 0
+	if strings.TrimSpace(code) != "" {
+		files, err := solutionFiles(q.SolutionLanguage, code)
+		if err != nil {
+			return false, nil
+		}
+		for _, tc := range q.TestCases {
+			resp, err := exec.Execute(ctx, executor.ExecuteRequest{
+				Language:       q.SolutionLanguage,
+				Mode:           "run",
+				Files:          files,
+				Stdin:          tc.Input,
+				TimeoutSeconds: solutionCheckTimeout,
+			})
+			if err != nil {
+				return false, err
+			}
+			if resp.Error == "" && resp.ExitCode == 0 && normalizeOutput(resp.Stdout) == normalizeOutput(tc.Expected) {
+				passed++
+			}
+		}
+	}
+
+	ratio := float64(passed) / float64(len(q.TestCases))
+	answer.IsCorrect = passed == len(q.TestCases)
+	answer.Score = float64(q.Points) * ratio
+	answer.CriterionScores = fillRubric(q.Rubric, ratio, models.ScoreSourceAuto)
+	return true, nil
+}
+
+// chosenOption вариант, который выбрал кандидат (по id или тексту); nil — ни один не совпал
+func chosenOption(answer string, options []models.QuestionOption) *models.QuestionOption {
+	chosen := strings.TrimSpace(answer)
+	for i, opt := range options {
+		if opt.ID == chosen || strings.EqualFold(strings.TrimSpace(opt.Text), chosen) {
+			return &options[i]
+		}
+	}
+	return nil
+}
+
+// fillRubric раскладывает долю ratio (0..1) по всем критериям рубрики — для автоматических проверок
+func fillRubric(rubric []models.RubricCriterion, ratio float64, source string) []models.CriterionScore {
+	if len(rubric) == 0 {
+		return nil
+	}
+	scores := make([]models.CriterionScore, 0, len(rubric))
+	for _, c := range rubric {
+		scores = append(scores, models.CriterionScore{
+			CriterionID: c.ID,
+			Name:        c.Name,
+			Points:      c.MaxPoints * ratio,
+			MaxPoints:   c.MaxPoints,
+			Source:      source,
+		})
+	}
+	return scores
+}
+
+// scoreRubric проверяет баллы по критериям и переводит их сумму в баллы вопроса (maxScore).
+// Баллы нужны по каждому критерию рубрики.
+func scoreRubric(rubric []models.RubricCriterion, inputs []models.CriterionScoreInput, maxScore float64, source string) ([]models.CriterionScore, float64, error) {
+	byID := make(map[string]models.CriterionScoreInput, len(inputs))
+	for _, in := range inputs {
+		byID[in.CriterionID] = in
+	}
+
+	var achieved, possible float64
+	scores := make([]models.CriterionScore, 0, len(rubric))
+	for _, c := range rubric {
+		in, ok := byID[c.ID]
+		if !ok {
+			return nil, 0, fmt.Errorf("missing score for criterion %q", c.Name)
+		}
+		if in.Points < 0 || in.Points > c.MaxPoints {
+			return nil, 0, fmt.Errorf("criterion %q: points must be between 0 and %.2f", c.Name, c.MaxPoints)
+		}
+		delete(byID, c.ID)
+
+		achieved += in.Points
+		possible += c.MaxPoints
+		scores = append(scores, models.CriterionScore{
+			CriterionID: c.ID,
+			Name:        c.Name,
+			Points:      in.Points,
+			MaxPoints:   c.MaxPoints,
+			Comment:     in.Comment,
+			Source:      source,
+		})
+	}
+	for id := range byID {
+		return nil, 0, fmt.Errorf("unknown criterion %s", id)
+	}
+
+	total := 0.0
+	if possible > 0 {
+		total = achieved / possible * maxScore
+	}
+	return scores, total, nil
+}
+
+// applyReviewScore переносит оценку эксперта в ответ
+func applyReviewScore(answer *models.CandidateAnswer, score, maxScore float64, criteria []models.CriterionScore) {
+	answer.Score = score
+	answer.IsCorrect = maxScore > 0 && score >= maxScore*reviewPassRatio
+	answer.CriterionScores = criteria
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+	coremodels "github.com/easyhire/internal/models"
+	"github.com/easyhire/internal/pkg/auth"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvitationExpired   = errors.New("invitation has expired")
+	ErrInvitationCompleted = errors.New("invitation has already been completed")
+	// ErrInvitationTaken приглашение уже принято другим аккаунтом
+	ErrInvitationTaken = errors.New("invitation has been accepted by another account")
+	// ErrInvitationEmailMismatch авторизованный пользователь — не тот, кого пригласили
+	ErrInvitationEmailMismatch = errors.New("invitation was sent to a different email")
+	ErrInvalidCredentials      = errors.New("invalid email or password")
+	// ErrInvalidAccountDetails для нового аккаунта нужны имя и достаточно надёжный пароль
+	ErrInvalidAccountDetails = errors.New("invalid account details")
+)
+
+// InvitationService приглашение со стороны кандидата: ссылка из письма открывается, приглашение
+// принимается (аккаунт создаётся или привязывается) и по нему начинается сессия.
+// Статус: pending/sent → opened → accepted (сессия начата) → completed (сессия сдана).
+type InvitationService interface {
+	OpenInvitation(ctx context.Context, token string) (*models.InvitationView, error)
+	// AcceptInvitation userID — авторизованный пользователь ("" — вход по паролю или регистрация);
+	// возвращает и аккаунт кандидата, чтобы выдать ему токены
+	AcceptInvitation(ctx context.Context, token string, req models.AcceptInvitationRequest, userID string) (*models.InvitationAcceptance, *coremodels.User, error)
+}
+
+type invitationService struct {
+	assessmentRepo    repository.AssessmentRepository
+	userRepo          repository.UserRepository
+	assessmentService AssessmentService
+	passwordService   *auth.PasswordService
+}
+
+func NewInvitationService(
+	assessmentRepo repository.AssessmentRepository,
+	userRepo repository.UserRepository,
+	assessmentService AssessmentService,
+	passwordService *auth.PasswordService,
+) InvitationService {
+	return &invitationService{
+		assessmentRepo:    assessmentRepo,
+		userRepo:          userRepo,
+		assessmentService: assessmentService,
+		passwordService:   passwordService,
+	}
+}
+
+// ==========================
+// INVITATION FLOW
+// ==========================
+
+// OpenInvitation приглашение по токену; первое открытие отмечается (opened_at, статус opened)
+func (s *invitationService) OpenInvitation(ctx context.Context, token string) (*models.InvitationView, error) {
+	invitation, err := s.invitationByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, invitation.AssessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("assessment not found: %w", err)
+	}
+
+	now := time.Now()
+	changed := false
+	if invitation.OpenedAt == nil {
+		invitation.OpenedAt = &now
+		changed = true
+	}
+	if invitation.Status == models.InvitationStatusPending || invitation.Status == models.InvitationStatusSent {
+		invitation.Status = models.InvitationStatusOpened
+		changed = true
+	}
+	if changed {
+		if err := s.assessmentRepo.UpdateInvitation(ctx, invitation); err != nil {
+			return nil, fmt.Errorf("update invitation failed: %w", err)
+		}
+	}
+
+	_, err = s.userRepo.GetUserByEmail(ctx, invitation.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("load account failed: %w", err)
+	}
+
+	view := &models.InvitationView{
+		Invitation:     invitation,
+		Title:          assessment.Title,
+		Description:    assessment.Description,
+		TimeLimit:      assessment.TimeLimit,
+		TotalQuestions: assessment.TotalQuestions,
+		Availability:   assessment.Availability,
+		AccountExists:  err == nil,
+	}
+	if invitation.Availability.IsSet() {
+		view.Availability = invitation.Availability
+	}
+	return view, nil
+}
+
+// AcceptInvitation привязывает кандидата к приглашению и начинает сессию по нему.
+// Если начать сессию пока нельзя (окно не открылось, попытки исчерпаны), приглашение всё равно
+// принимается, а причина возвращается в Availability. Приглашение сохраняется принятым только
+// после попытки начать сессию: при ошибке оно остаётся как было, а созданный аккаунт кандидата
+// позволяет повторить принятие со своим паролем.
+func (s *invitationService) AcceptInvitation(ctx context.Context, token string, req models.AcceptInvitationRequest, userID string) (*models.InvitationAcceptance, *coremodels.User, error) {
+	invitation, err := s.invitationByToken(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if invitation.Status == models.InvitationStatusCompleted {
+		return nil, nil, ErrInvitationCompleted
+	}
+	user, created, err := s.resolveCandidate(ctx, invitation, req, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	candidateID := user.ID.String()
+	if invitation.CandidateID != nil && *invitation.CandidateID != candidateID {
+		return nil, nil, ErrInvitationTaken
+	}
+	invitation.CandidateID = &candidateID
+
+	acceptance := &models.InvitationAcceptance{Invitation: invitation, AccountCreated: created}
+	session, err := s.assessmentService.StartInvitationSession(ctx, invitation)
+	var availabilityErr *AvailabilityError
+	switch {
+	case err == nil:
+		acceptance.Session = session
+	case errors.As(err, &availabilityErr):
+		acceptance.Availability = availabilityErr.Status
+	default:
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if invitation.OpenedAt == nil {
+		invitation.OpenedAt = &now
+	}
+	if invitation.Status != models.InvitationStatusAccepted {
+		invitation.Status = models.InvitationStatusAccepted
+		invitation.AcceptedAt = &now
+	}
+	if err := s.assessmentRepo.UpdateInvitation(ctx, invitation); err != nil {
+		return nil, nil, fmt.Errorf("update invitation failed: %w", err)
+	}
+	return acceptance, user, nil
+}
+
+// invitationByToken приглашение, которое ещё можно открыть; просроченное помечается expired
+func (s *invitationService) invitationByToken(ctx context.Context, token string) (*models.Invitation, error) {
+	invitation, err := s.assessmentRepo.GetInvitationByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("invitation not found: %w", err)
+	}
+	if invitation.Status == models.InvitationStatusExpired {
+		return nil, ErrInvitationExpired
+	}
+	// принятое приглашение не истекает: кандидат возвращается по ссылке к своей сессии
+	accepted := invitation.Status == models.InvitationStatusAccepted || invitation.Status == models.InvitationStatusCompleted
+	if !accepted && time.Now().After(invitation.ExpiresAt) {
+		invitation.Status = models.InvitationStatusExpired
+		if err := s.assessmentRepo.UpdateInvitation(ctx, invitation); err != nil {
+			log.Printf("⚠️ mark invitation expired failed: %v (invitation %s)", err, invitation.ID)
+		}
+		return nil, ErrInvitationExpired
+	}
+	return invitation, nil
+}
+
+// resolveCandidate аккаунт, на который принимается приглашение: авторизованный пользователь
+// с приглашённым email, существующий аккаунт этого email по паролю или новый аккаунт кандидата
+func (s *invitationService) resolveCandidate(ctx context.Context, invitation *models.Invitation, req models.AcceptInvitationRequest, userID string) (*coremodels.User, bool, error) {
+	if userID != "" {
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, false, fmt.Errorf("user not found: %w", err)
+		}
+		if !strings.EqualFold(user.Email, invitation.Email) {
+			return nil, false, ErrInvitationEmailMismatch
+		}
+		return user, false, nil
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, invitation.Email)
+	switch {
+	case err == nil:
+		if !user.IsActive || req.Password == "" || s.passwordService.Compare(user.PasswordHash, req.Password) != nil {
+			return nil, false, ErrInvalidCredentials
+		}
+		return user, false, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, false, fmt.Errorf("load account failed: %w", err)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, false, fmt.Errorf("%w: name is required", ErrInvalidAccountDetails)
+	}
+	if problems := s.passwordService.ValidateStrength(req.Password); len(problems) > 0 {
+		return nil, false, fmt.Errorf("%w: %s", ErrInvalidAccountDetails, strings.Join(problems, "; "))
+	}
+	hash, err := s.passwordService.Hash(req.Password)
+	if err != nil {
+		return nil, false, fmt.Errorf("hash password failed: %w", err)
+	}
+	user = &coremodels.User{
+		Email:        invitation.Email,
+		PasswordHash: hash,
+		Name:         name,
+		Role:         coremodels.RoleCandidate,
+		IsActive:     true,
+	}
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return nil, false, fmt.Errorf("create account failed: %w", err)
+	}
+	return user, true, nil
+}
+
+// ==========================
+// SESSION → INVITATION STATUS
+// ==========================
+
+// acceptInvitation приглашение сессии переходит в accepted и привязывается к кандидату.
+// Ошибка не мешает сессии — её только логируют.
+func (s *assessmentService) acceptInvitation(ctx context.Context, session *models.AssessmentSession, now time.Time) {
+	if session.InvitationID == nil {
+		return
+	}
+	invitation, err := s.assessmentRepo.GetInvitationByID(ctx, *session.InvitationID)
+	if err != nil {
+		log.Printf("⚠️ load invitation failed: %v (session %s)", err, session.ID)
+		return
+	}
+	changed := false
+	if invitation.CandidateID == nil {
+		invitation.CandidateID = &session.CandidateID
+		changed = true
+	}
+	switch invitation.Status {
+	case models.InvitationStatusPending, models.InvitationStatusSent, models.InvitationStatusOpened, "":
+		invitation.Status = models.InvitationStatusAccepted
+		invitation.AcceptedAt = &now
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	if err := s.assessmentRepo.UpdateInvitation(ctx, invitation); err != nil {
+		log.Printf("⚠️ accept invitation failed: %v (invitation %s)", err, invitation.ID)
+	}
+}
+
+// completeInvitation приглашение сданной сессии переходит в completed
+func (s *assessmentService) completeInvitation(ctx context.Context, session *models.AssessmentSession, now time.Time) {
+	if session.InvitationID == nil {
+		return
+	}
+	invitation, err := s.assessmentRepo.GetInvitationByID(ctx, *session.InvitationID)
+	if err != nil {
+		log.Printf("⚠️ load invitation failed: %v (session %s)", err, session.ID)
+		return
+	}
+	if invitation.Status == models.InvitationStatusCompleted {
+		return
+	}
+	invitation.Status = models.InvitationStatusCompleted
+	invitation.CompletedAt = &now
+	if err := s.assessmentRepo.UpdateInvitation(ctx, invitation); err != nil {
+		log.Printf("⚠️ complete invitation failed: %v (invitation %s)", err, invitation.ID)
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"math"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+const (
+	defaultCalibrationMinResponses  = 30
+	defaultCalibrationMaxIterations = 50
+	calibrationTolerance            = 1e-3
+
+	// сетка квадратуры для EAP-оценки θ
+	abilityGridMin   = -4.0
+	abilityGridMax   = 4.0
+	abilityGridSteps = 81
+
+	// границы параметров, чтобы вопросы со 100% / 0% верных ответов не улетали в бесконечность
+	minDiscrimination = 0.1
+	maxDiscrimination = 4.0
+	maxAbsDifficulty  = 6.0
+)
+
+// irtItem параметры вопроса в ходе калибровки
+type irtItem struct {
+	a, b float64
+}
+
+// irtProbability вероятность верного ответа в модели 2PL
+func irtProbability(theta, a, b float64) float64 {
+	return 1 / (1 + math.Exp(-a*(theta-b)))
+}
+
+type irtObservation struct {
+	item    int
+	correct bool
+}
+
+// estimateAbility EAP-оценка θ по ответам на откалиброванные вопросы с априорным N(0, 1).
+// В отличие от ML, оценка конечна и при всех верных (неверных) ответах.
+func estimateAbility(obs []irtObservation, items []irtItem) (theta, stdErr float64) {
+	var sumW, sumWT, sumWT2 float64
+	for _, t := range abilityGrid() {
+		logL := -t * t / 2
+		for _, o := range obs {
+			p := irtProbability(t, items[o.item].a, items[o.item].b)
+			p = math.Min(math.Max(p, 1e-9), 1-1e-9)
+			if o.correct {
+				logL += math.Log(p)
+			} else {
+				logL += math.Log(1 - p)
+			}
+		}
+		w := math.Exp(logL)
+		sumW += w
+		sumWT += w * t
+		sumWT2 += w * t * t
+	}
+	if sumW == 0 {
+		return 0, 1
+	}
+
+	theta = sumWT / sumW
+	variance := sumWT2/sumW - theta*theta
+	return theta, math.Sqrt(math.Max(variance, 0))
+}
+
+// abilityGrid узлы квадратуры
+func abilityGrid() []float64 {
+	step := (abilityGridMax - abilityGridMin) / float64(abilityGridSteps-1)
+	grid := make([]float64, abilityGridSteps)
+	for k := range grid {
+		grid[k] = abilityGridMin + float64(k)*step
+	}
+	return grid
+}
+
+// fitItem M-шаг для одного вопроса: Ньютон-Рафсон по ожидаемым числам ответов в узлах сетки
+// (n — сколько кандидатов «в узле», r — сколько из них ответили верно).
+// Параметризация logit = a·θ + c (b = -c/a); слабые априорные a ~ N(1, 1), c ~ N(0, 4)
+// держат оценки конечными на маленьких выборках.
+func fitItem(item irtItem, grid, n, r []float64) irtItem {
+	a := item.a
+	c := -item.a * item.b
+
+	for iter := 0; iter < 10; iter++ {
+		ga, gc := -(a - 1), -c/4
+		haa, hac, hcc := -1.0, 0.0, -0.25
+
+		for k, t := range grid {
+			if n[k] == 0 {
+				continue
+			}
+			p := 1 / (1 + math.Exp(-(a*t + c)))
+			w := n[k] * p * (1 - p)
+			resid := r[k] - n[k]*p
+			ga += resid * t
+			gc += resid
+			haa -= w * t * t
+			hac -= w * t
+			hcc -= w
+		}
+
+		det := haa*hcc - hac*hac
+		if det == 0 {
+			break
+		}
+		da := (hcc*ga - hac*gc) / det
+		dc := (haa*gc - hac*ga) / det
+		a -= da
+		c -= dc
+		a = math.Min(math.Max(a, minDiscrimination), maxDiscrimination)
+
+		if math.Abs(da) < calibrationTolerance && math.Abs(dc) < calibrationTolerance {
+			break
+		}
+	}
+
+	b := math.Min(math.Max(-c/a, -maxAbsDifficulty), maxAbsDifficulty)
+	return irtItem{a: a, b: b}
+}
+
+// irtCalibration результат калибровки
+type irtCalibration struct {
+	params        []models.IRTParams
+	skipped       int
+	sessions      int
+	iterations    int
+	converged     bool
+	logLikelihood float64
+}
+
+// calibrate2PL подбирает a и b каждого вопроса методом маргинального максимального правдоподобия
+// (EM Бока-Эйткина): θ кандидатов не оцениваются точечно, а интегрируются по сетке с априорным N(0, 1).
+// Параметры возвращаются только для вопросов с minResponses ответов и больше.
+func calibrate2PL(responses []models.ItemResponse, minResponses, maxIterations int) irtCalibration {
+	// индексы вопросов и сессий
+	itemIndex := map[string]int{}
+	var itemIDs []string
+	sessionIndex := map[string]int{}
+	var bySession [][]irtObservation
+	for _, r := range responses {
+		j, ok := itemIndex[r.QuestionID]
+		if !ok {
+			j = len(itemIDs)
+			itemIndex[r.QuestionID] = j
+			itemIDs = append(itemIDs, r.QuestionID)
+		}
+		i, ok := sessionIndex[r.SessionID]
+		if !ok {
+			i = len(bySession)
+			sessionIndex[r.SessionID] = i
+			bySession = append(bySession, nil)
+		}
+		bySession[i] = append(bySession[i], irtObservation{item: j, correct: r.Correct})
+	}
+
+	answered := make([]int, len(itemIDs))
+	right := make([]int, len(itemIDs))
+	for _, obs := range bySession {
+		for _, o := range obs {
+			answered[o.item]++
+			if o.correct {
+				right[o.item]++
+			}
+		}
+	}
+
+	// начальные значения: a = 1, b = -logit(доля верных)
+	items := make([]irtItem, len(itemIDs))
+	for j := range items {
+		p := (float64(right[j]) + 0.5) / (float64(answered[j]) + 1)
+		items[j] = irtItem{a: 1, b: -math.Log(p / (1 - p))}
+	}
+
+	grid := abilityGrid()
+	// логарифмы весов узлов N(0, 1), нормированные на сетке
+	prior := make([]float64, len(grid))
+	var priorSum float64
+	for k, t := range grid {
+		prior[k] = -t * t / 2
+		priorSum += math.Exp(prior[k])
+	}
+	for k := range prior {
+		prior[k] -= math.Log(priorSum)
+	}
+
+	result := irtCalibration{sessions: len(bySession)}
+	logPost := make([]float64, len(grid))
+	for iter := 1; iter <= maxIterations; iter++ {
+		result.iterations = iter
+
+		// E-шаг: апостериорные веса узлов для каждой сессии → ожидаемые n и r по вопросам
+		n := make([][]float64, len(items))
+		r := make([][]float64, len(items))
+		for j := range items {
+			n[j] = make([]float64, len(grid))
+			r[j] = make([]float64, len(grid))
+		}
+		result.logLikelihood = 0
+
+		for _, obs := range bySession {
+			maxLog := math.Inf(-1)
+			for k, t := range grid {
+				logPost[k] = prior[k]
+				for _, o := range obs {
+					p := irtProbability(t, items[o.item].a, items[o.item].b)
+					p = math.Min(math.Max(p, 1e-9), 1-1e-9)
+					if o.correct {
+						logPost[k] += math.Log(p)
+					} else {
+						logPost[k] += math.Log(1 - p)
+					}
+				}
+				maxLog = math.Max(maxLog, logPost[k])
+			}
+
+			var sum float64
+			for k := range grid {
+				logPost[k] = math.Exp(logPost[k] - maxLog)
+				sum += logPost[k]
+			}
+			result.logLikelihood += maxLog + math.Log(sum)
+
+			for k := range grid {
+				w := logPost[k] / sum
+				for _, o := range obs {
+					n[o.item][k] += w
+					if o.correct {
+						r[o.item][k] += w
+					}
+				}
+			}
+		}
+
+		// M-шаг
+		maxChange := 0.0
+		for j := range items {
+			next := fitItem(items[j], grid, n[j], r[j])
+			maxChange = math.Max(maxChange, math.Max(math.Abs(next.a-items[j].a), math.Abs(next.b-items[j].b)))
+			items[j] = next
+		}
+
+		if maxChange < calibrationTolerance {
+			result.converged = true
+			break
+		}
+	}
+	result.logLikelihood = round4(result.logLikelihood)
+
+	for j, id := range itemIDs {
+		if answered[j] < minResponses {
+			result.skipped++
+			continue
+		}
+		result.params = append(result.params, models.IRTParams{
+			QuestionID:     id,
+			Discrimination: round4(items[j].a),
+			Difficulty:     round4(items[j].b),
+			Responses:      answered[j],
+			CorrectRate:    round4(float64(right[j]) / float64(answered[j])),
+		})
+	}
+	return result
+}
+
+// abilityFromAnswers θ кандидата по ответам на откалиброванные вопросы
+// (nil, если таких вопросов меньше minItems)
+func abilityFromAnswers(answers []models.CandidateAnswer, byID map[string]models.Question, minItems int) *models.AbilityEstimate {
+	var items []irtItem
+	var obs []irtObservation
+	for _, answer := range answers {
+		q, ok := byID[answer.QuestionID]
+		if !ok || !q.IsCalibrated() {
+			continue
+		}
+		obs = append(obs, irtObservation{item: len(items), correct: answer.IsCorrect})
+		items = append(items, irtItem{a: *q.IRTDiscrimination, b: *q.IRTDifficulty})
+	}
+	if len(obs) == 0 || len(obs) < minItems {
+		return nil
+	}
+
+	theta, stdErr := estimateAbility(obs, items)
+	return &models.AbilityEstimate{
+		Theta:    round4(theta),
+		StdError: round4(stdErr),
+		Items:    len(obs),
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+func TestEstimateAbilityPrior(t *testing.T) {
+	// без ответов EAP совпадает с априорным N(0, 1)
+	theta, stdErr := estimateAbility(nil, nil)
+	if math.Abs(theta) > 1e-9 || math.Abs(stdErr-1) > 0.01 {
+		t.Errorf("no answers: theta = %v, std_error = %v, want 0 and 1", theta, stdErr)
+	}
+}
+
+func TestEstimateAbilityExtremes(t *testing.T) {
+	items := []irtItem{{a: 1.2, b: -1}, {a: 1, b: 0}, {a: 1.5, b: 1}}
+	obs := func(correct bool) []irtObservation {
+		var o []irtObservation
+		for j := range items {
+			o = append(o, irtObservation{item: j, correct: correct})
+		}
+		return o
+	}
+
+	high, highErr := estimateAbility(obs(true), items)
+	low, lowErr := estimateAbility(obs(false), items)
+
+	// при всех верных (неверных) ответах оценка конечна и лежит внутри сетки
+	if high <= 0 || high >= abilityGridMax {
+		t.Errorf("all correct: theta = %v, want in (0, %v)", high, abilityGridMax)
+	}
+	if low >= 0 || low <= abilityGridMin {
+		t.Errorf("all wrong: theta = %v, want in (%v, 0)", low, abilityGridMin)
+	}
+	if highErr <= 0 || highErr >= 1 || lowErr <= 0 || lowErr >= 1 {
+		t.Errorf("std errors = %v, %v, want in (0, 1)", highErr, lowErr)
+	}
+}
+
+func TestEstimateAbilityMonotonic(t *testing.T) {
+	items := []irtItem{{a: 1, b: -1}, {a: 1, b: -0.5}, {a: 1, b: 0}, {a: 1, b: 0.5}, {a: 1, b: 1}}
+
+	prev := math.Inf(-1)
+	for solved := 0; solved <= len(items); solved++ {
+		var obs []irtObservation
+		for j := range items {
+			obs = append(obs, irtObservation{item: j, correct: j < solved})
+		}
+		theta, _ := estimateAbility(obs, items)
+		if theta <= prev {
+			t.Errorf("%d solved: theta = %v, not above %v", solved, theta, prev)
+		}
+		prev = theta
+	}
+}
+
+func TestCalibrate2PLRecoversParameters(t *testing.T) {
+	truth := []irtItem{{a: 1.0, b: -1.5}, {a: 1.5, b: -0.5}, {a: 0.8, b: 0}, {a: 1.2, b: 0.7}, {a: 1.0, b: 1.5}}
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	var responses []models.ItemResponse
+	for i := 0; i < 3000; i++ {
+		theta := rng.NormFloat64()
+		for j, item := range truth {
+			responses = append(responses, models.ItemResponse{
+				SessionID:  fmt.Sprintf("s%d", i),
+				QuestionID: fmt.Sprintf("q%d", j),
+				Correct:    rng.Float64() < irtProbability(theta, item.a, item.b),
+			})
+		}
+	}
+	// вопрос с малым числом ответов калибруется вместе со всеми, но в результат не попадает
+	responses = append(responses, models.ItemResponse{SessionID: "s0", QuestionID: "rare", Correct: true})
+
+	cal := calibrate2PL(responses, defaultCalibrationMinResponses, defaultCalibrationMaxIterations)
+
+	if !cal.converged {
+		t.Errorf("calibration did not converge in %d iterations", cal.iterations)
+	}
+	if cal.sessions != 3000 || cal.skipped != 1 || len(cal.params) != len(truth) {
+		t.Fatalf("sessions = %d, skipped = %d, params = %d, want 3000, 1, %d", cal.sessions, cal.skipped, len(cal.params), len(truth))
+	}
+	for j, p := range cal.params {
+		want := truth[j]
+		if p.QuestionID != fmt.Sprintf("q%d", j) {
+			t.Fatalf("params[%d] is %s", j, p.QuestionID)
+		}
+		if math.Abs(p.Difficulty-want.b) > 0.25 {
+			t.Errorf("%s: difficulty = %v, want %v", p.QuestionID, p.Difficulty, want.b)
+		}
+		if math.Abs(p.Discrimination-want.a) > 0.3 {
+			t.Errorf("%s: discrimination = %v, want %v", p.QuestionID, p.Discrimination, want.a)
+		}
+		if p.Responses != 3000 {
+			t.Errorf("%s: responses = %d, want 3000", p.QuestionID, p.Responses)
+		}
+	}
+}
+
+// Вопрос, на который все ответили верно, не уходит в бесконечность
+func TestCalibrate2PLBoundsDegenerateItems(t *testing.T) {
+	var responses []models.ItemResponse
+	for i := 0; i < 50; i++ {
+		session := fmt.Sprintf("s%d", i)
+		responses = append(responses,
+			models.ItemResponse{SessionID: session, QuestionID: "easy", Correct: true},
+			models.ItemResponse{SessionID: session, QuestionID: "mixed", Correct: i%2 == 0},
+		)
+	}
+
+	cal := calibrate2PL(responses, 10, defaultCalibrationMaxIterations)
+	for _, p := range cal.params {
+		if math.IsNaN(p.Difficulty) || math.Abs(p.Difficulty) > maxAbsDifficulty {
+			t.Errorf("%s: difficulty = %v, want within ±%v", p.QuestionID, p.Difficulty, maxAbsDifficulty)
+		}
+		if p.Discrimination < minDiscrimination || p.Discrimination > maxDiscrimination {
+			t.Errorf("%s: discrimination = %v, want in [%v, %v]", p.QuestionID, p.Discrimination, minDiscrimination, maxDiscrimination)
+		}
+	}
+}
+
+func TestAbilityFromAnswersMinItems(t *testing.T) {
+	a, b := 1.0, 0.0
+	calibrated := models.Question{IRTDiscrimination: &a, IRTDifficulty: &b}
+	byID := map[string]models.Question{"q1": calibrated, "q2": calibrated, "q3": {}}
+	answers := []models.CandidateAnswer{
+		{QuestionID: "q1", IsCorrect: true},
+		{QuestionID: "q2", IsCorrect: true},
+		{QuestionID: "q3", IsCorrect: true}, // не откалиброван
+	}
+
+	if got := abilityFromAnswers(answers, byID, 3); got != nil {
+		t.Errorf("2 calibrated of 3 required: got %+v, want nil", got)
+	}
+	got := abilityFromAnswers(answers, byID, 2)
+	if got == nil || got.Items != 2 || got.Theta <= 0 {
+		t.Errorf("got %+v, want estimate over 2 items with positive theta", got)
+	}
+}
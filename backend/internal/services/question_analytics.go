@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+)
+
+const (
+	// analyticsMinResponses меньше ответов — флаги не ставим, статистика ненадёжна
+	analyticsMinResponses = 20
+
+	tooEasyCorrectRate      = 0.95
+	tooHardCorrectRate      = 0.10
+	lowDiscrimination       = 0.15
+	unusedDistractorRate    = 0.05
+	slowMedianTimeRatio     = 1.0 // медиана времени выше лимита
+	analyticsMaxBankResults = 500
+)
+
+// GetQuestionAnalytics статистика одного вопроса
+func (s *questionService) GetQuestionAnalytics(ctx context.Context, questionID string) (*models.QuestionAnalytics, error) {
+	question, err := s.questionRepo.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+
+	samples, err := s.questionRepo.GetAnswerSamples(ctx, []string{questionID})
+	if err != nil {
+		return nil, fmt.Errorf("load answers failed: %w", err)
+	}
+
+	analytics := analyzeQuestion(*question, samples, activeTimeBonuses(ctx, s.scoringService))
+	return &analytics, nil
+}
+
+// ListQuestionAnalytics статистика по банку вопросов (фильтр как у списка вопросов).
+// flaggedOnly — только вопросы с флагами.
+func (s *questionService) ListQuestionAnalytics(ctx context.Context, filter repository.QuestionFilter, flaggedOnly bool) ([]models.QuestionAnalytics, error) {
+	if filter.Limit <= 0 || filter.Limit > analyticsMaxBankResults {
+		filter.Limit = analyticsMaxBankResults
+	}
+
+	listed, _, err := s.questionRepo.ListQuestions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list questions failed: %w", err)
+	}
+	ids := make([]string, 0, len(listed))
+	for _, q := range listed {
+		ids = append(ids, q.ID)
+	}
+
+	// варианты ответов нужны для анализа дистракторов
+	questions, err := s.questionRepo.GetQuestionsByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load questions failed: %w", err)
+	}
+	samples, err := s.questionRepo.GetAnswerSamples(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load answers failed: %w", err)
+	}
+
+	byQuestion := map[string][]models.QuestionAnswerSample{}
+	for _, sample := range samples {
+		byQuestion[sample.QuestionID] = append(byQuestion[sample.QuestionID], sample)
+	}
+
+	bonuses := activeTimeBonuses(ctx, s.scoringService)
+	result := make([]models.QuestionAnalytics, 0, len(questions))
+	for _, q := range questions {
+		a := analyzeQuestion(q, byQuestion[q.ID], bonuses)
+		if flaggedOnly && len(a.Flags) == 0 {
+			continue
+		}
+		result = append(result, a)
+	}
+
+	// сначала сломанные, потом больше флагов
+	sort.SliceStable(result, func(i, j int) bool {
+		bi, bj := hasBrokenFlag(result[i]), hasBrokenFlag(result[j])
+		if bi != bj {
+			return bi
+		}
+		return len(result[i].Flags) > len(result[j].Flags)
+	})
+	return result, nil
+}
+
+// analyzeQuestion считает статистику вопроса по ответам.
+// Point-biserial считается с итоговым процентом сессии (вопрос в него входит, поэтому на
+// коротких оценках корреляция немного завышена).
+func analyzeQuestion(q models.Question, samples []models.QuestionAnswerSample, bonuses models.TimeBonusConfig) models.QuestionAnalytics {
+	a := models.QuestionAnalytics{
+		QuestionID: q.ID,
+		Title:      q.Title,
+		Type:       q.Type,
+		Difficulty: q.Difficulty,
+		Competency: q.Competency,
+		Responses:  len(samples),
+		TimeLimit:  questionTimeLimit(q, bonuses),
+		Sufficient: len(samples) >= analyticsMinResponses,
+		Flags:      []models.QuestionFlag{},
+	}
+	if len(samples) == 0 {
+		return a
+	}
+
+	totals := make([]float64, len(samples))
+	correct := make([]bool, len(samples))
+	var times []float64
+	for i, sample := range samples {
+		totals[i] = sample.TotalPercentage
+		correct[i] = sample.IsCorrect
+		if sample.IsCorrect {
+			a.Correct++
+		}
+		if sample.Submitted {
+			a.Answered++
+			times = append(times, float64(sample.TimeSpent))
+		}
+	}
+	a.CorrectRate = round4(float64(a.Correct) / float64(len(samples)))
+	a.SkipRate = round4(float64(len(samples)-a.Answered) / float64(len(samples)))
+	a.AvgTime, a.MedianTime = timeStats(times)
+	a.PointBiserial = pointBiserial(correct, totals)
+
+	if q.Type == models.QuestionTypeMultipleChoice && len(q.Options) > 0 {
+		a.Options, a.Unmatched = analyzeOptions(q.Options, samples, totals)
+	}
+
+	if a.Sufficient {
+		a.Flags = questionFlags(a)
+	}
+	return a
+}
+
+// analyzeOptions частота выбора каждого варианта среди отвеченных
+func analyzeOptions(options []models.QuestionOption, samples []models.QuestionAnswerSample, totals []float64) ([]models.OptionAnalytics, int) {
+	chosen := make([][]bool, len(options))
+	for k := range options {
+		chosen[k] = make([]bool, len(samples))
+	}
+
+	answered, unmatched := 0, 0
+	for i, sample := range samples {
+		if !sample.Submitted {
+			continue
+		}
+		answered++
+		opt := chosenOption(sample.Answer, options)
+		if opt == nil {
+			unmatched++
+			continue
+		}
+		for k := range options {
+			if options[k].ID == opt.ID {
+				chosen[k][i] = true
+			}
+		}
+	}
+
+	result := make([]models.OptionAnalytics, 0, len(options))
+	for k, opt := range options {
+		oa := models.OptionAnalytics{
+			OptionID:  opt.ID,
+			Text:      opt.Text,
+			IsCorrect: opt.IsCorrect,
+		}
+		var sum float64
+		for i, c := range chosen[k] {
+			if c {
+				oa.Chosen++
+				sum += totals[i]
+			}
+		}
+		if answered > 0 {
+			oa.Rate = round4(float64(oa.Chosen) / float64(answered))
+		}
+		if oa.Chosen > 0 {
+			mean := round4(sum / float64(oa.Chosen))
+			oa.MeanTotal = &mean
+		}
+		oa.PointBiserial = pointBiserial(chosen[k], totals)
+		result = append(result, oa)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Chosen > result[j].Chosen })
+	return result, unmatched
+}
+
+func questionFlags(a models.QuestionAnalytics) []models.QuestionFlag {
+	flags := []models.QuestionFlag{}
+	add := func(code string, broken bool, format string, args ...interface{}) {
+		flags = append(flags, models.QuestionFlag{Code: code, Message: fmt.Sprintf(format, args...), Broken: broken})
+	}
+
+	if a.CorrectRate > tooEasyCorrectRate {
+		add(models.QuestionFlagTooEasy, false, "%.0f%% of candidates answer correctly", a.CorrectRate*100)
+	}
+	if a.CorrectRate < tooHardCorrectRate {
+		add(models.QuestionFlagTooHard, false, "only %.0f%% of candidates answer correctly", a.CorrectRate*100)
+	}
+	if r := a.PointBiserial; r != nil {
+		if *r < 0 {
+			add(models.QuestionFlagNegativeCorrelation, true, "stronger candidates answer it worse (point-biserial %.2f); check the answer key", *r)
+		} else if *r < lowDiscrimination {
+			add(models.QuestionFlagLowDiscrimination, false, "barely separates strong and weak candidates (point-biserial %.2f)", *r)
+		}
+	}
+
+	var key *models.OptionAnalytics
+	for i := range a.Options {
+		if a.Options[i].IsCorrect {
+			key = &a.Options[i]
+			break
+		}
+	}
+	for _, opt := range a.Options {
+		if opt.IsCorrect {
+			continue
+		}
+		if key != nil && opt.PointBiserial != nil && *opt.PointBiserial > 0 &&
+			(key.PointBiserial == nil || *opt.PointBiserial > *key.PointBiserial) {
+			add(models.QuestionFlagDistractorBeatsKey, true, "wrong option %q attracts stronger candidates than the correct one", opt.Text)
+		}
+		if opt.Rate < unusedDistractorRate {
+			add(models.QuestionFlagUnusedDistractor, false, "wrong option %q is chosen by %.0f%% of candidates", opt.Text, opt.Rate*100)
+		}
+	}
+
+	if a.TimeLimit > 0 && a.MedianTime > float64(a.TimeLimit)*slowMedianTimeRatio {
+		add(models.QuestionFlagSlow, false, "median time %.0fs exceeds the %ds limit", a.MedianTime, a.TimeLimit)
+	}
+	return flags
+}
+
+func hasBrokenFlag(a models.QuestionAnalytics) bool {
+	for _, f := range a.Flags {
+		if f.Broken {
+			return true
+		}
+	}
+	return false
+}
+
+// timeStats среднее и медиана
+func timeStats(times []float64) (avg, median float64) {
+	if len(times) == 0 {
+		return 0, 0
+	}
+	sorted := append([]float64(nil), times...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, t := range sorted {
+		sum += t
+	}
+	avg = sum / float64(len(sorted))
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	} else {
+		median = sorted[mid]
+	}
+	return round4(avg), round4(median)
+}
+
+// pointBiserial r_pb = (M1 - M0) / σ · √(p·q); nil, если корреляция не определена
+func pointBiserial(flags []bool, totals []float64) *float64 {
+	n := len(totals)
+	if n < 2 {
+		return nil
+	}
+
+	var sum, sum1 float64
+	n1 := 0
+	for i, t := range totals {
+		sum += t
+		if flags[i] {
+			sum1 += t
+			n1++
+		}
+	}
+	if n1 == 0 || n1 == n {
+		return nil
+	}
+
+	mean := sum / float64(n)
+	var variance float64
+	for _, t := range totals {
+		variance += (t - mean) * (t - mean)
+	}
+	sd := math.Sqrt(variance / float64(n))
+	if sd == 0 {
+		return nil
+	}
+
+	m1 := sum1 / float64(n1)
+	m0 := (sum - sum1) / float64(n-n1)
+	p := float64(n1) / float64(n)
+	r := round4((m1 - m0) / sd * math.Sqrt(p*(1-p)))
+	return &r
+}
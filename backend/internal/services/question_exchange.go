@@ -0,0 +1,409 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// ErrUnsupportedQuestionFormat неизвестный формат импорта/экспорта
+var ErrUnsupportedQuestionFormat = errors.New("unsupported question format (yaml, json, gift, qti)")
+
+// importItem вопрос, разобранный из файла, с ошибками разбора
+type importItem struct {
+	question models.BundleQuestion
+	errs     []string
+}
+
+// QuestionFormatFromFileName формат по расширению файла ("" — не распознан)
+func QuestionFormatFromFileName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		return models.QuestionFormatYAML
+	case ".json":
+		return models.QuestionFormatJSON
+	case ".gift", ".txt":
+		return models.QuestionFormatGIFT
+	case ".zip", ".xml":
+		return models.QuestionFormatQTI
+	}
+	return ""
+}
+
+// ==========================
+// IMPORT
+// ==========================
+
+// ImportQuestions импортирует банк вопросов. Вопросы обрабатываются независимо: ошибки одного
+// попадают в отчёт и не мешают остальным. Ключ — ExternalID: новый вопрос создаётся,
+// изменённый получает новую версию (рубрика переносится), совпадающий не трогается.
+// Созданные и изменённые вопросы начинают с draft и проходят обычную проверку.
+func (s *questionService) ImportQuestions(ctx context.Context, data []byte, opts models.QuestionImportOptions, importedBy string) (*models.QuestionImportReport, error) {
+	items, err := decodeQuestions(opts.Format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.QuestionImportReport{
+		Format: opts.Format,
+		DryRun: opts.DryRun,
+		Total:  len(items),
+		Items:  make([]models.QuestionImportItem, 0, len(items)),
+	}
+	seen := map[string]int{}
+	for i, item := range items {
+		result := s.importQuestion(ctx, item, opts, importedBy, seen, i+1)
+		switch result.Status {
+		case models.ImportStatusCreated:
+			report.Created++
+		case models.ImportStatusUpdated:
+			report.Updated++
+		case models.ImportStatusUnchanged:
+			report.Unchanged++
+		default:
+			report.Failed++
+		}
+		if len(result.Similar) > 0 {
+			report.Warnings++
+		}
+		report.Items = append(report.Items, result)
+	}
+	return report, nil
+}
+
+func (s *questionService) importQuestion(ctx context.Context, item importItem, opts models.QuestionImportOptions, importedBy string, seen map[string]int, index int) models.QuestionImportItem {
+	q := item.question
+	q.ExternalID = strings.TrimSpace(q.ExternalID)
+	result := models.QuestionImportItem{
+		Index:      index,
+		ExternalID: q.ExternalID,
+		Title:      q.Title,
+		Status:     models.ImportStatusFailed,
+		Errors:     item.errs,
+	}
+	if q.ExternalID == "" {
+		result.Errors = append(result.Errors, "external_id is required")
+	} else if first, ok := seen[q.ExternalID]; ok {
+		result.Errors = append(result.Errors, fmt.Sprintf("duplicate external_id (same as item %d)", first))
+	} else {
+		seen[q.ExternalID] = index
+	}
+	if len(result.Errors) > 0 {
+		return result
+	}
+
+	existing, err := s.questionRepo.GetLatestQuestionByExternalID(ctx, q.ExternalID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		result.Errors = append(result.Errors, fmt.Sprintf("lookup failed: %v", err))
+		return result
+	}
+
+	if existing != nil {
+		carryOverFields(opts.Format, &q, existing)
+	}
+	if q.Competency == "" {
+		q.Competency = opts.Competency
+	}
+	if q.Level == "" {
+		q.Level = opts.Level
+	}
+
+	req := bundleToRequest(q)
+	if errs := questionRequestErrors(req); len(errs) > 0 {
+		result.Errors = errs
+		return result
+	}
+	next, _ := buildQuestion(req)
+	next.CreatedBy = importedBy
+
+	switch {
+	case existing == nil:
+		result.Status = models.ImportStatusCreated
+		result.Version = 1
+		if opts.DryRun {
+			result.Similar = s.previewSimilar(ctx, next, "")
+			return result
+		}
+		if err := s.questionRepo.CreateQuestion(ctx, next); err != nil {
+			result.Status = models.ImportStatusFailed
+			result.Errors = []string{fmt.Sprintf("create question failed: %v", err)}
+			return result
+		}
+	case sameBundleContent(opts.Format, existing, next):
+		result.Status = models.ImportStatusUnchanged
+		result.QuestionID = existing.ID
+		result.Version = existing.Version
+		return result
+	default:
+		result.Status = models.ImportStatusUpdated
+		result.Version = existing.Version + 1
+		if opts.DryRun {
+			result.QuestionID = existing.ID
+			result.Similar = s.previewSimilar(ctx, next, rootID(existing))
+			return result
+		}
+		next.Rubric = cloneRubric(existing.Rubric)
+		if err := s.questionRepo.CreateQuestionVersion(ctx, existing.ID, next); err != nil {
+			result.Status = models.ImportStatusFailed
+			result.Errors = []string{fmt.Sprintf("create question version failed: %v", err)}
+			return result
+		}
+	}
+	result.QuestionID = next.ID
+	result.Version = next.Version
+	result.Similar = indexQuestion(ctx, s.questionRepo, next)
+	return result
+}
+
+// previewSimilar похожие вопросы банка для вопроса, который ещё не сохранён (пробный импорт)
+func (s *questionService) previewSimilar(ctx context.Context, q *models.Question, excludeRootID string) []models.SimilarQuestion {
+	sig, _ := questionSignature(q)
+	similar, err := findSimilar(ctx, s.questionRepo, sig, excludeRootID, similarityThreshold, maxSimilarWarnings)
+	if err != nil {
+		log.Printf("⚠️ %v (import preview)", err)
+		return nil
+	}
+	return similar
+}
+
+// carryOverFields поля, которых нет в формате, берутся у существующего вопроса: иначе
+// повторный импорт выгрузки сбросил бы их к значениям по умолчанию
+func carryOverFields(format string, q *models.BundleQuestion, existing *models.Question) {
+	current := questionToBundle(existing)
+	if q.Competency == "" {
+		q.Competency = current.Competency
+	}
+	if q.Level == "" {
+		q.Level = current.Level
+	}
+	switch format {
+	case models.QuestionFormatQTI:
+		q.Tags = current.Tags
+		q.Explanation = current.Explanation
+		fallthrough
+	case models.QuestionFormatGIFT:
+		// "// tags:" пишет только экспорт — в чужих файлах его нет
+		if q.Tags == nil {
+			q.Tags = current.Tags
+		}
+		q.TimeLimit = current.TimeLimit
+		q.Points = current.Points
+		q.TestCases = current.TestCases
+		q.StarterFiles = current.StarterFiles
+		q.ReferenceSolution = current.ReferenceSolution
+		q.SolutionLanguage = current.SolutionLanguage
+	}
+}
+
+// sameBundleContent совпадает ли содержимое вопросов в том виде, в каком его передаёт формат
+// (рубрика, IRT и статус не сравниваются)
+func sameBundleContent(format string, current, next *models.Question) bool {
+	if !current.IsActive {
+		return false
+	}
+	a, b := formatView(format, questionToBundle(current)), formatView(format, questionToBundle(next))
+	return reflect.DeepEqual(a, b)
+}
+
+// formatView поля вопроса, которые переносит формат, нормализованные так же, как при разборе
+func formatView(format string, b models.BundleQuestion) models.BundleQuestion {
+	b.ExternalID = ""
+	var text func(string) string
+	switch format {
+	case models.QuestionFormatGIFT:
+		text = strings.TrimSpace
+		b.TimeLimit, b.Points = 0, 0
+		b.TestCases, b.StarterFiles = nil, nil
+		b.ReferenceSolution, b.SolutionLanguage = "", ""
+		b.Explanation = text(b.Explanation)
+	case models.QuestionFormatQTI:
+		text = normalizeText
+		b = models.BundleQuestion{Title: b.Title, Description: b.Description, Type: b.Type, Options: b.Options}
+	default:
+		return b
+	}
+
+	b.Title = strings.TrimSpace(b.Title)
+	// пустой текст экспортируется заголовком и разбирается обратно в пустой
+	if b.Description = text(b.Description); b.Description == b.Title {
+		b.Description = ""
+	}
+	options := make([]models.BundleOption, len(b.Options))
+	for i, o := range b.Options {
+		options[i] = models.BundleOption{Text: text(o.Text), Correct: o.Correct}
+	}
+	b.Options = options
+	return b
+}
+
+// decodeQuestions разбирает файл в вопросы; ошибка — только если файл не читается целиком
+func decodeQuestions(format string, data []byte) ([]importItem, error) {
+	switch format {
+	case models.QuestionFormatYAML, models.QuestionFormatJSON:
+		var bundle models.QuestionBundle
+		if format == models.QuestionFormatYAML {
+			dec := yaml.NewDecoder(bytes.NewReader(data))
+			dec.KnownFields(true)
+			if err := dec.Decode(&bundle); err != nil {
+				return nil, fmt.Errorf("invalid yaml bundle: %w", err)
+			}
+		} else {
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&bundle); err != nil {
+				return nil, fmt.Errorf("invalid json bundle: %w", err)
+			}
+		}
+		if bundle.Version > models.QuestionBundleVersion {
+			return nil, fmt.Errorf("bundle version %d is newer than supported %d", bundle.Version, models.QuestionBundleVersion)
+		}
+		items := make([]importItem, 0, len(bundle.Questions))
+		for _, q := range bundle.Questions {
+			items = append(items, importItem{question: q})
+		}
+		return items, nil
+	case models.QuestionFormatGIFT:
+		return parseGIFT(string(data)), nil
+	case models.QuestionFormatQTI:
+		return parseQTI(data)
+	}
+	return nil, ErrUnsupportedQuestionFormat
+}
+
+// ==========================
+// EXPORT
+// ==========================
+
+// ExportQuestions выгружает последние версии вопросов по фильтру.
+// GIFT и QTI содержат только multiple choice, остальные вопросы учитываются в Skipped.
+func (s *questionService) ExportQuestions(ctx context.Context, format string, filter repository.QuestionFilter) (*models.QuestionExport, error) {
+	switch format {
+	case models.QuestionFormatYAML, models.QuestionFormatJSON, models.QuestionFormatGIFT, models.QuestionFormatQTI:
+	default:
+		return nil, ErrUnsupportedQuestionFormat
+	}
+
+	filter.LatestOnly = true
+	filter.Preload = true
+	questions, _, err := s.questionRepo.ListQuestions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list questions failed: %w", err)
+	}
+
+	export := &models.QuestionExport{Format: format}
+	var bundle []models.BundleQuestion
+	for i := range questions {
+		q := &questions[i]
+		if (format == models.QuestionFormatGIFT || format == models.QuestionFormatQTI) && q.Type != models.QuestionTypeMultipleChoice {
+			export.Skipped++
+			continue
+		}
+		bundle = append(bundle, questionToBundle(q))
+	}
+	export.Exported = len(bundle)
+
+	switch format {
+	case models.QuestionFormatYAML, models.QuestionFormatJSON:
+		now := time.Now().UTC()
+		doc := models.QuestionBundle{Version: models.QuestionBundleVersion, ExportedAt: &now, Questions: bundle}
+		if doc.Questions == nil {
+			doc.Questions = []models.BundleQuestion{}
+		}
+		if format == models.QuestionFormatYAML {
+			export.ContentType = "application/yaml"
+			export.FileName = "questions.yaml"
+			export.Data, err = yaml.Marshal(doc)
+		} else {
+			export.ContentType = "application/json"
+			export.FileName = "questions.json"
+			export.Data, err = json.MarshalIndent(doc, "", "  ")
+		}
+	case models.QuestionFormatGIFT:
+		export.ContentType = "text/plain; charset=utf-8"
+		export.FileName = "questions.gift"
+		export.Data = []byte(encodeGIFT(bundle))
+	case models.QuestionFormatQTI:
+		export.ContentType = "application/zip"
+		export.FileName = "questions-qti.zip"
+		export.Data, err = encodeQTI(bundle)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s failed: %w", format, err)
+	}
+	return export, nil
+}
+
+// ==========================
+// CONVERSION
+// ==========================
+
+func questionToBundle(q *models.Question) models.BundleQuestion {
+	b := models.BundleQuestion{
+		ExternalID:        q.ExternalID,
+		Title:             q.Title,
+		Description:       q.Description,
+		Type:              q.Type,
+		Level:             q.Difficulty,
+		Competency:        q.Competency,
+		Explanation:       q.Explanation,
+		TimeLimit:         q.TimeLimit,
+		Points:            q.Points,
+		ReferenceSolution: q.ReferenceSolution,
+		SolutionLanguage:  q.SolutionLanguage,
+	}
+	if b.ExternalID == "" {
+		b.ExternalID = rootID(q)
+	}
+	if len(q.StarterFiles) > 0 {
+		b.StarterFiles = q.StarterFiles
+	}
+	for _, t := range q.Tags {
+		b.Tags = append(b.Tags, t.Name)
+	}
+	sort.Strings(b.Tags)
+	for _, o := range q.Options {
+		b.Options = append(b.Options, models.BundleOption{Text: o.Text, Correct: o.IsCorrect})
+	}
+	for _, tc := range q.TestCases {
+		b.TestCases = append(b.TestCases, models.BundleTestCase{Input: tc.Input, Expected: tc.Expected, Hidden: tc.IsHidden})
+	}
+	return b
+}
+
+func bundleToRequest(b models.BundleQuestion) models.QuestionRequest {
+	req := models.QuestionRequest{
+		Title:             strings.TrimSpace(b.Title),
+		Description:       b.Description,
+		Type:              b.Type,
+		Difficulty:        b.Level,
+		Competency:        strings.TrimSpace(b.Competency),
+		Tags:              b.Tags,
+		Explanation:       b.Explanation,
+		TimeLimit:         b.TimeLimit,
+		Points:            b.Points,
+		ExternalID:        b.ExternalID,
+		StarterFiles:      b.StarterFiles,
+		ReferenceSolution: b.ReferenceSolution,
+		SolutionLanguage:  b.SolutionLanguage,
+	}
+	for _, o := range b.Options {
+		req.Options = append(req.Options, models.QuestionOptionInput{Text: o.Text, IsCorrect: o.Correct})
+	}
+	for _, tc := range b.TestCases {
+		req.TestCases = append(req.TestCases, models.TestCaseInput{Input: tc.Input, Expected: tc.Expected, IsHidden: tc.Hidden})
+	}
+	return req
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+// exchangeQuestion вопрос банка с полями, которых нет в GIFT и QTI
+func exchangeQuestion() *models.Question {
+	q := &models.Question{
+		Title:       "Closed channels",
+		Description: "What does a receive from a closed channel return?\n\nAssume ch is `chan int` {buffered: no}.",
+		Type:        models.QuestionTypeMultipleChoice,
+		Difficulty:  models.DifficultyMiddle,
+		Competency:  "concurrency",
+		Tags:        []models.QuestionTag{{Name: "channels"}, {Name: "runtime"}},
+		Options: []models.QuestionOption{
+			{Text: "The zero value and ok=false", IsCorrect: true},
+			{Text: "It blocks forever"},
+			{Text: "A panic: ~send on closed channel~"},
+			{Text: "value = 1 # always"},
+		},
+		Explanation: "Receives from a closed channel never block.",
+		TimeLimit:   120,
+		Points:      3,
+		ExternalID:  "conc-007",
+		IsActive:    true,
+	}
+	q.ID = "q-1"
+	return q
+}
+
+func parseSingle(t *testing.T, format string, data []byte) models.BundleQuestion {
+	t.Helper()
+	items, err := decodeQuestions(format, data)
+	if err != nil {
+		t.Fatalf("decode %s: %v", format, err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("decode %s: %d questions, want 1", format, len(items))
+	}
+	if len(items[0].errs) > 0 {
+		t.Fatalf("decode %s: %v", format, items[0].errs)
+	}
+	return items[0].question
+}
+
+func TestGIFTRoundTrip(t *testing.T) {
+	existing := exchangeQuestion()
+	parsed := parseSingle(t, models.QuestionFormatGIFT, []byte(encodeGIFT([]models.BundleQuestion{questionToBundle(existing)})))
+
+	if parsed.ExternalID != "conc-007" || parsed.Competency != "concurrency" || parsed.Level != models.DifficultyMiddle {
+		t.Errorf("id/competency/level = %q/%q/%q", parsed.ExternalID, parsed.Competency, parsed.Level)
+	}
+	if !reflect.DeepEqual(parsed.Tags, []string{"channels", "runtime"}) {
+		t.Errorf("tags = %v", parsed.Tags)
+	}
+	if parsed.Description != existing.Description {
+		t.Errorf("description = %q, want %q", parsed.Description, existing.Description)
+	}
+
+	carryOverFields(models.QuestionFormatGIFT, &parsed, existing)
+	if parsed.TimeLimit != 120 || parsed.Points != 3 {
+		t.Errorf("time_limit/points = %d/%d, want carried over 120/3", parsed.TimeLimit, parsed.Points)
+	}
+	want := formatView(models.QuestionFormatGIFT, questionToBundle(existing))
+	if got := formatView(models.QuestionFormatGIFT, parsed); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip changed the question:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestGIFTRoundTripMultipleCorrect(t *testing.T) {
+	existing := exchangeQuestion()
+	existing.Options[1].IsCorrect = true
+	existing.Options[3].IsCorrect = true
+	parsed := parseSingle(t, models.QuestionFormatGIFT, []byte(encodeGIFT([]models.BundleQuestion{questionToBundle(existing)})))
+
+	for i, o := range parsed.Options {
+		if o.Correct != existing.Options[i].IsCorrect {
+			t.Errorf("option %d correct = %v, want %v", i, o.Correct, existing.Options[i].IsCorrect)
+		}
+	}
+}
+
+func TestQTIRoundTrip(t *testing.T) {
+	existing := exchangeQuestion()
+	data, err := encodeQTI([]models.BundleQuestion{questionToBundle(existing)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := parseSingle(t, models.QuestionFormatQTI, data)
+
+	if parsed.ExternalID != "conc-007" || parsed.Title != existing.Title {
+		t.Errorf("id/title = %q/%q", parsed.ExternalID, parsed.Title)
+	}
+	// абзацы (пустая строка) переживают <br/><br/>
+	if parsed.Description != existing.Description {
+		t.Errorf("description = %q, want %q", parsed.Description, existing.Description)
+	}
+	if parsed.Competency != "" || parsed.Level != "" || parsed.Tags != nil || parsed.Explanation != "" {
+		t.Errorf("qti carries no competency, level, tags or explanation: %+v", parsed)
+	}
+
+	carryOverFields(models.QuestionFormatQTI, &parsed, existing)
+	current := questionToBundle(existing)
+	current.ExternalID = parsed.ExternalID
+	if !reflect.DeepEqual(parsed, current) {
+		t.Errorf("after carry over:\n got %+v\nwant %+v", parsed, current)
+	}
+}
+
+func TestQTIMultipleCorrectAndEmptyDescription(t *testing.T) {
+	existing := exchangeQuestion()
+	existing.Description = ""
+	existing.Options[0].IsCorrect = true
+	existing.Options[2].IsCorrect = true
+	data, err := encodeQTI([]models.BundleQuestion{questionToBundle(existing)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := parseSingle(t, models.QuestionFormatQTI, data)
+
+	// пустой текст экспортируется заголовком и разбирается обратно в пустой
+	if parsed.Description != "" {
+		t.Errorf("description = %q, want empty", parsed.Description)
+	}
+	for i, o := range parsed.Options {
+		if o.Correct != existing.Options[i].IsCorrect {
+			t.Errorf("option %d correct = %v, want %v", i, o.Correct, existing.Options[i].IsCorrect)
+		}
+	}
+}
+
+func TestSameBundleContent(t *testing.T) {
+	current := exchangeQuestion()
+
+	reformatted := exchangeQuestion()
+	reformatted.Description = "  What does a receive from   a closed channel return?\n\n\n\nAssume ch is `chan int` {buffered: no}.\n"
+	reformatted.Tags = nil
+	reformatted.TimeLimit = 300
+	if !sameBundleContent(models.QuestionFormatQTI, current, reformatted) {
+		t.Errorf("qti: whitespace, tags and limits must not count as a change")
+	}
+	if sameBundleContent(models.QuestionFormatGIFT, current, reformatted) {
+		t.Errorf("gift: tags are part of the format and must count as a change")
+	}
+
+	edited := exchangeQuestion()
+	edited.Options[1].Text = "It returns immediately"
+	for _, format := range []string{models.QuestionFormatGIFT, models.QuestionFormatQTI} {
+		if sameBundleContent(format, current, edited) {
+			t.Errorf("%s: changed option text must count as a change", format)
+		}
+	}
+
+	// снятый с публикации вопрос при повторном импорте всегда получает новую версию
+	retired := exchangeQuestion()
+	retired.IsActive = false
+	if sameBundleContent(models.QuestionFormatGIFT, retired, exchangeQuestion()) {
+		t.Errorf("inactive question must not be reported as unchanged")
+	}
+}
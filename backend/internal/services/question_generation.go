@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+)
+
+const (
+	// maxGeneratedQuestions вопросов за один запрос (генерация синхронная)
+	maxGeneratedQuestions = 20
+	// generationAttempts попыток на вопрос: при невалидном ответе модели ошибки отправляются ей обратно
+	generationAttempts = 2
+)
+
+const generationSystemPrompt = `You write questions for technical hiring assessments of Go backend engineers.
+Questions must be unambiguous, technically correct and match the requested level.
+Answer with a single JSON object and nothing else.`
+
+// levelGuidance ожидания от уровня для промпта
+var levelGuidance = map[models.DifficultyLevel]string{
+	models.DifficultyJunior: "junior (0-2 years): language fundamentals, standard library, simple tasks",
+	models.DifficultyMiddle: "middle (2-5 years): idiomatic Go, concurrency primitives, testing, common trade-offs",
+	models.DifficultySenior: "senior (5+ years): design decisions, performance, failure modes, production concerns",
+	models.DifficultyExpert: "expert: runtime internals, large-scale architecture, subtle edge cases",
+}
+
+// QuestionGenerationService генерация вопросов через AIProvider. Ответ модели проверяется теми же
+// правилами, что и ручной ввод; сохранённые вопросы помечены ai_generated и ждут проверки эксперта (pending).
+type QuestionGenerationService interface {
+	Generate(ctx context.Context, req models.GenerateQuestionsRequest, requestedBy string) (*models.GenerationResult, error)
+}
+
+type questionGenerationService struct {
+	questionRepo repository.QuestionRepository
+	provider     AIProvider
+}
+
+// NewQuestionGenerationService provider может быть nil — тогда генерация возвращает ErrAIUnavailable
+func NewQuestionGenerationService(questionRepo repository.QuestionRepository, provider AIProvider) QuestionGenerationService {
+	return &questionGenerationService{
+		questionRepo: questionRepo,
+		provider:     provider,
+	}
+}
+
+// generationSpec что нужно сгенерировать
+type generationSpec struct {
+	competency string
+	level      models.DifficultyLevel
+	qType      models.QuestionType
+	context    string
+	avoid      []string // заголовки уже созданных в этом запросе вопросов
+}
+
+// generatedQuestion ожидаемая структура ответа модели
+type generatedQuestion struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Options     []struct {
+		Text      string `json:"text"`
+		IsCorrect bool   `json:"is_correct"`
+	} `json:"options"`
+	TestCases []struct {
+		Input    string `json:"input"`
+		Expected string `json:"expected"`
+		IsHidden bool   `json:"is_hidden"`
+	} `json:"test_cases"`
+	StarterCode       string   `json:"starter_code"`
+	ReferenceSolution string   `json:"reference_solution"`
+	SolutionLanguage  string   `json:"solution_language"`
+	Explanation       string   `json:"explanation"`
+	Tags              []string `json:"tags"`
+	TimeLimit         int      `json:"time_limit"`
+	Points            int      `json:"points"`
+}
+
+// Generate создаёт вопросы по каждой компетенции, чередуя типы из запроса.
+// Неудачные вопросы попадают в Failed; недоступность провайдера прерывает генерацию.
+func (s *questionGenerationService) Generate(ctx context.Context, req models.GenerateQuestionsRequest, requestedBy string) (*models.GenerationResult, error) {
+	if s.provider == nil {
+		return nil, ErrAIUnavailable
+	}
+
+	types := req.QuestionTypes
+	if len(types) == 0 {
+		types = []models.QuestionType{models.QuestionTypeMultipleChoice, models.QuestionTypeCoding}
+	}
+	for _, t := range types {
+		switch t {
+		case models.QuestionTypeMultipleChoice, models.QuestionTypeCoding, models.QuestionTypeArchitecture, models.QuestionTypeDebugging:
+		default:
+			return nil, fmt.Errorf("unknown question type %q", t)
+		}
+	}
+	total := 0
+	for i := range req.Competencies {
+		if req.Competencies[i].Count == 0 {
+			req.Competencies[i].Count = 1
+		}
+		total += req.Competencies[i].Count
+	}
+	if total > maxGeneratedQuestions {
+		return nil, fmt.Errorf("at most %d questions can be generated per request", maxGeneratedQuestions)
+	}
+
+	result := &models.GenerationResult{
+		Provider:  s.provider.Name(),
+		Model:     req.Model,
+		Questions: []models.Question{},
+		Failed:    []models.GenerationFailure{},
+	}
+	if result.Model == "" {
+		result.Model = s.provider.Model()
+	}
+
+	for _, target := range req.Competencies {
+		spec := generationSpec{competency: target.Name, level: target.Level, context: req.Context}
+		for i := 0; i < target.Count; i++ {
+			spec.qType = types[i%len(types)]
+
+			question, errs, err := s.generateOne(ctx, spec, req.Model, result)
+			if err != nil {
+				if errors.Is(err, ErrAIUnavailable) && len(result.Questions) == 0 {
+					return nil, err
+				}
+				errs = []string{err.Error()}
+			}
+			if len(errs) == 0 {
+				saved, serr := s.saveDraft(ctx, question, requestedBy)
+				if serr == nil {
+					result.Questions = append(result.Questions, *saved)
+					spec.avoid = append(spec.avoid, saved.Title)
+					continue
+				}
+				errs = []string{serr.Error()}
+			}
+
+			result.Failed = append(result.Failed, models.GenerationFailure{
+				Competency: spec.competency,
+				Level:      spec.level,
+				Type:       spec.qType,
+				Errors:     errs,
+			})
+			if errors.Is(err, ErrAIUnavailable) || ctx.Err() != nil {
+				return result, nil
+			}
+		}
+	}
+	return result, nil
+}
+
+// generateOne запрашивает вопрос у модели; при невалидном ответе повторяет запрос с перечнем ошибок.
+// errs — ошибки валидации последней попытки, err — сбой провайдера.
+func (s *questionGenerationService) generateOne(ctx context.Context, spec generationSpec, model string, result *models.GenerationResult) (*models.Question, []string, error) {
+	prompt := generationPrompt(spec)
+	var errs []string
+	for attempt := 1; attempt <= generationAttempts; attempt++ {
+		p := prompt
+		if len(errs) > 0 {
+			p += "\n\nYour previous answer was rejected: " + strings.Join(errs, "; ") + ".\nReturn the corrected JSON object only."
+		}
+
+		completion, err := s.provider.Complete(ctx, AICompletionRequest{
+			System: generationSystemPrompt,
+			Prompt: p,
+			Model:  model,
+			JSON:   true,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		result.PromptTokens += completion.PromptTokens
+		result.CompletionTokens += completion.CompletionTokens
+
+		var question *models.Question
+		question, errs = parseGeneratedQuestion(completion.Text, spec)
+		if len(errs) == 0 {
+			question.AIModel = s.provider.Name() + "/" + completion.Model
+			return question, nil, nil
+		}
+	}
+	return nil, errs, nil
+}
+
+// saveDraft сохраняет вопрос и сразу отправляет его на проверку эксперту
+func (s *questionGenerationService) saveDraft(ctx context.Context, question *models.Question, requestedBy string) (*models.Question, error) {
+	question.CreatedBy = requestedBy
+	if err := s.questionRepo.CreateQuestion(ctx, question); err != nil {
+		return nil, fmt.Errorf("create question failed: %w", err)
+	}
+
+	entry := &models.QuestionValidation{
+		QuestionID: question.ID,
+		Action:     models.ValidationActionSubmit,
+		FromStatus: models.ValidationStatusDraft,
+		ToStatus:   models.ValidationStatusPending,
+		Comment:    "generated by " + question.AIModel,
+	}
+	if requestedBy != "" {
+		entry.UserID = &requestedBy
+	}
+	if err := s.questionRepo.ChangeValidationStatus(ctx, question, entry); err != nil {
+		return nil, fmt.Errorf("submit generated question failed: %w", err)
+	}
+	saved, err := s.questionRepo.GetQuestionByID(ctx, question.ID)
+	if err != nil {
+		return nil, err
+	}
+	saved.Similar = indexQuestion(ctx, s.questionRepo, saved)
+	return saved, nil
+}
+
+func generationPrompt(spec generationSpec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Write one %s question.\n", spec.qType)
+	fmt.Fprintf(&b, "Competency: %s\n", spec.competency)
+	fmt.Fprintf(&b, "Level: %s\n", levelGuidance[spec.level])
+	if spec.context != "" {
+		fmt.Fprintf(&b, "Additional context: %s\n", spec.context)
+	}
+	if len(spec.avoid) > 0 {
+		fmt.Fprintf(&b, "Do not repeat these questions: %s\n", strings.Join(spec.avoid, "; "))
+	}
+
+	b.WriteString("\nJSON fields:\n")
+	b.WriteString(`- "title": short title (max 200 characters)` + "\n")
+	b.WriteString(`- "description": the full question text shown to the candidate` + "\n")
+	switch spec.qType {
+	case models.QuestionTypeMultipleChoice:
+		b.WriteString(`- "options": 4 answer options, [{"text": "...", "is_correct": true|false}], at least one correct` + "\n")
+	case models.QuestionTypeCoding, models.QuestionTypeDebugging:
+		b.WriteString(`- "test_cases": 3-5 cases, [{"input": "stdin", "expected": "stdout", "is_hidden": true|false}]; the program reads stdin and writes stdout` + "\n")
+		b.WriteString(`- "starter_code": code given to the candidate` + "\n")
+		if spec.qType == models.QuestionTypeDebugging {
+			b.WriteString(`  (for debugging: a program with a bug the candidate must find and fix)` + "\n")
+		}
+		b.WriteString(`- "reference_solution": a complete program (package main) that passes all test cases` + "\n")
+		b.WriteString(`- "solution_language": "go"` + "\n")
+	case models.QuestionTypeArchitecture:
+		b.WriteString(`- the description must state requirements and constraints; the answer is free text graded by an expert` + "\n")
+	}
+	b.WriteString(`- "explanation": the correct answer and why, for reviewers` + "\n")
+	b.WriteString(`- "tags": 1-5 short lowercase tags` + "\n")
+	b.WriteString(`- "time_limit": seconds the candidate should need` + "\n")
+	return b.String()
+}
+
+// parseGeneratedQuestion проверяет ответ модели и собирает вопрос. Тип, компетенция
+// и уровень берутся из запроса, а не из ответа.
+func parseGeneratedQuestion(text string, spec generationSpec) (*models.Question, []string) {
+	var out generatedQuestion
+	if err := json.Unmarshal([]byte(extractJSONObject(text)), &out); err != nil {
+		return nil, []string{fmt.Sprintf("response is not a valid JSON object: %v", err)}
+	}
+
+	req := models.QuestionRequest{
+		Title:             strings.TrimSpace(out.Title),
+		Description:       strings.TrimSpace(out.Description),
+		Type:              spec.qType,
+		Difficulty:        spec.level,
+		Competency:        spec.competency,
+		Tags:              out.Tags,
+		Explanation:       out.Explanation,
+		TimeLimit:         out.TimeLimit,
+		Points:            out.Points,
+		ReferenceSolution: out.ReferenceSolution,
+		SolutionLanguage:  strings.ToLower(out.SolutionLanguage),
+	}
+	if req.TimeLimit < 0 || req.TimeLimit > 3600 {
+		req.TimeLimit = 0
+	}
+	if req.Points < 0 || req.Points > 10 {
+		req.Points = 0
+	}
+
+	var errs []string
+	if req.Description == "" {
+		errs = append(errs, "description is required")
+	}
+	switch spec.qType {
+	case models.QuestionTypeMultipleChoice:
+		for _, o := range out.Options {
+			req.Options = append(req.Options, models.QuestionOptionInput{Text: strings.TrimSpace(o.Text), IsCorrect: o.IsCorrect})
+		}
+		req.ReferenceSolution, req.SolutionLanguage = "", ""
+	case models.QuestionTypeCoding, models.QuestionTypeDebugging:
+		for _, tc := range out.TestCases {
+			req.TestCases = append(req.TestCases, models.TestCaseInput{Input: tc.Input, Expected: tc.Expected, IsHidden: tc.IsHidden})
+		}
+		if len(req.TestCases) == 0 {
+			errs = append(errs, "coding questions need at least one test case")
+		}
+		if strings.TrimSpace(req.ReferenceSolution) == "" {
+			errs = append(errs, "reference_solution is required")
+		}
+		if req.SolutionLanguage == "" {
+			req.SolutionLanguage = "go"
+		}
+		if out.StarterCode != "" {
+			if files, err := solutionFiles(req.SolutionLanguage, out.StarterCode); err == nil {
+				req.StarterFiles = files
+			}
+		}
+	default:
+		req.ReferenceSolution, req.SolutionLanguage = "", ""
+	}
+
+	errs = append(errs, questionRequestErrors(req)...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	question, err := buildQuestion(req)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	question.AIGenerated = true
+	return question, nil
+}
+
+// extractJSONObject JSON-объект из ответа модели (модели иногда оборачивают его в ```json ... ```)
+func extractJSONObject(text string) string {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return text
+	}
+	return text[start : end+1]
+}
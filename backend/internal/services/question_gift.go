@@ -0,0 +1,371 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+// Moodle GIFT: https://docs.moodle.org/en/GIFT_format
+//
+// Поддерживаются multiple choice (в т.ч. несколько верных с весами ~%50%) и true/false,
+// который превращается в выбор из двух вариантов. $CATEGORY задаёт компетенцию,
+// комментарии "// id:", "// level:", "// tags:" — внешний id, уровень и теги (их пишет экспорт).
+
+const giftSpecial = "~=#{}:"
+
+// giftMeta служебные комментарии перед вопросом
+type giftMeta struct {
+	id    string
+	level models.DifficultyLevel
+	tags  []string
+}
+
+func (m *giftMeta) apply(comment string) {
+	key, value, ok := strings.Cut(comment, ":")
+	if !ok {
+		return
+	}
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "id":
+		m.id = value
+	case "level":
+		m.level = models.DifficultyLevel(value)
+	case "tags":
+		m.tags = nil
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				m.tags = append(m.tags, tag)
+			}
+		}
+	}
+}
+
+// parseGIFT вопросы из GIFT-файла; вопросы разделены пустой строкой
+func parseGIFT(src string) []importItem {
+	src = strings.TrimPrefix(src, "\ufeff")
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+
+	var items []importItem
+	var category string
+	var meta giftMeta
+	var lines []string
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(lines, "\n"))
+		lines = nil
+		if text != "" {
+			items = append(items, parseGIFTQuestion(text, category, meta))
+		}
+		meta = giftMeta{}
+	}
+
+	for _, line := range strings.Split(src, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "//"):
+			meta.apply(strings.TrimSpace(trimmed[2:]))
+		case strings.HasPrefix(trimmed, "$CATEGORY:"):
+			flush()
+			category = giftCategory(strings.TrimPrefix(trimmed, "$CATEGORY:"))
+		default:
+			lines = append(lines, line)
+		}
+	}
+	flush()
+	return items
+}
+
+// giftCategory последний сегмент пути категории Moodle ($course$/top/Go/concurrency → concurrency)
+func giftCategory(path string) string {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		path = path[i+1:]
+	}
+	return strings.TrimSpace(path)
+}
+
+func parseGIFTQuestion(text, category string, meta giftMeta) importItem {
+	q := models.BundleQuestion{
+		ExternalID: meta.id,
+		Type:       models.QuestionTypeMultipleChoice,
+		Level:      meta.level,
+		Competency: category,
+		Tags:       meta.tags,
+	}
+	item := importItem{}
+
+	var title string
+	if strings.HasPrefix(text, "::") {
+		if end := indexUnescaped(text[2:], "::"); end >= 0 {
+			title = giftUnescape(strings.TrimSpace(text[2 : 2+end]))
+			text = text[2+end+2:]
+		}
+	}
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "[") {
+		if i := strings.Index(text, "]"); i > 0 {
+			switch strings.ToLower(text[1:i]) {
+			case "html", "moodle", "plain", "markdown":
+				text = text[i+1:]
+			}
+		}
+	}
+
+	open := indexUnescaped(text, "{")
+	if open < 0 {
+		item.errs = append(item.errs, "answer block {...} not found (descriptions and essays are not supported)")
+		q.Title = title
+		item.question = q
+		return item
+	}
+	closing := indexUnescaped(text[open:], "}")
+	if closing < 0 {
+		item.errs = append(item.errs, "unterminated answer block")
+		q.Title = title
+		item.question = q
+		return item
+	}
+	closing += open
+
+	// вопрос с пропущенным словом: ответы в середине текста
+	stem := strings.TrimSpace(text[:open])
+	if after := strings.TrimSpace(text[closing+1:]); after != "" {
+		stem += " _____ " + after
+	}
+	q.Description = giftUnescape(stem)
+
+	options, explanation, err := parseGIFTAnswers(text[open+1 : closing])
+	if err != nil {
+		item.errs = append(item.errs, err.Error())
+	}
+	q.Options = options
+	q.Explanation = explanation
+
+	if title == "" {
+		title = firstLine(q.Description, 100)
+	}
+	q.Title = title
+	// экспорт пишет заголовок вместо пустого текста
+	if q.Description == q.Title {
+		q.Description = ""
+	}
+	if q.ExternalID == "" {
+		sum := sha1.Sum([]byte(q.Title + "\n" + q.Description))
+		q.ExternalID = "gift-" + hex.EncodeToString(sum[:8])
+	}
+	item.question = q
+	return item
+}
+
+// parseGIFTAnswers варианты из блока {...}; общий отзыв (####) становится объяснением
+func parseGIFTAnswers(block string) ([]models.BundleOption, string, error) {
+	block = strings.TrimSpace(block)
+	var explanation string
+	if i := indexUnescaped(block, "####"); i >= 0 {
+		explanation = giftUnescape(strings.TrimSpace(block[i+4:]))
+		block = strings.TrimSpace(block[:i])
+	}
+
+	if strings.HasPrefix(block, "#") {
+		return nil, explanation, fmt.Errorf("numerical questions are not supported")
+	}
+	switch strings.ToUpper(strings.TrimSpace(cutUnescaped(block, "#"))) {
+	case "T", "TRUE":
+		return []models.BundleOption{{Text: "True", Correct: true}, {Text: "False"}}, explanation, nil
+	case "F", "FALSE":
+		return []models.BundleOption{{Text: "True"}, {Text: "False", Correct: true}}, explanation, nil
+	case "":
+		return nil, explanation, fmt.Errorf("essay questions are not supported")
+	}
+
+	var options []models.BundleOption
+	wrong := 0
+	start := -1
+	var marker byte
+	addOption := func(body string) error {
+		body = strings.TrimSpace(cutUnescaped(body, "#"))
+		if indexUnescaped(body, "->") >= 0 {
+			return fmt.Errorf("matching questions are not supported")
+		}
+		weight := 0.0
+		if marker == '=' {
+			weight = 100
+		}
+		if strings.HasPrefix(body, "%") {
+			end := strings.Index(body[1:], "%")
+			if end < 0 {
+				return fmt.Errorf("invalid answer weight in %q", body)
+			}
+			w, err := strconv.ParseFloat(body[1:1+end], 64)
+			if err != nil {
+				return fmt.Errorf("invalid answer weight in %q", body)
+			}
+			weight = w
+			body = strings.TrimSpace(body[end+2:])
+		}
+		if marker == '~' {
+			wrong++
+		}
+		options = append(options, models.BundleOption{Text: giftUnescape(body), Correct: weight > 0})
+		return nil
+	}
+
+	for i := 0; i < len(block); i++ {
+		switch block[i] {
+		case '\\':
+			i++
+		case '=', '~':
+			if start < 0 {
+				if strings.TrimSpace(block[:i]) != "" {
+					return nil, explanation, fmt.Errorf("unexpected text before answers: %q", strings.TrimSpace(block[:i]))
+				}
+			} else if err := addOption(block[start:i]); err != nil {
+				return nil, explanation, err
+			}
+			marker = block[i]
+			start = i + 1
+		}
+	}
+	if start < 0 {
+		return nil, explanation, fmt.Errorf("no answers found")
+	}
+	if err := addOption(block[start:]); err != nil {
+		return nil, explanation, err
+	}
+	if wrong == 0 {
+		return nil, explanation, fmt.Errorf("short answer questions are not supported")
+	}
+	return options, explanation, nil
+}
+
+// encodeGIFT multiple choice вопросы в GIFT, сгруппированные по компетенции
+func encodeGIFT(questions []models.BundleQuestion) string {
+	sorted := append([]models.BundleQuestion(nil), questions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Competency < sorted[j].Competency })
+
+	var b strings.Builder
+	category := ""
+	for i, q := range sorted {
+		if i == 0 || q.Competency != category {
+			category = q.Competency
+			fmt.Fprintf(&b, "$CATEGORY: %s\n\n", category)
+		}
+
+		fmt.Fprintf(&b, "// id: %s\n// level: %s\n", q.ExternalID, q.Level)
+		if len(q.Tags) > 0 {
+			fmt.Fprintf(&b, "// tags: %s\n", strings.Join(q.Tags, ", "))
+		}
+		stem := q.Description
+		if strings.TrimSpace(stem) == "" {
+			stem = q.Title
+		}
+		fmt.Fprintf(&b, "::%s::%s {\n", giftEscape(q.Title), giftEscape(stem))
+
+		correct := 0
+		for _, o := range q.Options {
+			if o.Correct {
+				correct++
+			}
+		}
+		weight := strconv.FormatFloat(100/float64(max(correct, 1)), 'f', 5, 64)
+		weight = strings.TrimRight(strings.TrimRight(weight, "0"), ".")
+		for _, o := range q.Options {
+			switch {
+			case correct == 1 && o.Correct:
+				fmt.Fprintf(&b, "\t=%s\n", giftEscape(o.Text))
+			case correct == 1:
+				fmt.Fprintf(&b, "\t~%s\n", giftEscape(o.Text))
+			case o.Correct:
+				fmt.Fprintf(&b, "\t~%%%s%%%s\n", weight, giftEscape(o.Text))
+			default:
+				fmt.Fprintf(&b, "\t~%%-100%%%s\n", giftEscape(o.Text))
+			}
+		}
+		if q.Explanation != "" {
+			fmt.Fprintf(&b, "\t####%s\n", giftEscape(q.Explanation))
+		}
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
+
+// indexUnescaped позиция sub в s без учёта экранированных "\x" (-1, если нет)
+func indexUnescaped(s, sub string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// cutUnescaped часть s до неэкранированного sep
+func cutUnescaped(s, sep string) string {
+	if i := indexUnescaped(s, sep); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func giftEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+		case strings.ContainsRune(giftSpecial, r):
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func giftUnescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			next := s[i+1]
+			switch {
+			case next == 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			case next == '\\' || strings.IndexByte(giftSpecial, next) >= 0:
+				b.WriteByte(next)
+				i++
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// firstLine первая строка s, не длиннее limit символов
+func firstLine(s string, limit int) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	if utf8.RuneCountInString(line) <= limit {
+		return line
+	}
+	return string([]rune(line)[:limit-1]) + "…"
+}
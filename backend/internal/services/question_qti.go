@@ -0,0 +1,367 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+// IMS QTI 2.1: экспорт — content package (zip с imsmanifest.xml и файлом на вопрос),
+// импорт — такой же пакет или отдельный assessmentItem. Поддерживается choiceInteraction
+// (один или несколько верных). Внешний id хранится в label (identifier ограничен NCName),
+// компетенции и уровня в QTI нет — их задают параметры импорта (у существующего вопроса
+// они, как и объяснение, теги и лимиты, сохраняются).
+
+const (
+	qtiNamespace      = "http://www.imsglobal.org/xsd/imsqti_v2p1"
+	qtiMatchCorrect   = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct"
+	qtiCPNamespace    = "http://www.imsglobal.org/xsd/imscp_v1p1"
+	qtiItemType       = "imsqti_item_xmlv2p1"
+	qtiResponseIdent  = "RESPONSE"
+	qtiManifestName   = "imsmanifest.xml"
+	qtiItemsDirectory = "items"
+)
+
+var qtiIdentifierInvalid = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+type qtiItem struct {
+	XMLName    xml.Name                 `xml:"assessmentItem"`
+	Identifier string                   `xml:"identifier,attr"`
+	Label      string                   `xml:"label,attr,omitempty"`
+	Title      string                   `xml:"title,attr"`
+	Responses  []qtiResponseDeclaration `xml:"responseDeclaration"`
+	Body       qtiInner                 `xml:"itemBody"`
+}
+
+type qtiResponseDeclaration struct {
+	Identifier  string   `xml:"identifier,attr"`
+	Cardinality string   `xml:"cardinality,attr"`
+	Correct     []string `xml:"correctResponse>value"`
+}
+
+type qtiChoiceInteraction struct {
+	ResponseIdentifier string            `xml:"responseIdentifier,attr"`
+	Prompt             qtiInner          `xml:"prompt"`
+	Choices            []qtiSimpleChoice `xml:"simpleChoice"`
+}
+
+type qtiSimpleChoice struct {
+	Identifier string `xml:"identifier,attr"`
+	Inner      string `xml:",innerxml"`
+}
+
+type qtiInner struct {
+	Inner string `xml:",innerxml"`
+}
+
+// ==========================
+// IMPORT
+// ==========================
+
+// parseQTI вопросы из zip-пакета или одного XML-файла assessmentItem
+func parseQTI(data []byte) ([]importItem, error) {
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		item, ok := parseQTIItem(data)
+		if !ok {
+			return nil, fmt.Errorf("invalid qti: assessmentItem not found")
+		}
+		return []importItem{item}, nil
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid qti package: %w", err)
+	}
+	var items []importItem
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".xml") || path.Base(f.Name) == qtiManifestName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("read %s failed: %w", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s failed: %w", f.Name, err)
+		}
+		// в пакете бывают не только вопросы (тесты, стили) — их пропускаем
+		if item, ok := parseQTIItem(content); ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// parseQTIItem ok=false, если документ — не assessmentItem
+func parseQTIItem(data []byte) (importItem, bool) {
+	var doc qtiItem
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		if strings.Contains(string(data), "assessmentItem") {
+			return importItem{errs: []string{fmt.Sprintf("invalid assessmentItem: %v", err)}}, true
+		}
+		return importItem{}, false
+	}
+
+	q := models.BundleQuestion{
+		ExternalID: strings.TrimSpace(doc.Label),
+		Title:      strings.TrimSpace(doc.Title),
+		Type:       models.QuestionTypeMultipleChoice,
+	}
+	if q.ExternalID == "" {
+		q.ExternalID = doc.Identifier
+	}
+	item := importItem{}
+
+	bodyText, interactions, err := qtiBody(doc.Body.Inner)
+	if err != nil {
+		item.errs = append(item.errs, fmt.Sprintf("invalid itemBody: %v", err))
+	}
+	if len(interactions) != 1 {
+		item.errs = append(item.errs, fmt.Sprintf("expected exactly one choiceInteraction, found %d", len(interactions)))
+		if q.Title == "" {
+			q.Title = firstLine(bodyText, 100)
+		}
+		item.question = q
+		return item, true
+	}
+	interaction := interactions[0]
+
+	correct := map[string]bool{}
+	for _, r := range doc.Responses {
+		if r.Identifier == interaction.ResponseIdentifier {
+			for _, v := range r.Correct {
+				correct[strings.TrimSpace(v)] = true
+			}
+		}
+	}
+	for _, choice := range interaction.Choices {
+		text, err := xmlText(choice.Inner)
+		if err != nil {
+			item.errs = append(item.errs, fmt.Sprintf("invalid simpleChoice %s: %v", choice.Identifier, err))
+		}
+		q.Options = append(q.Options, models.BundleOption{Text: text, Correct: correct[choice.Identifier]})
+	}
+
+	prompt, _ := xmlText(interaction.Prompt.Inner)
+	q.Description = strings.TrimSpace(strings.Join([]string{bodyText, prompt}, "\n\n"))
+	if q.Title == "" {
+		q.Title = firstLine(q.Description, 100)
+	}
+	// экспорт пишет заголовок вместо пустого текста
+	if q.Description == q.Title {
+		q.Description = ""
+	}
+	item.question = q
+	return item, true
+}
+
+// qtiBody текст itemBody вне взаимодействий и сами choiceInteraction (на любой глубине)
+func qtiBody(inner string) (string, []qtiChoiceInteraction, error) {
+	var interactions []qtiChoiceInteraction
+	var b strings.Builder
+	dec := xml.NewDecoder(strings.NewReader(inner))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return normalizeText(b.String()), interactions, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "choiceInteraction" {
+				var ci qtiChoiceInteraction
+				if err := dec.DecodeElement(&ci, &t); err != nil {
+					return normalizeText(b.String()), interactions, err
+				}
+				interactions = append(interactions, ci)
+				continue
+			}
+			if isBlockElement(t.Name.Local) {
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			// <br/> — один перенос: экспорт пишет так переносы строк текста
+			if t.Name.Local != "br" && isBlockElement(t.Name.Local) {
+				b.WriteByte('\n')
+			}
+		case xml.CharData:
+			// отступы разметки между элементами — не текст
+			if strings.TrimSpace(string(t)) == "" && bytes.ContainsRune(t, '\n') {
+				continue
+			}
+			b.Write(t)
+		}
+	}
+	return normalizeText(b.String()), interactions, nil
+}
+
+// xmlText текст XHTML-фрагмента без разметки
+func xmlText(inner string) (string, error) {
+	text, _, err := qtiBody(inner)
+	return text, err
+}
+
+func isBlockElement(name string) bool {
+	switch name {
+	case "p", "div", "br", "li", "pre", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "table", "tr":
+		return true
+	}
+	return false
+}
+
+// normalizeText схлопывает пробелы в строках; подряд идущие пустые строки сводятся к одной
+// (абзацы сохраняются), пустые строки по краям убираются
+func normalizeText(s string) string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ==========================
+// EXPORT
+// ==========================
+
+// encodeQTI content package с вопросами multiple choice
+func encodeQTI(questions []models.BundleQuestion) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	var resources strings.Builder
+	used := map[string]bool{}
+	for _, q := range questions {
+		identifier := qtiIdentifier(q.ExternalID)
+		for base, n := identifier, 2; used[identifier]; n++ {
+			identifier = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[identifier] = true
+
+		href := path.Join(qtiItemsDirectory, identifier+".xml")
+		w, err := archive.Create(href)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(qtiItemXML(identifier, q)); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&resources, "    <resource identifier=\"%s\" type=\"%s\" href=\"%s\">\n      <file href=\"%s\"/>\n    </resource>\n",
+			identifier, qtiItemType, href, href)
+	}
+
+	w, err := archive.Create(qtiManifestName)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<manifest xmlns="%s" identifier="MANIFEST-easyhire">
+  <metadata>
+    <schema>QTIv2.1 Package</schema>
+    <schemaversion>1.0.0</schemaversion>
+  </metadata>
+  <organizations/>
+  <resources>
+%s  </resources>
+</manifest>
+`, qtiCPNamespace, resources.String())
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func qtiItemXML(identifier string, q models.BundleQuestion) []byte {
+	var correct []string
+	var choices strings.Builder
+	for i, o := range q.Options {
+		id := fmt.Sprintf("choice%d", i+1)
+		if o.Correct {
+			correct = append(correct, id)
+		}
+		fmt.Fprintf(&choices, "      <simpleChoice identifier=\"%s\">%s</simpleChoice>\n", id, xmlEscape(o.Text))
+	}
+
+	cardinality, maxChoices := "single", 1
+	if len(correct) > 1 {
+		cardinality, maxChoices = "multiple", 0
+	}
+	var values strings.Builder
+	for _, id := range correct {
+		fmt.Fprintf(&values, "      <value>%s</value>\n", id)
+	}
+
+	prompt := q.Description
+	if strings.TrimSpace(prompt) == "" {
+		prompt = q.Title
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
+<assessmentItem xmlns="%s" identifier="%s" label="%s" title="%s" adaptive="false" timeDependent="false">
+  <responseDeclaration identifier="%s" cardinality="%s" baseType="identifier">
+    <correctResponse>
+%s    </correctResponse>
+  </responseDeclaration>
+  <outcomeDeclaration identifier="SCORE" cardinality="single" baseType="float">
+    <defaultValue>
+      <value>0</value>
+    </defaultValue>
+  </outcomeDeclaration>
+  <itemBody>
+    <choiceInteraction responseIdentifier="%s" shuffle="false" maxChoices="%d">
+      <prompt>%s</prompt>
+%s    </choiceInteraction>
+  </itemBody>
+  <responseProcessing template="%s"/>
+</assessmentItem>
+`, qtiNamespace, identifier, xmlEscape(q.ExternalID), xmlEscape(q.Title),
+		qtiResponseIdent, cardinality, values.String(),
+		qtiResponseIdent, maxChoices, qtiPrompt(prompt), choices.String(),
+		qtiMatchCorrect)
+	return b.Bytes()
+}
+
+// qtiIdentifier допустимый identifier QTI из внешнего id
+func qtiIdentifier(externalID string) string {
+	id := qtiIdentifierInvalid.ReplaceAllString(externalID, "_")
+	if id == "" || !(id[0] == '_' || (id[0] >= 'A' && id[0] <= 'Z') || (id[0] >= 'a' && id[0] <= 'z')) {
+		id = "Q" + id
+	}
+	return id
+}
+
+// qtiPrompt текст с переносами строк как <br/>
+func qtiPrompt(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = xmlEscape(line)
+	}
+	return strings.Join(lines, "<br/>")
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+)
+
+// ErrQuestionNotLatest правка не от последней версии вопроса
+var ErrQuestionNotLatest = errors.New("question has a newer version; edit the latest one")
+
+type QuestionService interface {
+	// Questions (every edit creates a new immutable version)
+	CreateQuestion(ctx context.Context, req models.QuestionRequest, createdBy string) (*models.Question, error)
+	GetQuestion(ctx context.Context, id string) (*models.Question, error)
+	ListQuestions(ctx context.Context, filter repository.QuestionFilter) ([]models.Question, int64, error)
+	UpdateQuestion(ctx context.Context, id string, req models.QuestionRequest, editedBy string) (*models.Question, error)
+	RetireQuestion(ctx context.Context, id string) error
+	ListVersions(ctx context.Context, id string) ([]models.Question, error)
+
+	// Validation workflow
+	SubmitForReview(ctx context.Context, id, userID string, req models.SubmitQuestionRequest) (*models.Question, error)
+	ValidateQuestion(ctx context.Context, id, reviewerID string, isAdmin bool, req models.ValidateQuestionRequest) (*models.Question, error)
+	CheckSolution(ctx context.Context, id string) (*models.SolutionCheck, error)
+	ListValidations(ctx context.Context, id string) ([]models.QuestionValidation, error)
+
+	// Import / export (YAML/JSON bundles, GIFT, QTI)
+	ImportQuestions(ctx context.Context, data []byte, opts models.QuestionImportOptions, importedBy string) (*models.QuestionImportReport, error)
+	ExportQuestions(ctx context.Context, format string, filter repository.QuestionFilter) (*models.QuestionExport, error)
+
+	// Tags (metadata: changing them does not create a new version)
+	ListTags(ctx context.Context) ([]models.QuestionTag, error)
+	CreateTag(ctx context.Context, req models.QuestionTagRequest) (*models.QuestionTag, error)
+	UpdateTag(ctx context.Context, id string, req models.QuestionTagRequest) (*models.QuestionTag, error)
+	DeleteTag(ctx context.Context, id string) error
+	SetQuestionTags(ctx context.Context, id string, tags []string) (*models.Question, error)
+
+	// Similarity (near-duplicate detection)
+	FindSimilarQuestions(ctx context.Context, id string, threshold float64) ([]models.SimilarQuestion, error)
+	DuplicateReport(ctx context.Context, threshold float64) (*models.DuplicateReport, error)
+	ReindexSimilarity(ctx context.Context) (int, error)
+
+	// Rubric
+	GetRubric(ctx context.Context, questionID string) ([]models.RubricCriterion, error)
+	SetRubric(ctx context.Context, questionID string, req models.RubricRequest, editedBy string) (*models.Question, error)
+
+	// Analytics
+	GetQuestionAnalytics(ctx context.Context, questionID string) (*models.QuestionAnalytics, error)
+	ListQuestionAnalytics(ctx context.Context, filter repository.QuestionFilter, flaggedOnly bool) ([]models.QuestionAnalytics, error)
+}
+
+// defaultQuestionTimeLimit лимит нового вопроса, если он не задан (как default колонки)
+const defaultQuestionTimeLimit = 300
+
+type questionService struct {
+	questionRepo   repository.QuestionRepository
+	scoringService ScoringService
+	executor       ExecutorClient
+}
+
+func NewQuestionService(questionRepo repository.QuestionRepository, scoringService ScoringService, executor ExecutorClient) QuestionService {
+	return &questionService{
+		questionRepo:   questionRepo,
+		scoringService: scoringService,
+		executor:       executor,
+	}
+}
+
+// ==========================
+// QUESTIONS
+// ==========================
+
+func (s *questionService) CreateQuestion(ctx context.Context, req models.QuestionRequest, createdBy string) (*models.Question, error) {
+	question, err := buildQuestion(req)
+	if err != nil {
+		return nil, err
+	}
+	question.CreatedBy = createdBy
+
+	if err := s.questionRepo.CreateQuestion(ctx, question); err != nil {
+		return nil, fmt.Errorf("create question failed: %w", err)
+	}
+	created, err := s.questionRepo.GetQuestionByID(ctx, question.ID)
+	if err != nil {
+		return nil, err
+	}
+	created.Similar = indexQuestion(ctx, s.questionRepo, created)
+	return created, nil
+}
+
+// GetQuestion любая версия по её id
+func (s *questionService) GetQuestion(ctx context.Context, id string) (*models.Question, error) {
+	question, err := s.questionRepo.GetQuestionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+	return question, nil
+}
+
+func (s *questionService) ListQuestions(ctx context.Context, filter repository.QuestionFilter) ([]models.Question, int64, error) {
+	questions, total, err := s.questionRepo.ListQuestions(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list questions failed: %w", err)
+	}
+	return questions, total, nil
+}
+
+// UpdateQuestion создаёт новую версию с содержимым из запроса; рубрика переносится из текущей.
+// Старая версия не меняется — ответы кандидатов, которые её видели, оцениваются по ней.
+// Новая версия начинает с draft и снова проходит проверку.
+func (s *questionService) UpdateQuestion(ctx context.Context, id string, req models.QuestionRequest, editedBy string) (*models.Question, error) {
+	current, err := s.latest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := buildQuestion(req)
+	if err != nil {
+		return nil, err
+	}
+	next.Rubric = cloneRubric(current.Rubric)
+	next.CreatedBy = editedBy
+
+	return s.saveVersion(ctx, current.ID, next)
+}
+
+// RetireQuestion снимает вопрос (все версии) с выдачи в новых сессиях
+func (s *questionService) RetireQuestion(ctx context.Context, id string) error {
+	question, err := s.questionRepo.GetQuestionByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("question not found: %w", err)
+	}
+	if err := s.questionRepo.RetireQuestion(ctx, rootID(question)); err != nil {
+		return fmt.Errorf("retire question failed: %w", err)
+	}
+	return nil
+}
+
+// ListVersions все версии вопроса, от новой к старой
+func (s *questionService) ListVersions(ctx context.Context, id string) ([]models.Question, error) {
+	question, err := s.questionRepo.GetQuestionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+	versions, err := s.questionRepo.GetQuestionVersions(ctx, rootID(question))
+	if err != nil {
+		return nil, fmt.Errorf("load versions failed: %w", err)
+	}
+	return versions, nil
+}
+
+// latest версия id, если она последняя
+func (s *questionService) latest(ctx context.Context, id string) (*models.Question, error) {
+	question, err := s.questionRepo.GetQuestionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+	if !question.IsLatest {
+		return nil, ErrQuestionNotLatest
+	}
+	return question, nil
+}
+
+func (s *questionService) saveVersion(ctx context.Context, previousID string, next *models.Question) (*models.Question, error) {
+	if err := s.questionRepo.CreateQuestionVersion(ctx, previousID, next); err != nil {
+		if errors.Is(err, repository.ErrQuestionVersionConflict) {
+			return nil, ErrQuestionNotLatest
+		}
+		return nil, fmt.Errorf("create question version failed: %w", err)
+	}
+	saved, err := s.questionRepo.GetQuestionByID(ctx, next.ID)
+	if err != nil {
+		return nil, err
+	}
+	saved.Similar = indexQuestion(ctx, s.questionRepo, saved)
+	return saved, nil
+}
+
+// buildQuestion проверяет запрос и собирает вопрос со связями
+func buildQuestion(req models.QuestionRequest) (*models.Question, error) {
+	if errs := questionRequestErrors(req); len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "; "))
+	}
+
+	question := &models.Question{
+		Title:        req.Title,
+		Description:  req.Description,
+		Type:         req.Type,
+		Difficulty:   req.Difficulty,
+		Competency:   req.Competency,
+		Explanation:  req.Explanation,
+		TimeLimit:    req.TimeLimit,
+		Points:       req.Points,
+		IsActive:     true,
+		ExternalID:   strings.TrimSpace(req.ExternalID),
+		StarterFiles: req.StarterFiles,
+
+		ValidationStatus:  models.ValidationStatusDraft,
+		ReferenceSolution: req.ReferenceSolution,
+		SolutionLanguage:  req.SolutionLanguage,
+	}
+	if question.ReferenceSolution != "" && question.SolutionLanguage == "" {
+		question.SolutionLanguage = "go"
+	}
+	if question.TimeLimit == 0 {
+		question.TimeLimit = defaultQuestionTimeLimit
+	}
+	if question.Points == 0 {
+		question.Points = 1
+	}
+	if req.IsActive != nil {
+		question.IsActive = *req.IsActive
+	}
+
+	for _, tag := range normalizeTags(req.Tags) {
+		question.Tags = append(question.Tags, models.QuestionTag{Name: tag})
+	}
+	for i, o := range req.Options {
+		question.Options = append(question.Options, models.QuestionOption{Text: o.Text, IsCorrect: o.IsCorrect, Order: i + 1})
+	}
+	for i, tc := range req.TestCases {
+		question.TestCases = append(question.TestCases, models.TestCase{Input: tc.Input, Expected: tc.Expected, IsHidden: tc.IsHidden, Order: i + 1})
+	}
+	return question, nil
+}
+
+// questionRequestErrors все ошибки содержимого вопроса (импорт показывает их списком по каждому вопросу)
+func questionRequestErrors(req models.QuestionRequest) []string {
+	var errs []string
+	switch req.Type {
+	case models.QuestionTypeMultipleChoice, models.QuestionTypeCoding, models.QuestionTypeArchitecture, models.QuestionTypeDebugging:
+	default:
+		errs = append(errs, fmt.Sprintf("unknown question type %q", req.Type))
+	}
+	switch req.Difficulty {
+	case models.DifficultyJunior, models.DifficultyMiddle, models.DifficultySenior, models.DifficultyExpert:
+	default:
+		errs = append(errs, fmt.Sprintf("unknown difficulty %q", req.Difficulty))
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		errs = append(errs, "title is required")
+	} else if len(req.Title) > 500 {
+		errs = append(errs, "title is longer than 500 characters")
+	}
+	if strings.TrimSpace(req.Competency) == "" {
+		errs = append(errs, "competency is required")
+	} else if len(req.Competency) > 100 {
+		errs = append(errs, "competency is longer than 100 characters")
+	}
+	if len(req.ExternalID) > 200 {
+		errs = append(errs, "external_id is longer than 200 characters")
+	}
+	if req.TimeLimit < 0 || req.Points < 0 {
+		errs = append(errs, "time_limit and points must not be negative")
+	}
+	for _, tag := range req.Tags {
+		if len(strings.TrimSpace(tag)) > 100 {
+			errs = append(errs, fmt.Sprintf("tag %q is longer than 100 characters", tag))
+		}
+	}
+
+	if req.Type == models.QuestionTypeMultipleChoice {
+		correct := 0
+		for _, o := range req.Options {
+			if o.IsCorrect {
+				correct++
+			}
+			if strings.TrimSpace(o.Text) == "" {
+				errs = append(errs, "option text is required")
+				break
+			}
+		}
+		if len(req.Options) < 2 || correct == 0 {
+			errs = append(errs, "multiple_choice question needs at least 2 options and a correct one")
+		}
+	}
+
+	switch req.SolutionLanguage {
+	case "", "go", "python", "javascript":
+	default:
+		errs = append(errs, fmt.Sprintf("unsupported solution language %q", req.SolutionLanguage))
+	}
+	for name := range req.StarterFiles {
+		if name == "" || path.IsAbs(name) || strings.Contains(name, "..") || strings.Contains(name, "\\") {
+			errs = append(errs, fmt.Sprintf("invalid starter file name %q", name))
+		}
+	}
+	return errs
+}
+
+// cloneQuestion копия содержимого версии для следующей (без id и параметров IRT —
+// они относятся к конкретной версии)
+func cloneQuestion(q *models.Question) *models.Question {
+	next := &models.Question{
+		Title:       q.Title,
+		Description: q.Description,
+		Type:        q.Type,
+		Difficulty:  q.Difficulty,
+		Competency:  q.Competency,
+		Explanation: q.Explanation,
+		TimeLimit:   q.TimeLimit,
+		Points:      q.Points,
+		IsActive:    q.IsActive,
+		Rubric:      cloneRubric(q.Rubric),
+
+		ExternalID:   q.ExternalID,
+		StarterFiles: q.StarterFiles,
+		AIGenerated:  q.AIGenerated,
+		AIModel:      q.AIModel,
+
+		ValidationStatus:  models.ValidationStatusDraft,
+		ReferenceSolution: q.ReferenceSolution,
+		SolutionLanguage:  q.SolutionLanguage,
+	}
+	for _, t := range q.Tags {
+		next.Tags = append(next.Tags, models.QuestionTag{ID: t.ID, Name: t.Name})
+	}
+	for _, o := range q.Options {
+		next.Options = append(next.Options, models.QuestionOption{Text: o.Text, IsCorrect: o.IsCorrect, Order: o.Order})
+	}
+	for _, tc := range q.TestCases {
+		next.TestCases = append(next.TestCases, models.TestCase{Input: tc.Input, Expected: tc.Expected, IsHidden: tc.IsHidden, Order: tc.Order})
+	}
+	return next
+}
+
+func cloneRubric(criteria []models.RubricCriterion) []models.RubricCriterion {
+	var cloned []models.RubricCriterion
+	for _, c := range criteria {
+		cloned = append(cloned, models.RubricCriterion{
+			Name:        c.Name,
+			Description: c.Description,
+			MaxPoints:   c.MaxPoints,
+			Order:       c.Order,
+			Anchors:     c.Anchors,
+		})
+	}
+	return cloned
+}
+
+// rootID id первой версии (для вопросов до версионирования — свой)
+func rootID(q *models.Question) string {
+	if q.RootID != "" {
+		return q.RootID
+	}
+	return q.ID
+}
+
+// ==========================
+// RUBRIC
+// ==========================
+
+func (s *questionService) GetRubric(ctx context.Context, questionID string) ([]models.RubricCriterion, error) {
+	if _, err := s.questionRepo.GetQuestionByID(ctx, questionID); err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+	return s.questionRepo.GetRubric(ctx, questionID)
+}
+
+// SetRubric новая рубрика — это новая версия вопроса: ответы, оценённые по старой, её сохраняют
+func (s *questionService) SetRubric(ctx context.Context, questionID string, req models.RubricRequest, editedBy string) (*models.Question, error) {
+	current, err := s.latest(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	criteria := make([]models.RubricCriterion, 0, len(req.Criteria))
+	for i, c := range req.Criteria {
+		for _, a := range c.Anchors {
+			if a.Points < 0 || a.Points > c.MaxPoints {
+				return nil, fmt.Errorf("criterion %q: anchor %q points must be between 0 and %.2f", c.Name, a.Label, c.MaxPoints)
+			}
+		}
+		criteria = append(criteria, models.RubricCriterion{
+			Name:        c.Name,
+			Description: c.Description,
+			MaxPoints:   c.MaxPoints,
+			Order:       i + 1,
+			Anchors:     c.Anchors,
+		})
+	}
+
+	next := cloneQuestion(current)
+	next.Rubric = criteria
+	next.CreatedBy = editedBy
+
+	return s.saveVersion(ctx, current.ID, next)
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+)
+
+// Поиск похожих вопросов: текст вопроса (заголовок, условие, варианты, стартовые файлы) режется
+// на шинглы по shingleSize слов, по ним считается MinHash-сигнатура. Доля совпавших значений
+// сигнатур — оценка коэффициента Жаккара. Кандидаты ищутся через LSH: сигнатура разбита на
+// lshBands полос, вопросы с совпавшей полосой попадают в одну корзину.
+const (
+	shingleSize  = 3
+	minhashSize  = 128
+	lshBands     = 32 // по 4 значения: кандидатами становятся пары с похожестью от ~0.4
+	reindexBatch = 500
+
+	// similarityThreshold похожесть, начиная с которой при создании и импорте выдаётся предупреждение
+	similarityThreshold = 0.8
+	// maxSimilarWarnings похожих вопросов в предупреждении
+	maxSimilarWarnings = 5
+)
+
+// minhashSeeds соли хеш-функций MinHash (фиксированные — сигнатуры в базе должны оставаться сравнимыми)
+var minhashSeeds = func() []uint64 {
+	seeds := make([]uint64, minhashSize)
+	state := uint64(0x5eed0f0e)
+	for i := range seeds {
+		state += 0x9e3779b97f4a7c15
+		seeds[i] = mix64(state)
+	}
+	return seeds
+}()
+
+// mix64 финализатор splitmix64
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// questionText текст вопроса, по которому ищутся дубликаты
+func questionText(q *models.Question) string {
+	parts := []string{q.Title, q.Description}
+	for _, o := range q.Options {
+		parts = append(parts, o.Text)
+	}
+	names := make([]string, 0, len(q.StarterFiles))
+	for name := range q.StarterFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, q.StarterFiles[name])
+	}
+	return strings.Join(parts, "\n")
+}
+
+// shingles хеши шинглов текста: регистр, пунктуация и пробелы не учитываются
+func shingles(text string) map[uint64]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	out := map[uint64]struct{}{}
+	if len(words) == 0 {
+		return out
+	}
+	size := shingleSize
+	if len(words) < size {
+		size = len(words)
+	}
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		out[h.Sum64()] = struct{}{}
+	}
+	return out
+}
+
+// minhash сигнатура множества шинглов (nil для пустого)
+func minhash(set map[uint64]struct{}) []uint32 {
+	if len(set) == 0 {
+		return nil
+	}
+	sig := make([]uint32, minhashSize)
+	for i := range sig {
+		sig[i] = ^uint32(0)
+	}
+	for h := range set {
+		for i, seed := range minhashSeeds {
+			if v := uint32(mix64(h^seed) >> 32); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// lshBuckets корзины сигнатуры: хеш номера полосы и её значений
+func lshBuckets(sig []uint32) []int64 {
+	if len(sig) != minhashSize {
+		return nil
+	}
+	rows := minhashSize / lshBands
+	buckets := make([]int64, 0, lshBands)
+	buf := make([]byte, 4)
+	for band := 0; band < lshBands; band++ {
+		h := fnv.New64a()
+		binary.LittleEndian.PutUint32(buf, uint32(band))
+		h.Write(buf)
+		for _, v := range sig[band*rows : (band+1)*rows] {
+			binary.LittleEndian.PutUint32(buf, v)
+			h.Write(buf)
+		}
+		buckets = append(buckets, int64(h.Sum64()))
+	}
+	return buckets
+}
+
+// signatureSimilarity доля совпавших значений сигнатур
+func signatureSimilarity(a, b []uint32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+// questionSignature сигнатура вопроса и число шинглов
+func questionSignature(q *models.Question) ([]uint32, int) {
+	set := shingles(questionText(q))
+	return minhash(set), len(set)
+}
+
+// findSimilar вопросы индекса с похожестью от threshold, самые похожие первыми
+func findSimilar(ctx context.Context, repo repository.QuestionRepository, sig []uint32, excludeRootID string, threshold float64, limit int) ([]models.SimilarQuestion, error) {
+	candidates, err := repo.FindSimilarityCandidates(ctx, lshBuckets(sig), excludeRootID)
+	if err != nil {
+		return nil, fmt.Errorf("find similar questions failed: %w", err)
+	}
+
+	similar := []models.SimilarQuestion{}
+	for _, c := range candidates {
+		if sim := signatureSimilarity(sig, c.Signature); sim >= threshold {
+			similar = append(similar, similarQuestion(c, sim))
+		}
+	}
+	sort.SliceStable(similar, func(i, j int) bool { return similar[i].Similarity > similar[j].Similarity })
+	if limit > 0 && len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
+
+func similarQuestion(c repository.SimilarityCandidate, similarity float64) models.SimilarQuestion {
+	return models.SimilarQuestion{
+		QuestionID:       c.QuestionID,
+		RootID:           c.RootID,
+		Title:            c.Title,
+		Type:             c.Type,
+		Competency:       c.Competency,
+		ValidationStatus: c.ValidationStatus,
+		AIGenerated:      c.AIGenerated,
+		Similarity:       similarity,
+	}
+}
+
+// indexQuestion сохраняет сигнатуру новой (последней) версии вопроса и возвращает похожие вопросы банка.
+// Ошибка индекса не мешает сохранению вопроса — её только логируют.
+func indexQuestion(ctx context.Context, repo repository.QuestionRepository, q *models.Question) []models.SimilarQuestion {
+	sig, n := questionSignature(q)
+	root := rootID(q)
+
+	similar, err := findSimilar(ctx, repo, sig, root, similarityThreshold, maxSimilarWarnings)
+	if err != nil {
+		log.Printf("⚠️ %v (question %s)", err, q.ID)
+		similar = nil
+	}
+	signature := &models.QuestionSignature{RootID: root, QuestionID: q.ID, Signature: sig, Shingles: n}
+	if err := repo.SaveQuestionSignature(ctx, signature, lshBuckets(sig)); err != nil {
+		log.Printf("⚠️ save question signature failed: %v (question %s)", err, q.ID)
+	}
+	return similar
+}
+
+// ==========================
+// SIMILARITY
+// ==========================
+
+// FindSimilarQuestions вопросы банка, похожие на вопрос id (threshold <= 0 — порог предупреждений)
+func (s *questionService) FindSimilarQuestions(ctx context.Context, id string, threshold float64) ([]models.SimilarQuestion, error) {
+	question, err := s.questionRepo.GetQuestionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+	if threshold <= 0 {
+		threshold = similarityThreshold
+	}
+	sig, _ := questionSignature(question)
+	return findSimilar(ctx, s.questionRepo, sig, rootID(question), threshold, 0)
+}
+
+// ReindexSimilarity строит сигнатуры вопросов, которых ещё нет в индексе; возвращает число проиндексированных
+func (s *questionService) ReindexSimilarity(ctx context.Context) (int, error) {
+	indexed := 0
+	for {
+		questions, err := s.questionRepo.ListUnindexedQuestions(ctx, reindexBatch)
+		if err != nil {
+			return indexed, fmt.Errorf("load questions failed: %w", err)
+		}
+		if len(questions) == 0 {
+			return indexed, nil
+		}
+		for i := range questions {
+			sig, n := questionSignature(&questions[i])
+			signature := &models.QuestionSignature{RootID: rootID(&questions[i]), QuestionID: questions[i].ID, Signature: sig, Shingles: n}
+			if err := s.questionRepo.SaveQuestionSignature(ctx, signature, lshBuckets(sig)); err != nil {
+				return indexed, fmt.Errorf("save question signature failed: %w", err)
+			}
+			indexed++
+		}
+	}
+}
+
+// DuplicateReport кластеры похожих вопросов банка: пары с похожестью от threshold, объединённые
+// в компоненты связности. Перед отчётом индексируются вопросы без сигнатуры.
+func (s *questionService) DuplicateReport(ctx context.Context, threshold float64) (*models.DuplicateReport, error) {
+	if threshold <= 0 {
+		threshold = similarityThreshold
+	}
+	if _, err := s.ReindexSimilarity(ctx); err != nil {
+		return nil, err
+	}
+	index, err := s.questionRepo.ListSimilarityIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load similarity index failed: %w", err)
+	}
+
+	// кандидаты — вопросы из общих корзин LSH
+	buckets := map[int64][]int{}
+	for i, c := range index {
+		for _, b := range lshBuckets(c.Signature) {
+			buckets[b] = append(buckets[b], i)
+		}
+	}
+	type pair struct{ a, b int }
+	checked := map[pair]bool{}
+	parent := make([]int, len(index))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	pairIndex := map[pair]float64{}
+	for _, members := range buckets {
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				p := pair{members[x], members[y]}
+				if p.a > p.b {
+					p.a, p.b = p.b, p.a
+				}
+				if checked[p] {
+					continue
+				}
+				checked[p] = true
+				sim := signatureSimilarity(index[p.a].Signature, index[p.b].Signature)
+				if sim < threshold {
+					continue
+				}
+				pairIndex[p] = sim
+				parent[find(p.a)] = find(p.b)
+			}
+		}
+	}
+
+	clusters := map[int]*models.DuplicateCluster{}
+	best := map[int]float64{}
+	for p, sim := range pairIndex {
+		root := find(p.a)
+		cluster, ok := clusters[root]
+		if !ok {
+			cluster = &models.DuplicateCluster{}
+			clusters[root] = cluster
+		}
+		cluster.Pairs = append(cluster.Pairs, models.DuplicatePair{A: index[p.a].QuestionID, B: index[p.b].QuestionID, Similarity: sim})
+		cluster.MaxSimilarity = max(cluster.MaxSimilarity, sim)
+		best[p.a] = max(best[p.a], sim)
+		best[p.b] = max(best[p.b], sim)
+	}
+	for i, sim := range best {
+		cluster := clusters[find(i)]
+		cluster.Questions = append(cluster.Questions, similarQuestion(index[i], sim))
+	}
+
+	report := &models.DuplicateReport{
+		Threshold:   threshold,
+		Indexed:     len(index),
+		Clusters:    make([]models.DuplicateCluster, 0, len(clusters)),
+		GeneratedAt: time.Now(),
+	}
+	for _, cluster := range clusters {
+		sort.Slice(cluster.Questions, func(i, j int) bool {
+			if cluster.Questions[i].Similarity != cluster.Questions[j].Similarity {
+				return cluster.Questions[i].Similarity > cluster.Questions[j].Similarity
+			}
+			return cluster.Questions[i].QuestionID < cluster.Questions[j].QuestionID
+		})
+		sort.Slice(cluster.Pairs, func(i, j int) bool { return cluster.Pairs[i].Similarity > cluster.Pairs[j].Similarity })
+		report.Clusters = append(report.Clusters, *cluster)
+	}
+	sort.SliceStable(report.Clusters, func(i, j int) bool {
+		if report.Clusters[i].MaxSimilarity != report.Clusters[j].MaxSimilarity {
+			return report.Clusters[i].MaxSimilarity > report.Clusters[j].MaxSimilarity
+		}
+		return len(report.Clusters[i].Questions) > len(report.Clusters[j].Questions)
+	})
+	return report, nil
+}
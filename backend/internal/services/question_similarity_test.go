@@ -0,0 +1,152 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+func TestShinglesIgnoreCaseAndPunctuation(t *testing.T) {
+	a := shingles("Close the channel, then range over it!")
+	b := shingles("close   THE channel\nthen range (over) it")
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("shingles differ for the same words")
+	}
+	if len(a) != 5 {
+		t.Errorf("%d shingles, want 5 (7 words by %d)", len(a), shingleSize)
+	}
+
+	// текст короче шингла — один шингл из всех слов
+	if got := len(shingles("Goroutines?")); got != 1 {
+		t.Errorf("short text: %d shingles, want 1", got)
+	}
+	if got := len(shingles(" ... ")); got != 0 {
+		t.Errorf("no words: %d shingles, want 0", got)
+	}
+}
+
+func TestMinhashEstimatesJaccard(t *testing.T) {
+	set := func(from, to uint64) map[uint64]struct{} {
+		s := map[uint64]struct{}{}
+		for i := from; i < to; i++ {
+			s[mix64(i)] = struct{}{}
+		}
+		return s
+	}
+
+	if minhash(map[uint64]struct{}{}) != nil {
+		t.Errorf("empty set must have no signature")
+	}
+	a := minhash(set(0, 1000))
+	if len(a) != minhashSize {
+		t.Fatalf("signature size = %d, want %d", len(a), minhashSize)
+	}
+	if sim := signatureSimilarity(a, minhash(set(0, 1000))); sim != 1 {
+		t.Errorf("same set: similarity = %v, want 1", sim)
+	}
+
+	tests := []struct {
+		from, to uint64
+		jaccard  float64
+	}{
+		{500, 1500, 1.0 / 3},
+		{100, 1100, 0.9 / 1.1},
+		{5000, 6000, 0},
+	}
+	for _, tt := range tests {
+		sim := signatureSimilarity(a, minhash(set(tt.from, tt.to)))
+		// стандартная ошибка оценки при 128 значениях — не больше 0.045
+		if math.Abs(sim-tt.jaccard) > 0.15 {
+			t.Errorf("[%d, %d): similarity = %v, want ≈ %v", tt.from, tt.to, sim, tt.jaccard)
+		}
+	}
+}
+
+func TestSignatureSimilarityMismatchedLength(t *testing.T) {
+	if sim := signatureSimilarity(nil, nil); sim != 0 {
+		t.Errorf("empty signatures: similarity = %v, want 0", sim)
+	}
+	if sim := signatureSimilarity([]uint32{1, 2}, []uint32{1}); sim != 0 {
+		t.Errorf("different lengths: similarity = %v, want 0", sim)
+	}
+}
+
+func TestLSHBuckets(t *testing.T) {
+	sig := minhash(shingles("What happens when you send on a closed channel in Go"))
+	buckets := lshBuckets(sig)
+	if len(buckets) != lshBands {
+		t.Fatalf("%d buckets, want %d", len(buckets), lshBands)
+	}
+	if !reflect.DeepEqual(buckets, lshBuckets(append([]uint32(nil), sig...))) {
+		t.Errorf("buckets of equal signatures differ")
+	}
+
+	// изменение одного значения сигнатуры меняет ровно одну полосу
+	changed := append([]uint32(nil), sig...)
+	changed[5]++
+	diff := 0
+	for i, b := range lshBuckets(changed) {
+		if b != buckets[i] {
+			diff++
+		}
+	}
+	if diff != 1 {
+		t.Errorf("%d buckets changed, want 1", diff)
+	}
+
+	// одинаковые значения в разных полосах не попадают в одну корзину
+	flat := make([]uint32, minhashSize)
+	seen := map[int64]bool{}
+	for _, b := range lshBuckets(flat) {
+		if seen[b] {
+			t.Errorf("bands share bucket %d", b)
+		}
+		seen[b] = true
+	}
+
+	if lshBuckets(nil) != nil || lshBuckets(sig[:10]) != nil {
+		t.Errorf("signature of the wrong size must have no buckets")
+	}
+}
+
+func TestQuestionSignatureNearDuplicates(t *testing.T) {
+	question := func(title, description string, options ...string) *models.Question {
+		q := &models.Question{Title: title, Description: description}
+		for _, o := range options {
+			q.Options = append(q.Options, models.QuestionOption{Text: o})
+		}
+		return q
+	}
+	original := question("Closed channels",
+		"What does a receive operation return when it reads from a channel that has already been closed by the sender goroutine?",
+		"The zero value of the element type and ok set to false", "It blocks forever", "It panics at runtime")
+	reworded := question("Closed channels!",
+		"What does a receive operation return when it reads from a channel that has already been closed by the sending goroutine?",
+		"The zero value of the element type and ok set to false", "It blocks forever", "It panics at runtime")
+	other := question("Map iteration order",
+		"Is the iteration order of a Go map stable between two consecutive range loops over the same map?",
+		"Yes, always", "No, it is randomized", "Only for maps with string keys")
+
+	sig, n := questionSignature(original)
+	if n == 0 || len(sig) != minhashSize {
+		t.Fatalf("signature of %d values over %d shingles", len(sig), n)
+	}
+	near, _ := questionSignature(reworded)
+	far, _ := questionSignature(other)
+
+	if sim := signatureSimilarity(sig, near); sim < 0.6 {
+		t.Errorf("near duplicate: similarity = %v, want at least 0.6", sim)
+	}
+	if sim := signatureSimilarity(sig, far); sim > 0.1 {
+		t.Errorf("unrelated question: similarity = %v, want close to 0", sim)
+	}
+
+	// порядок стартовых файлов не влияет на текст вопроса
+	a := &models.Question{StarterFiles: map[string]string{"main.go": "package main", "util.go": "package util"}}
+	b := &models.Question{StarterFiles: map[string]string{"util.go": "package util", "main.go": "package main"}}
+	if questionText(a) != questionText(b) {
+		t.Errorf("question text depends on starter files order")
+	}
+}
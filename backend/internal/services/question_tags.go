@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/easyhire/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrQuestionTagExists тег с таким именем уже есть в справочнике
+var ErrQuestionTagExists = errors.New("question tag with this name already exists")
+
+// ==========================
+// TAGS
+// ==========================
+
+func (s *questionService) ListTags(ctx context.Context) ([]models.QuestionTag, error) {
+	tags, err := s.questionRepo.ListTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list tags failed: %w", err)
+	}
+	return tags, nil
+}
+
+func (s *questionService) CreateTag(ctx context.Context, req models.QuestionTagRequest) (*models.QuestionTag, error) {
+	name := normalizeTag(req.Name)
+	if name == "" {
+		return nil, errors.New("tag name is required")
+	}
+	if err := s.ensureTagNameFree(ctx, name, ""); err != nil {
+		return nil, err
+	}
+
+	tag := &models.QuestionTag{Name: name, Description: req.Description, Color: req.Color}
+	if err := s.questionRepo.CreateTag(ctx, tag); err != nil {
+		return nil, fmt.Errorf("create tag failed: %w", err)
+	}
+	return tag, nil
+}
+
+// UpdateTag переименование и оформление тега; связи с вопросами сохраняются
+func (s *questionService) UpdateTag(ctx context.Context, id string, req models.QuestionTagRequest) (*models.QuestionTag, error) {
+	tag, err := s.questionRepo.GetTag(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("tag not found: %w", err)
+	}
+	name := normalizeTag(req.Name)
+	if name == "" {
+		return nil, errors.New("tag name is required")
+	}
+	if err := s.ensureTagNameFree(ctx, name, tag.ID); err != nil {
+		return nil, err
+	}
+
+	tag.Name = name
+	tag.Description = req.Description
+	tag.Color = req.Color
+	if err := s.questionRepo.UpdateTag(ctx, tag); err != nil {
+		return nil, fmt.Errorf("update tag failed: %w", err)
+	}
+	return tag, nil
+}
+
+// DeleteTag удаляет тег у всех вопросов (включая старые версии)
+func (s *questionService) DeleteTag(ctx context.Context, id string) error {
+	if err := s.questionRepo.DeleteTag(ctx, id); err != nil {
+		return fmt.Errorf("delete tag failed: %w", err)
+	}
+	return nil
+}
+
+// SetQuestionTags заменяет теги последней версии вопроса. Теги — метаданные банка:
+// новая версия не создаётся и статус проверки не сбрасывается.
+func (s *questionService) SetQuestionTags(ctx context.Context, id string, tags []string) (*models.Question, error) {
+	question, err := s.latest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	next := []models.QuestionTag{}
+	for _, tag := range normalizeTags(tags) {
+		if len(tag) > 100 {
+			return nil, fmt.Errorf("tag %q is longer than 100 characters", tag)
+		}
+		next = append(next, models.QuestionTag{Name: tag})
+	}
+	if err := s.questionRepo.SetQuestionTags(ctx, question.ID, next); err != nil {
+		return nil, fmt.Errorf("set question tags failed: %w", err)
+	}
+	return s.questionRepo.GetQuestionByID(ctx, question.ID)
+}
+
+func (s *questionService) ensureTagNameFree(ctx context.Context, name, tagID string) error {
+	existing, err := s.questionRepo.GetTagByName(ctx, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("lookup tag failed: %w", err)
+	}
+	if existing.ID != tagID {
+		return ErrQuestionTagExists
+	}
+	return nil
+}
+
+// normalizeTag имя тега в справочнике: без крайних пробелов, в нижнем регистре
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// normalizeTags непустые нормализованные теги без повторов, в исходном порядке
+func normalizeTags(tags []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
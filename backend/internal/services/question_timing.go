@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+var (
+	// ErrSessionNotInProgress сессия ещё не начата или уже сдана
+	ErrSessionNotInProgress = errors.New("session is not in progress")
+	// ErrQuestionNotInAssessment вопрос не из оценки, по которой идёт сессия
+	ErrQuestionNotInAssessment = errors.New("question does not belong to the session's assessment")
+)
+
+// ServeQuestion выдаёт вопрос кандидату и фиксирует момент выдачи (StartedAt ответа).
+// Повторный вызов возвращает тот же момент — время не сбрасывается.
+func (s *assessmentService) ServeQuestion(ctx context.Context, sessionID, questionID string) (*models.ServedQuestion, error) {
+	if sessionID == "" || questionID == "" {
+		return nil, fmt.Errorf("session_id and question_id are required")
+	}
+
+	session, err := s.assessmentRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	if session.Status != models.SessionStatusInProgress {
+		return nil, ErrSessionNotInProgress
+	}
+
+	question, err := s.sessionQuestion(ctx, session, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	answer, err := s.assessmentRepo.GetAnswer(ctx, sessionID, questionID)
+	if err != nil || answer == nil {
+		answer = &models.CandidateAnswer{
+			SessionID:  sessionID,
+			QuestionID: questionID,
+			StartedAt:  time.Now(),
+		}
+		if err := s.assessmentRepo.SaveAnswer(ctx, answer); err != nil {
+			return nil, fmt.Errorf("save served question failed: %w", err)
+		}
+	}
+
+	limit := questionTimeLimit(*question, activeTimeBonuses(ctx, s.scoringService))
+	served := &models.ServedQuestion{
+		ID:          question.ID,
+		Title:       question.Title,
+		Description: question.Description,
+		Type:        question.Type,
+		Difficulty:  question.Difficulty,
+		Files:       question.StarterFiles,
+		TimeLimit:   limit,
+		ServedAt:    answer.StartedAt,
+		Deadline:    answer.StartedAt.Add(time.Duration(limit) * time.Second),
+	}
+	for _, opt := range question.Options {
+		served.Options = append(served.Options, models.ServedQuestionOption{
+			ID:    opt.ID,
+			Text:  opt.Text,
+			Order: opt.Order,
+		})
+	}
+	return served, nil
+}
+
+// sessionQuestion вопрос сессии: из фиксированного набора оценки, а если набора нет —
+// из пула её компетенций (см. inQuestionPool)
+func (s *assessmentService) sessionQuestion(ctx context.Context, session *models.AssessmentSession, questionID string) (*models.Question, error) {
+	question, err := s.questionRepo.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+	assessment, err := s.assessmentRepo.GetAssessmentWithQuestions(ctx, session.AssessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("assessment not found: %w", err)
+	}
+
+	if len(assessment.Questions) > 0 {
+		for _, aq := range assessment.Questions {
+			if aq.QuestionID == question.ID {
+				return question, nil
+			}
+		}
+		return nil, ErrQuestionNotInAssessment
+	}
+	if inQuestionPool(assessment, question) {
+		return question, nil
+	}
+	// уже выданный вопрос остаётся в сессии, даже если его успели снять или заменить новой версией
+	if answer, err := s.assessmentRepo.GetAnswer(ctx, session.ID, question.ID); err == nil && answer != nil {
+		return question, nil
+	}
+	return nil, ErrQuestionNotInAssessment
+}
+
+// inQuestionPool вопрос из пула оценки: те же условия, что у GetRandomQuestions, —
+// актуальная одобренная версия по компетенции и уровню оценки, с учётом её структуры
+func inQuestionPool(assessment *models.Assessment, question *models.Question) bool {
+	if !question.IsActive || !question.IsLatest || question.ValidationStatus != models.ValidationStatusApproved {
+		return false
+	}
+	if len(assessment.QuestionTypes) > 0 && assessment.QuestionTypes[question.Type] == 0 {
+		return false
+	}
+	for _, c := range assessment.Competencies {
+		if c.CompetencyID != question.Competency {
+			continue
+		}
+		// распределение по уровням заменяет уровень компетенции
+		if len(assessment.LevelDistribution) > 0 {
+			return assessment.LevelDistribution[question.Difficulty] > 0
+		}
+		return c.Level == "" || c.Level == string(question.Difficulty)
+	}
+	return false
+}
+
+// implicitServeTime момент выдачи для ответа на вопрос, который не запрашивали через ServeQuestion:
+// конец предыдущего ответа или начало сессии
+func (s *assessmentService) implicitServeTime(ctx context.Context, session *models.AssessmentSession, now time.Time) time.Time {
+	served := now
+	if session.StartedAt != nil {
+		served = *session.StartedAt
+	}
+
+	answers, err := s.assessmentRepo.GetSessionAnswers(ctx, session.ID)
+	if err != nil {
+		return served
+	}
+	for _, a := range answers {
+		if a.SubmittedAt != nil && a.SubmittedAt.After(served) && !a.SubmittedAt.After(now) {
+			served = *a.SubmittedAt
+		}
+	}
+	return served
+}
+
+// measureAnswerTime серверное время ответа: от выдачи вопроса до отправки.
+// Опоздание — по тому же правилу, что и штраф в calculateTimeBonus: лимит плюс LateGraceSeconds.
+func measureAnswerTime(answer *models.CandidateAnswer, question *models.Question, submittedAt time.Time, bonuses models.TimeBonusConfig) {
+	spent := int(submittedAt.Sub(answer.StartedAt).Seconds())
+	if spent < 0 {
+		spent = 0
+	}
+	answer.TimeSpent = spent
+	limit := questionTimeLimit(*question, bonuses)
+	answer.IsLate = limit > 0 && spent > limit+bonuses.LateGraceSeconds
+}
+
+// questionTimeLimit лимит вопроса; без него — BaselineSeconds формулы, как в calculateTimeBonus
+func questionTimeLimit(q models.Question, bonuses models.TimeBonusConfig) int {
+	if q.TimeLimit > 0 {
+		return q.TimeLimit
+	}
+	return bonuses.BaselineSeconds
+}
+
+// activeTimeBonuses нормы времени активной формулы; если её не загрузить — значения по умолчанию
+func activeTimeBonuses(ctx context.Context, scoring ScoringService) models.TimeBonusConfig {
+	if scoring != nil {
+		if active, err := scoring.ActiveConfig(ctx); err == nil {
+			return active.Config.TimeBonuses
+		}
+	}
+	return models.DefaultScoringConfig().TimeBonuses
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+func TestInQuestionPool(t *testing.T) {
+	question := func(edit func(q *models.Question)) *models.Question {
+		q := &models.Question{
+			Type:             models.QuestionTypeMultipleChoice,
+			Difficulty:       models.DifficultyMiddle,
+			Competency:       "concurrency",
+			IsActive:         true,
+			IsLatest:         true,
+			ValidationStatus: models.ValidationStatusApproved,
+		}
+		if edit != nil {
+			edit(q)
+		}
+		return q
+	}
+	assessment := &models.Assessment{Competencies: []models.AssessmentCompetency{{CompetencyID: "concurrency", Level: "middle"}}}
+	structured := &models.Assessment{
+		Competencies:      assessment.Competencies,
+		QuestionTypes:     map[models.QuestionType]int{models.QuestionTypeMultipleChoice: 5},
+		LevelDistribution: map[models.DifficultyLevel]int{models.DifficultyMiddle: 3, models.DifficultySenior: 2},
+	}
+
+	tests := []struct {
+		name       string
+		assessment *models.Assessment
+		question   *models.Question
+		want       bool
+	}{
+		{"approved current", assessment, question(nil), true},
+		{"inactive", assessment, question(func(q *models.Question) { q.IsActive = false }), false},
+		{"old version", assessment, question(func(q *models.Question) { q.IsLatest = false }), false},
+		{"not approved", assessment, question(func(q *models.Question) { q.ValidationStatus = models.ValidationStatusPending }), false},
+		{"other competency", assessment, question(func(q *models.Question) { q.Competency = "testing" }), false},
+		{"other level", assessment, question(func(q *models.Question) { q.Difficulty = models.DifficultySenior }), false},
+		{"level from distribution", structured, question(func(q *models.Question) { q.Difficulty = models.DifficultySenior }), true},
+		{"level not in distribution", structured, question(func(q *models.Question) { q.Difficulty = models.DifficultyJunior }), false},
+		{"type not in structure", structured, question(func(q *models.Question) { q.Type = models.QuestionTypeCoding }), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inQuestionPool(tt.assessment, tt.question); got != tt.want {
+				t.Errorf("inQuestionPool = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Опоздание отмечается по тому же правилу, что и штраф за время
+func TestMeasureAnswerTimeMatchesTimeBonus(t *testing.T) {
+	bonuses := models.DefaultScoringConfig().TimeBonuses
+	bonuses.BaselineSeconds = 200
+	started := time.Now()
+
+	tests := []struct {
+		name      string
+		timeLimit int
+		spent     int
+		late      bool
+	}{
+		{"in time", 100, 90, false},
+		{"within grace", 100, 100 + bonuses.LateGraceSeconds, false},
+		{"late", 100, 101 + bonuses.LateGraceSeconds, true},
+		{"baseline when no limit", 0, 150, false},
+		{"late against baseline", 0, 201 + bonuses.LateGraceSeconds, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &models.Question{TimeLimit: tt.timeLimit}
+			answer := &models.CandidateAnswer{StartedAt: started}
+			measureAnswerTime(answer, q, started.Add(time.Duration(tt.spent)*time.Second), bonuses)
+
+			if answer.TimeSpent != tt.spent || answer.IsLate != tt.late {
+				t.Errorf("time spent = %d, late = %v, want %d and %v", answer.TimeSpent, answer.IsLate, tt.spent, tt.late)
+			}
+			penalized := calculateTimeBonus(answer.TimeSpent, q.TimeLimit, bonuses) < 1
+			if answer.IsLate != penalized {
+				t.Errorf("late = %v, but time bonus penalized = %v", answer.IsLate, penalized)
+			}
+		})
+	}
+}
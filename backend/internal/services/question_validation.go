@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easyhire/backend/internal/executor"
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+)
+
+// solutionCheckTimeout лимит на один тест-кейс эталонного решения (и решения кандидата)
+const solutionCheckTimeout = 30
+
+var (
+	// ErrInvalidValidationTransition действие недопустимо в текущем статусе
+	ErrInvalidValidationTransition = errors.New("action is not allowed in the current validation status")
+	// ErrSelfValidation автор не может одобрить свой вопрос (кроме админа)
+	ErrSelfValidation = errors.New("author cannot validate own question")
+)
+
+// SolutionCheckError эталонное решение не прошло тест-кейсы
+type SolutionCheckError struct {
+	Check *models.SolutionCheck
+}
+
+func (e *SolutionCheckError) Error() string {
+	if e.Check.Reason != "" {
+		return "reference solution check failed: " + e.Check.Reason
+	}
+	return "reference solution check failed"
+}
+
+// ==========================
+// VALIDATION WORKFLOW
+// ==========================
+
+// SubmitForReview draft/needs_review → pending. Эталонное решение прогоняется сразу:
+// с падающими тестами вопрос на проверку не уходит. Если executor недоступен,
+// вопрос отправляется, а решение будет проверено при одобрении.
+func (s *questionService) SubmitForReview(ctx context.Context, id, userID string, req models.SubmitQuestionRequest) (*models.Question, error) {
+	question, err := s.latest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if question.ValidationStatus != models.ValidationStatusDraft && question.ValidationStatus != models.ValidationStatusNeedsReview {
+		return nil, ErrInvalidValidationTransition
+	}
+
+	check, err := s.runSolutionCheck(ctx, question)
+	if err != nil {
+		check = &models.SolutionCheck{Skipped: true, Reason: err.Error(), CheckedAt: time.Now()}
+	} else if !check.Passed && !check.Skipped {
+		return nil, &SolutionCheckError{Check: check}
+	}
+
+	entry := &models.QuestionValidation{
+		QuestionID:    question.ID,
+		Action:        models.ValidationActionSubmit,
+		FromStatus:    question.ValidationStatus,
+		ToStatus:      models.ValidationStatusPending,
+		Comment:       req.Comment,
+		SolutionCheck: check,
+	}
+	if userID != "" {
+		entry.UserID = &userID
+	}
+	return s.changeStatus(ctx, question, entry)
+}
+
+// ValidateQuestion решение проверяющего по вопросу в статусе pending.
+// Одобрение требует успешного прогона эталонного решения (если у вопроса есть тест-кейсы).
+func (s *questionService) ValidateQuestion(ctx context.Context, id, reviewerID string, isAdmin bool, req models.ValidateQuestionRequest) (*models.Question, error) {
+	question, err := s.latest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if question.ValidationStatus != models.ValidationStatusPending {
+		return nil, ErrInvalidValidationTransition
+	}
+	if question.CreatedBy == reviewerID && !isAdmin {
+		return nil, ErrSelfValidation
+	}
+
+	entry := &models.QuestionValidation{
+		QuestionID: question.ID,
+		UserID:     &reviewerID,
+		Action:     req.Action,
+		FromStatus: question.ValidationStatus,
+		Comment:    req.Comment,
+	}
+
+	switch req.Action {
+	case models.ValidationActionApprove:
+		check, err := s.runSolutionCheck(ctx, question)
+		if err != nil {
+			return nil, fmt.Errorf("reference solution check failed: %w", err)
+		}
+		if !check.Passed && !check.Skipped {
+			return nil, &SolutionCheckError{Check: check}
+		}
+		entry.SolutionCheck = check
+		entry.ToStatus = models.ValidationStatusApproved
+	case models.ValidationActionReject:
+		entry.ToStatus = models.ValidationStatusRejected
+	case models.ValidationActionNeedsReview:
+		if strings.TrimSpace(req.Comment) == "" {
+			return nil, fmt.Errorf("comment is required when returning a question for changes")
+		}
+		entry.ToStatus = models.ValidationStatusNeedsReview
+	default:
+		return nil, fmt.Errorf("unknown action %q", req.Action)
+	}
+
+	now := time.Now()
+	question.ValidatedBy = &reviewerID
+	question.ValidatedAt = &now
+	return s.changeStatus(ctx, question, entry)
+}
+
+// CheckSolution прогон эталонного решения без смены статуса
+func (s *questionService) CheckSolution(ctx context.Context, id string) (*models.SolutionCheck, error) {
+	question, err := s.questionRepo.GetQuestionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+	return s.runSolutionCheck(ctx, question)
+}
+
+// ListValidations журнал проверки версии вопроса с комментариями
+func (s *questionService) ListValidations(ctx context.Context, id string) ([]models.QuestionValidation, error) {
+	if _, err := s.questionRepo.GetQuestionByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+	entries, err := s.questionRepo.ListValidations(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("load validations failed: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *questionService) changeStatus(ctx context.Context, question *models.Question, entry *models.QuestionValidation) (*models.Question, error) {
+	if err := s.questionRepo.ChangeValidationStatus(ctx, question, entry); err != nil {
+		if errors.Is(err, repository.ErrValidationConflict) {
+			return nil, ErrInvalidValidationTransition
+		}
+		return nil, fmt.Errorf("update validation status failed: %w", err)
+	}
+	return s.questionRepo.GetQuestionByID(ctx, question.ID)
+}
+
+// ==========================
+// REFERENCE SOLUTION
+// ==========================
+
+// runSolutionCheck запускает эталонное решение на каждом тест-кейсе (stdin → stdout).
+// Вопросы без исполняемых тест-кейсов пропускаются; ошибка — только если executor недоступен.
+func (s *questionService) runSolutionCheck(ctx context.Context, q *models.Question) (*models.SolutionCheck, error) {
+	check := &models.SolutionCheck{
+		Language:  q.SolutionLanguage,
+		CheckedAt: time.Now(),
+	}
+
+	if (q.Type != models.QuestionTypeCoding && q.Type != models.QuestionTypeDebugging) || len(q.TestCases) == 0 {
+		check.Skipped = true
+		check.Reason = "question has no executable test cases"
+		return check, nil
+	}
+	if strings.TrimSpace(q.ReferenceSolution) == "" {
+		check.Reason = "reference solution is required for questions with test cases"
+		return check, nil
+	}
+
+	files, err := solutionFiles(q.SolutionLanguage, q.ReferenceSolution)
+	if err != nil {
+		check.Reason = err.Error()
+		return check, nil
+	}
+
+	check.Passed = true
+	for _, tc := range q.TestCases {
+		resp, err := s.executor.Execute(ctx, executor.ExecuteRequest{
+			Language:       q.SolutionLanguage,
+			Mode:           "run",
+			Files:          files,
+			Stdin:          tc.Input,
+			TimeoutSeconds: solutionCheckTimeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result := models.SolutionCaseResult{
+			Order:      tc.Order,
+			Hidden:     tc.IsHidden,
+			Expected:   tc.Expected,
+			Actual:     resp.Stdout,
+			DurationMs: resp.Duration.Milliseconds(),
+		}
+		switch {
+		case resp.Error == "timeout":
+			result.Error = "timeout"
+		case resp.ExitCode != 0:
+			result.Error = fmt.Sprintf("exit code %d: %s", resp.ExitCode, strings.TrimSpace(resp.Stderr))
+		default:
+			result.Passed = normalizeOutput(resp.Stdout) == normalizeOutput(tc.Expected)
+		}
+		if !result.Passed {
+			check.Passed = false
+		}
+		check.Cases = append(check.Cases, result)
+	}
+	if !check.Passed {
+		check.Reason = "reference solution fails some test cases"
+	}
+	return check, nil
+}
+
+// solutionFiles файлы проекта для режима "run" executor
+func solutionFiles(language, code string) (map[string]string, error) {
+	switch language {
+	case "go":
+		return map[string]string{
+			"go.mod":  "module solution\n\ngo 1.22\n",
+			"main.go": code,
+		}, nil
+	case "python":
+		return map[string]string{"main.py": code}, nil
+	case "javascript":
+		return map[string]string{"main.js": code}, nil
+	default:
+		return nil, fmt.Errorf("unsupported solution language %q", language)
+	}
+}
+
+func normalizeOutput(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ResultService нормализация результатов: перцентиль, z-score и ранг среди
+// всех завершённых сессий оценки — в целом и по каждой компетенции
+type ResultService interface {
+	RecordResult(ctx context.Context, result *models.Result, assessmentID string) error
+	GetResultStanding(ctx context.Context, resultID string) (*models.ResultStanding, error)
+	GetDistributions(ctx context.Context, assessmentID string) ([]models.ScoreDistribution, error)
+}
+
+type resultService struct {
+	resultRepo     repository.ResultRepository
+	assessmentRepo repository.AssessmentRepository
+}
+
+func NewResultService(resultRepo repository.ResultRepository, assessmentRepo repository.AssessmentRepository) ResultService {
+	return &resultService{
+		resultRepo:     resultRepo,
+		assessmentRepo: assessmentRepo,
+	}
+}
+
+// RecordResult добавляет новый результат в распределения оценки (инкрементально)
+func (s *resultService) RecordResult(ctx context.Context, result *models.Result, assessmentID string) error {
+	scores := []models.ResultScore{{
+		ResultID:     result.ID,
+		AssessmentID: assessmentID,
+		Percentage:   result.Percentage,
+	}}
+	for _, cs := range result.CompetencyBreakdown {
+		if cs.Possible <= 0 {
+			continue
+		}
+		scores = append(scores, models.ResultScore{
+			ResultID:     result.ID,
+			AssessmentID: assessmentID,
+			Competency:   cs.CompetencyID,
+			Percentage:   cs.Percentage,
+		})
+	}
+
+	if err := s.resultRepo.RecordScores(ctx, scores); err != nil {
+		return fmt.Errorf("record result scores failed: %w", err)
+	}
+	return nil
+}
+
+// ==========================
+// STANDING
+// ==========================
+
+func (s *resultService) GetResultStanding(ctx context.Context, resultID string) (*models.ResultStanding, error) {
+	result, err := s.assessmentRepo.GetResultByID(ctx, resultID)
+	if err != nil {
+		return nil, fmt.Errorf("result not found: %w", err)
+	}
+
+	scores, err := s.resultRepo.GetResultScores(ctx, resultID)
+	if err != nil {
+		return nil, fmt.Errorf("load result scores failed: %w", err)
+	}
+
+	// результат появился до нормализации и не попал в backfill — учитываем сейчас
+	if len(scores) == 0 {
+		session, err := s.assessmentRepo.GetSessionByID(ctx, result.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("session not found: %w", err)
+		}
+		if err := s.RecordResult(ctx, result, session.AssessmentID); err != nil {
+			return nil, err
+		}
+		if scores, err = s.resultRepo.GetResultScores(ctx, resultID); err != nil {
+			return nil, fmt.Errorf("load result scores failed: %w", err)
+		}
+	}
+
+	standing := &models.ResultStanding{
+		ResultID:     resultID,
+		Competencies: []models.ScoreStanding{},
+	}
+	for _, sc := range scores {
+		st, err := s.standing(ctx, sc)
+		if err != nil {
+			return nil, err
+		}
+		standing.AssessmentID = sc.AssessmentID
+		if sc.Competency == "" {
+			standing.Overall = st
+		} else {
+			standing.Competencies = append(standing.Competencies, st)
+		}
+	}
+	return standing, nil
+}
+
+// standing место процента в своём разрезе. Перцентиль — доля результатов ниже
+// плюс половина равных (mid-rank), ранг — 1 + число результатов строго выше.
+func (s *resultService) standing(ctx context.Context, sc models.ResultScore) (models.ScoreStanding, error) {
+	st := models.ScoreStanding{
+		Competency: sc.Competency,
+		Percentage: sc.Percentage,
+	}
+
+	counts, err := s.resultRepo.CountScores(ctx, sc.AssessmentID, sc.Competency, sc.Percentage)
+	if err != nil {
+		return st, fmt.Errorf("count scores failed: %w", err)
+	}
+	dist, err := s.resultRepo.GetDistribution(ctx, sc.AssessmentID, sc.Competency)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return st, fmt.Errorf("load distribution failed: %w", err)
+	}
+
+	st.Total = counts.Total
+	st.Rank = counts.Above + 1
+	if counts.Total > 0 {
+		equal := counts.Total - counts.Above - counts.Below
+		st.Percentile = round4((float64(counts.Below) + 0.5*float64(equal)) / float64(counts.Total) * 100)
+	}
+	if dist != nil {
+		st.Mean = round4(dist.Mean)
+		st.StdDev = round4(dist.StdDev())
+		if sd := dist.StdDev(); sd > 0 {
+			st.ZScore = round4((sc.Percentage - dist.Mean) / sd)
+		}
+	}
+	return st, nil
+}
+
+func (s *resultService) GetDistributions(ctx context.Context, assessmentID string) ([]models.ScoreDistribution, error) {
+	if _, err := s.assessmentRepo.GetAssessmentByID(ctx, assessmentID); err != nil {
+		return nil, fmt.Errorf("assessment not found: %w", err)
+	}
+	dists, err := s.resultRepo.ListDistributions(ctx, assessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("load distributions failed: %w", err)
+	}
+	for i := range dists {
+		dists[i].Mean = round4(dists[i].Mean)
+		dists[i].StdDevValue = round4(dists[i].StdDev())
+	}
+	return dists, nil
+}
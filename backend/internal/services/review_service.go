@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/easyhire/backend/internal/models"
+	"github.com/easyhire/backend/internal/repository"
+)
+
+// ErrReviewTaken проверку одновременно назначили другому эксперту или изменили
+var ErrReviewTaken = errors.New("review was taken or changed concurrently")
+
+type ReviewService interface {
+	ListReviews(ctx context.Context, filter repository.ReviewFilter) ([]models.AnswerReview, int64, error)
+	GetReview(ctx context.Context, id, viewerID string, privileged bool) (*models.ReviewDetail, error)
+	AssignReview(ctx context.Context, id, reviewerID string) (*models.AnswerReview, error)
+	ClaimReview(ctx context.Context, id, reviewerID string) (*models.AnswerReview, error)
+	SubmitReview(ctx context.Context, id, reviewerID string, req models.SubmitReviewRequest) (*models.AnswerReview, error)
+	AcceptAISuggestion(ctx context.Context, id, reviewerID string, req models.AcceptAISuggestionRequest) (*models.AnswerReview, error)
+
+	// Calibration
+	AgreementReport(ctx context.Context, filter repository.AgreementFilter, reviewerID string) (*models.AgreementReport, error)
+}
+
+type reviewService struct {
+	reviewRepo        repository.ReviewRepository
+	assessmentRepo    repository.AssessmentRepository
+	questionRepo      repository.QuestionRepository
+	assessmentService AssessmentService
+}
+
+func NewReviewService(
+	reviewRepo repository.ReviewRepository,
+	assessmentRepo repository.AssessmentRepository,
+	questionRepo repository.QuestionRepository,
+	assessmentService AssessmentService,
+) ReviewService {
+	return &reviewService{
+		reviewRepo:        reviewRepo,
+		assessmentRepo:    assessmentRepo,
+		questionRepo:      questionRepo,
+		assessmentService: assessmentService,
+	}
+}
+
+func (s *reviewService) ListReviews(ctx context.Context, filter repository.ReviewFilter) ([]models.AnswerReview, int64, error) {
+	return s.reviewRepo.ListReviews(ctx, filter)
+}
+
+// GetReview проверка с вопросом и ответом. Проверка слепая: эксперт видит только свои проверки
+// и очередь, а оценки коллег (Peers) — после того как выставил свою. Подсказку модели при нескольких
+// экспертах на ответ видит только арбитр (aiSuggestionHidden). HR/админ видят всё.
+func (s *reviewService) GetReview(ctx context.Context, id, viewerID string, privileged bool) (*models.ReviewDetail, error) {
+	review, err := s.reviewRepo.GetReviewByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("review not found: %w", err)
+	}
+
+	own := review.ReviewerID != nil && *review.ReviewerID == viewerID
+	if !privileged && !own && review.Status != models.ReviewStatusPending {
+		return nil, fmt.Errorf("review not found")
+	}
+
+	question, err := s.questionRepo.GetQuestionByID(ctx, review.QuestionID)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+	answer, err := s.assessmentRepo.GetAnswer(ctx, review.SessionID, review.QuestionID)
+	if err != nil {
+		return nil, fmt.Errorf("answer not found: %w", err)
+	}
+
+	detail := &models.ReviewDetail{
+		Review:   *review,
+		Question: *question,
+		Answer:   *answer,
+	}
+	hidden := false
+	if !privileged {
+		if hidden, err = aiSuggestionHidden(ctx, s.assessmentRepo, review); err != nil {
+			return nil, err
+		}
+	}
+	if suggestion, err := s.reviewRepo.GetLatestAISuggestion(ctx, review.AnswerID); err == nil && !hidden {
+		detail.AISuggestion = suggestion
+	}
+
+	// Арбитр видит оценки экспертов сразу — ради них он и назначен
+	showPeers := privileged ||
+		(own && (review.Kind == models.ReviewKindAdjudication || review.Status == models.ReviewStatusSubmitted))
+	if showPeers {
+		all, err := s.reviewRepo.GetAnswerReviews(ctx, review.AnswerID)
+		if err != nil {
+			return nil, fmt.Errorf("load reviews failed: %w", err)
+		}
+		for _, r := range all {
+			if r.ID != review.ID && r.Status == models.ReviewStatusSubmitted {
+				detail.Peers = append(detail.Peers, r)
+			}
+		}
+	}
+
+	return detail, nil
+}
+
+// AssignReview назначает проверку конкретному эксперту (HR/админ)
+func (s *reviewService) AssignReview(ctx context.Context, id, reviewerID string) (*models.AnswerReview, error) {
+	review, err := s.reviewRepo.GetReviewByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("review not found: %w", err)
+	}
+	if review.Status == models.ReviewStatusSubmitted {
+		return nil, fmt.Errorf("review already submitted")
+	}
+
+	ok, err := s.reviewRepo.IsTechnicalExpert(ctx, reviewerID)
+	if err != nil {
+		return nil, fmt.Errorf("check reviewer failed: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("reviewer must be an active technical expert")
+	}
+
+	return s.assign(ctx, review, reviewerID, models.ReviewStatusPending, models.ReviewStatusAssigned)
+}
+
+// ClaimReview эксперт сам берёт проверку из очереди
+func (s *reviewService) ClaimReview(ctx context.Context, id, reviewerID string) (*models.AnswerReview, error) {
+	review, err := s.reviewRepo.GetReviewByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("review not found: %w", err)
+	}
+	if review.Status != models.ReviewStatusPending {
+		return nil, fmt.Errorf("review is not in the queue")
+	}
+
+	return s.assign(ctx, review, reviewerID, models.ReviewStatusPending)
+}
+
+// assign назначает проверку, если она всё ещё в одном из статусов from: статус проверяется
+// в самом UPDATE, поэтому из двух одновременных запросов проходит только один
+func (s *reviewService) assign(ctx context.Context, review *models.AnswerReview, reviewerID string, from ...models.ReviewStatus) (*models.AnswerReview, error) {
+	// Один эксперт — одна оценка на ответ, иначе проверка перестаёт быть независимой
+	siblings, err := s.reviewRepo.GetAnswerReviews(ctx, review.AnswerID)
+	if err != nil {
+		return nil, fmt.Errorf("load reviews failed: %w", err)
+	}
+	for _, r := range siblings {
+		if r.ID != review.ID && r.ReviewerID != nil && *r.ReviewerID == reviewerID {
+			return nil, fmt.Errorf("reviewer already reviews this answer")
+		}
+	}
+
+	now := time.Now()
+	ok, err := s.reviewRepo.AssignReview(ctx, review.ID, reviewerID, from, now)
+	if err != nil {
+		return nil, fmt.Errorf("update review failed: %w", err)
+	}
+	if !ok {
+		return nil, ErrReviewTaken
+	}
+	review.ReviewerID = &reviewerID
+	review.Status = models.ReviewStatusAssigned
+	review.AssignedAt = &now
+	return review, nil
+}
+
+// SubmitReview сохраняет оценку эксперта. Когда все эксперты по ответу отчитались, оценки сводятся
+// (reconcileAnswer); после последней проверки сессии считается итоговый результат.
+func (s *reviewService) SubmitReview(ctx context.Context, id, reviewerID string, req models.SubmitReviewRequest) (*models.AnswerReview, error) {
+	review, err := s.reviewRepo.GetReviewByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("review not found: %w", err)
+	}
+	if review.Status == models.ReviewStatusSubmitted {
+		return nil, fmt.Errorf("review already submitted")
+	}
+	if review.ReviewerID == nil || *review.ReviewerID != reviewerID {
+		return nil, fmt.Errorf("review is not assigned to you")
+	}
+
+	question, err := s.questionRepo.GetQuestionByID(ctx, review.QuestionID)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+
+	score := req.Score
+	var criteria []models.CriterionScore
+	if len(question.Rubric) > 0 {
+		criteria, score, err = scoreRubric(question.Rubric, req.Criteria, review.MaxScore, models.ScoreSourceReview)
+		if err != nil {
+			return nil, err
+		}
+	} else if score > review.MaxScore {
+		return nil, fmt.Errorf("score must be between 0 and %.2f", review.MaxScore)
+	}
+
+	// Решение по оценке модели — для аудита AI-оценивания; скрытую подсказку эксперт не видел
+	hidden, err := aiSuggestionHidden(ctx, s.assessmentRepo, review)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion, err := s.reviewRepo.GetLatestAISuggestion(ctx, review.AnswerID); err == nil && !hidden {
+		review.AISuggestionID = &suggestion.ID
+		review.AIDecision = models.AIDecisionOverridden
+		if sameAIGrade(suggestion, score, criteria) {
+			review.AIDecision = models.AIDecisionAccepted
+		}
+	}
+
+	now := time.Now()
+	review.Score = &score
+	review.Comment = req.Comment
+	review.CriterionScores = criteria
+	review.Status = models.ReviewStatusSubmitted
+	review.SubmittedAt = &now
+
+	ok, err := s.reviewRepo.SubmitReview(ctx, review)
+	if err != nil {
+		return nil, fmt.Errorf("update review failed: %w", err)
+	}
+	if !ok {
+		return nil, ErrReviewTaken
+	}
+
+	if err := s.reconcileAnswer(ctx, review); err != nil {
+		return nil, err
+	}
+
+	open, err := s.reviewRepo.CountOpenReviews(ctx, review.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("count open reviews failed: %w", err)
+	}
+	// последние проверки могут сдать одновременно — FinalizeSession считает результат один раз
+	if open == 0 {
+		if _, err := s.assessmentService.FinalizeSession(ctx, review.SessionID); err != nil {
+			return nil, fmt.Errorf("finalize session failed: %w", err)
+		}
+	}
+
+	return review, nil
+}
+
+// AcceptAISuggestion эксперт выставляет оценку модели как свою (с обоснованиями модели по критериям)
+func (s *reviewService) AcceptAISuggestion(ctx context.Context, id, reviewerID string, req models.AcceptAISuggestionRequest) (*models.AnswerReview, error) {
+	review, err := s.reviewRepo.GetReviewByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("review not found: %w", err)
+	}
+	hidden, err := aiSuggestionHidden(ctx, s.assessmentRepo, review)
+	if err != nil {
+		return nil, err
+	}
+	if hidden {
+		return nil, ErrAISuggestionHidden
+	}
+	suggestion, err := s.reviewRepo.GetLatestAISuggestion(ctx, review.AnswerID)
+	if err != nil {
+		return nil, fmt.Errorf("AI suggestion not found: %w", err)
+	}
+
+	submit := models.SubmitReviewRequest{
+		Score:   *suggestion.Score,
+		Comment: req.Comment,
+	}
+	if submit.Comment == "" {
+		submit.Comment = suggestion.Justification
+	}
+	for _, c := range suggestion.CriterionScores {
+		submit.Criteria = append(submit.Criteria, models.CriterionScoreInput{
+			CriterionID: c.CriterionID,
+			Points:      c.Points,
+			Comment:     c.Comment,
+		})
+	}
+	return s.SubmitReview(ctx, id, reviewerID, submit)
+}
+
+// reconcileAnswer сводит оценки экспертов в оценку ответа:
+// решение арбитра окончательное; если разброс обычных оценок больше порога оценки —
+// назначается арбитраж, иначе в ответ идёт среднее.
+func (s *reviewService) reconcileAnswer(ctx context.Context, submitted *models.AnswerReview) error {
+	answer, err := s.assessmentRepo.GetAnswer(ctx, submitted.SessionID, submitted.QuestionID)
+	if err != nil {
+		return fmt.Errorf("answer not found: %w", err)
+	}
+
+	if submitted.Kind == models.ReviewKindAdjudication {
+		applyReviewScore(answer, *submitted.Score, submitted.MaxScore, submitted.CriterionScores)
+		return s.updateAnswer(ctx, answer)
+	}
+
+	all, err := s.reviewRepo.GetAnswerReviews(ctx, submitted.AnswerID)
+	if err != nil {
+		return fmt.Errorf("load reviews failed: %w", err)
+	}
+
+	var regular []models.AnswerReview
+	var reviewers []string
+	for _, r := range all {
+		if r.Kind == models.ReviewKindAdjudication {
+			return nil // арбитраж уже идёт — ждём его решения
+		}
+		if r.Status != models.ReviewStatusSubmitted {
+			return nil // ещё не все эксперты отчитались
+		}
+		regular = append(regular, r)
+		if r.ReviewerID != nil {
+			reviewers = append(reviewers, *r.ReviewerID)
+		}
+	}
+
+	threshold := defaultDisagreementThreshold
+	if session, err := s.assessmentRepo.GetSessionByID(ctx, submitted.SessionID); err == nil {
+		if assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, session.AssessmentID); err == nil && assessment.DisagreementThreshold > 0 {
+			threshold = assessment.DisagreementThreshold
+		}
+	}
+
+	if len(regular) > 1 && scoreSpread(regular) > threshold*submitted.MaxScore {
+		adjudication := models.AnswerReview{
+			Kind:       models.ReviewKindAdjudication,
+			AnswerID:   submitted.AnswerID,
+			SessionID:  submitted.SessionID,
+			QuestionID: submitted.QuestionID,
+			Status:     models.ReviewStatusPending,
+			MaxScore:   submitted.MaxScore,
+		}
+		if reviewerID, err := s.reviewRepo.PickLeastLoadedExpert(ctx, reviewers); err == nil {
+			now := time.Now()
+			adjudication.ReviewerID = &reviewerID
+			adjudication.Status = models.ReviewStatusAssigned
+			adjudication.AssignedAt = &now
+		}
+		if err := s.reviewRepo.CreateReviews(ctx, []models.AnswerReview{adjudication}); err != nil {
+			return fmt.Errorf("create adjudication failed: %w", err)
+		}
+		return nil
+	}
+
+	score, criteria := meanReviewScore(regular)
+	applyReviewScore(answer, score, submitted.MaxScore, criteria)
+	return s.updateAnswer(ctx, answer)
+}
+
+func (s *reviewService) updateAnswer(ctx context.Context, answer *models.CandidateAnswer) error {
+	if err := s.assessmentRepo.UpdateAnswer(ctx, answer); err != nil {
+		return fmt.Errorf("update answer failed: %w", err)
+	}
+	return nil
+}
+
+// =====================
+// Calibration
+// =====================
+
+// AgreementReport согласованность экспертов: общая, по экспертам и по вопросам.
+// reviewerID оставляет в отчёте одного эксперта, но пары считаются со всеми его коллегами.
+func (s *reviewService) AgreementReport(ctx context.Context, filter repository.AgreementFilter, reviewerID string) (*models.AgreementReport, error) {
+	reviews, err := s.reviewRepo.ListSubmittedReviews(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("load reviews failed: %w", err)
+	}
+
+	report := buildAgreementReport(reviews)
+	if reviewerID != "" {
+		filtered := []models.ReviewerAgreement{}
+		for _, r := range report.Reviewers {
+			if r.ReviewerID == reviewerID {
+				filtered = append(filtered, r)
+			}
+		}
+		report.Reviewers = filtered
+	}
+	return report, nil
+}
@@ -1,103 +1,509 @@
 package services
 
 import (
+    "context"
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+
     "github.com/easyhire/backend/internal/models"
+    "github.com/easyhire/backend/internal/repository"
+    "gorm.io/gorm"
 )
 
 type ScoringService interface {
-    CalculateFinalScore(answers []models.CandidateAnswer, questions []models.Question) (*models.ScoreResult, error)
+    CalculateFinalScore(answers []models.CandidateAnswer, questions []models.Question, cfg models.ScoringConfig, history models.ScoringHistory) (*models.ScoreResult, error)
+
+    // Config versions
+    ActiveConfig(ctx context.Context) (*models.ScoringConfigVersion, error)
+    EffectiveConfig(ctx context.Context, assessment *models.Assessment) (models.ScoringConfig, int, error)
+    ListConfigs(ctx context.Context) ([]models.ScoringConfigVersion, error)
+    GetConfig(ctx context.Context, version int) (*models.ScoringConfigVersion, error)
+    CreateConfig(ctx context.Context, req models.ScoringConfigRequest, createdBy string) (*models.ScoringConfigVersion, error)
+    ActivateConfig(ctx context.Context, version int) (*models.ScoringConfigVersion, error)
 }
 
-type scoringService struct{}
+type scoringService struct {
+    scoringRepo repository.ScoringRepository
+}
 
-func NewScoringService() ScoringService {
-    return &scoringService{}
+func NewScoringService(scoringRepo repository.ScoringRepository) ScoringService {
+    return &scoringService{scoringRepo: scoringRepo}
 }
 
-func (s *scoringService) CalculateFinalScore(answers []models.CandidateAnswer, questions []models.Question) (*models.ScoreResult, error) {
-    // Базовая реализация Fibonacci scoring system
+// ==========================
+// SCORING
+// ==========================
+
+func (s *scoringService) CalculateFinalScore(answers []models.CandidateAnswer, questions []models.Question, cfg models.ScoringConfig, history models.ScoringHistory) (*models.ScoreResult, error) {
+    // Fibonacci scoring system, параметры — из версии конфигурации
     var totalScore float64
     var maxPossibleScore float64
-    
+
+    // Разбивка по компетенциям: те же взвешенные баллы, что и в общем счёте
+    byCompetency := map[string]*models.CompetencyScore{}
+    // Сколько задач решено на каждом уровне сложности
+    solvedByLevel := map[string]int{}
+
+    byID := make(map[string]models.Question, len(questions))
+    for _, q := range questions {
+        byID[q.ID] = q
+    }
+
+    // Первый проход: баллы за каждый ответ и суммы по уровням
+    type scoredAnswer struct {
+        question models.Question
+        earned   float64
+    }
+    var scored []scoredAnswer
+    levelEarned := map[string]float64{}
+    levelPossible := map[string]float64{}
+
     for _, answer := range answers {
-        for _, question := range questions {
-            if answer.QuestionID == question.ID {
-                // Базовый вес уровня (Fibonacci)
-                levelWeights := map[string]float64{
-                    "junior": 1,
-                    "middle": 2,
-                    "senior": 3,
-                    "expert": 5,
-                }
-                
-                // Вес компетенции
-                competencyWeights := map[string]float64{
-                    "go_fundamentals":     1.0,
-                    "concurrency":         1.3,
-                    "system_design":       1.3,
-                    "architecture":        1.3,
-                    "data_structures_go":  1.1,
-                    "memory_management":   1.1,
-                    "http_go":             1.0,
-                    "microservices":       1.2,
-                    "reliability":         1.2,
-                    "message_brokers":     1.2,
-                    "software_design":     1.2,
-                    "quality_assurance":   1.1,
-                    "optimization":        1.1,
-                    "web_security":        1.2,
-                    "data_security":       1.3,
-                }
-                
-                levelWeight := levelWeights[string(question.Difficulty)]
-                competencyWeight := competencyWeights[question.Competency]
-                
-                maxPossibleScore += levelWeight * competencyWeight
-                
-                if answer.IsCorrect {
-                    // Бонус за время
-                    timeBonus := 1.0
-                    if answer.TimeSpent > 0 {
-                        timeRatio := float64(answer.TimeSpent) / 300.0 // 5 минут на вопрос
-                        if timeRatio < 0.3 {
-                            timeBonus = 1.2
-                        } else if timeRatio < 0.7 {
-                            timeBonus = 1.1
-                        }
-                    }
-                    
-                    totalScore += levelWeight * competencyWeight * timeBonus
-                }
-                break
+        question, ok := byID[answer.QuestionID]
+        if !ok {
+            continue
+        }
+
+        level := string(question.Difficulty)
+        levelWeight := float64(cfg.LevelWeights[level])
+        competencyWeight := competencyWeightFor(cfg, question.Competency)
+
+        maxPossibleScore += levelWeight * competencyWeight
+        levelPossible[level] += levelWeight * competencyWeight
+
+        comp, ok := byCompetency[question.Competency]
+        if !ok {
+            comp = &models.CompetencyScore{
+                CompetencyID: question.Competency,
+                Weight:       competencyWeight,
+            }
+            byCompetency[question.Competency] = comp
+        }
+        comp.Possible += levelWeight * competencyWeight
+        comp.Questions++
+
+        earned := 0.0
+        if answer.IsCorrect {
+            timeBonus := calculateTimeBonus(answer.TimeSpent, question.TimeLimit, cfg.TimeBonuses)
+            earned = levelWeight * competencyWeight * timeBonus
+
+            // ответ, сданный слишком поздно, не считается решённой задачей
+            if timeBonus > 0 {
+                comp.Correct++
+                solvedByLevel[level]++
             }
         }
+        levelEarned[level] += earned
+        scored = append(scored, scoredAnswer{question: question, earned: earned})
     }
-    
+
+    // Anti-farming: урезаем вклад уровней, второй проход раскладывает урезанные баллы по компетенциям
+    factors, levelScores, flags := applyAntiFarming(levelEarned, levelPossible, history, cfg.AntiFarming)
+
+    for _, a := range scored {
+        earned := a.earned * factors[string(a.question.Difficulty)]
+        totalScore += earned
+        byCompetency[a.question.Competency].Achieved += earned
+    }
+
     percentage := 0.0
     if maxPossibleScore > 0 {
         percentage = (totalScore / maxPossibleScore) * 100
     }
-    
+
+    // Оценка способности θ по откалиброванным вопросам (2PL IRT)
+    ability := abilityFromAnswers(answers, byID, cfg.AbilityLevels.MinCalibratedItems)
+
     // Определение уровня
-    level := determineLevel(percentage)
-    
+    explanation := determineLevel(percentage, totalScore, maxPossibleScore, solvedByLevel, ability, cfg)
+
     return &models.ScoreResult{
-        TotalScore: totalScore,
-        Percentage: percentage,
-        Level:      level,
+        TotalScore:          totalScore,
+        Percentage:          percentage,
+        Level:               explanation.Level,
+        CompetencyBreakdown: competencyBreakdown(byCompetency, cfg),
+        LevelExplanation:    explanation,
+        LevelScores:         levelScores,
+        AntiFarmingFlags:    flags,
     }, nil
 }
 
-func determineLevel(percentage float64) string {
-    if percentage >= 85 {
+func competencyWeightFor(cfg models.ScoringConfig, competency string) float64 {
+    if w, ok := cfg.CompetencyWeights[competency]; ok {
+        return w
+    }
+    if cfg.DefaultCompetencyWeight > 0 {
+        return cfg.DefaultCompetencyWeight
+    }
+    return 1.0
+}
+
+// calculateTimeBonus множитель за время относительно лимита вопроса (timeLimit, иначе BaselineSeconds):
+// бонус за быстрый ответ, штраф за опоздание, ноль — если ответ сдан слишком поздно
+func calculateTimeBonus(timeSpent, timeLimit int, bonuses models.TimeBonusConfig) float64 {
+    if timeLimit <= 0 {
+        timeLimit = bonuses.BaselineSeconds
+    }
+    if timeSpent <= 0 || timeLimit <= 0 {
+        return 1.0
+    }
+
+    if timeSpent > timeLimit+bonuses.LateGraceSeconds {
+        if bonuses.ZeroCreditRatio > 0 && float64(timeSpent) > float64(timeLimit)*bonuses.ZeroCreditRatio {
+            return 0
+        }
+        return bonuses.LateMultiplier
+    }
+
+    timeRatio := float64(timeSpent) / float64(timeLimit)
+    if timeRatio < bonuses.VeryFastRatio {
+        return bonuses.VeryFastBonus
+    } else if timeRatio < bonuses.FastRatio {
+        return bonuses.FastBonus
+    }
+    return 1.0
+}
+
+// competencyBreakdown проценты и уровень по каждой компетенции, отсортировано по id
+func competencyBreakdown(byCompetency map[string]*models.CompetencyScore, cfg models.ScoringConfig) []models.CompetencyScore {
+    breakdown := make([]models.CompetencyScore, 0, len(byCompetency))
+    for _, comp := range byCompetency {
+        if comp.Possible > 0 {
+            comp.Percentage = (comp.Achieved / comp.Possible) * 100
+        }
+        comp.Level = levelByPercentage(comp.Percentage, cfg)
+        breakdown = append(breakdown, *comp)
+    }
+    sort.Slice(breakdown, func(i, j int) bool {
+        return breakdown[i].CompetencyID < breakdown[j].CompetencyID
+    })
+    return breakdown
+}
+
+// scoringLevels уровни от младшего к старшему
+var scoringLevels = []string{"junior", "middle", "senior", "expert"}
+
+// determineLevel подбирает старший уровень, для которого выполнены все требования:
+//   - процент не ниже LevelPercentages[level] (при AbilityLevels — θ не ниже AbilityLevels.Thresholds[level]);
+//   - решена хотя бы одна задача этого уровня или выше (EXPERT — только с решённой expert-задачей);
+//   - набрано не меньше LevelThresholds[level] баллов, если столько вообще можно набрать в этой оценке.
+// Ниже junior — TRAINEE.
+func determineLevel(percentage, totalScore, maxPossible float64, solvedByLevel map[string]int, ability *models.AbilityEstimate, cfg models.ScoringConfig) *models.LevelExplanation {
+    explanation := &models.LevelExplanation{
+        Level:         "TRAINEE",
+        Percentage:    percentage,
+        TotalScore:    totalScore,
+        MaxPossible:   maxPossible,
+        SolvedByLevel: map[string]int{},
+        Method:        "percentage",
+        Ability:       ability,
+    }
+    // θ вместо процента — если включено и откалиброванных вопросов достаточно
+    useAbility := cfg.AbilityLevels.Enabled && ability != nil
+    if useAbility {
+        explanation.Method = "ability"
+    }
+    for _, level := range scoringLevels {
+        explanation.SolvedByLevel[level] = solvedByLevel[level]
+        if solvedByLevel[level] > 0 {
+            explanation.MaxSolvedLevel = level
+        }
+    }
+
+    for i := len(scoringLevels) - 1; i >= 0; i-- {
+        level := scoringLevels[i]
+
+        solved := 0
+        for _, l := range scoringLevels[i:] {
+            solved += solvedByLevel[l]
+        }
+
+        check := models.LevelCheck{
+            Level:              strings.ToUpper(level),
+            RequiredPercentage: cfg.LevelPercentages[level],
+            PercentageMet:      percentage >= cfg.LevelPercentages[level],
+            RequiredPoints:     cfg.LevelThresholds[level],
+            PointsApplicable:   float64(cfg.LevelThresholds[level]) <= maxPossible,
+            SolvedAtOrAbove:    solved,
+            SolvedMet:          solved > 0,
+        }
+        check.PointsMet = !check.PointsApplicable || totalScore >= float64(check.RequiredPoints)
+        scoreMet := check.PercentageMet
+        if useAbility {
+            required := cfg.AbilityLevels.Thresholds[level]
+            check.RequiredAbility = &required
+            check.AbilityMet = ability.Theta >= required
+            scoreMet = check.AbilityMet
+        }
+        check.Reached = scoreMet && check.SolvedMet && check.PointsMet
+        check.Reason = levelCheckReason(check, level, percentage, totalScore, ability)
+
+        explanation.Checks = append(explanation.Checks, check)
+        if check.Reached && explanation.Level == "TRAINEE" {
+            explanation.Level = check.Level
+        }
+    }
+
+    explanation.Summary = levelSummary(explanation)
+    return explanation
+}
+
+func levelCheckReason(check models.LevelCheck, level string, percentage, totalScore float64, ability *models.AbilityEstimate) string {
+    var missing []string
+    if check.RequiredAbility != nil {
+        if !check.AbilityMet {
+            missing = append(missing, fmt.Sprintf("ability θ=%.2f is below %.2f", ability.Theta, *check.RequiredAbility))
+        }
+    } else if !check.PercentageMet {
+        missing = append(missing, fmt.Sprintf("score %.1f%% is below %.0f%%", percentage, check.RequiredPercentage))
+    }
+    if !check.SolvedMet {
+        missing = append(missing, fmt.Sprintf("no %s-level (or harder) task solved", level))
+    }
+    if !check.PointsMet {
+        missing = append(missing, fmt.Sprintf("%.1f points is below the %d-point threshold", totalScore, check.RequiredPoints))
+    }
+    if len(missing) == 0 {
+        reason := fmt.Sprintf("%.1f%% >= %.0f%% with %d task(s) solved at %s level or above", percentage, check.RequiredPercentage, check.SolvedAtOrAbove, level)
+        if check.RequiredAbility != nil {
+            reason = fmt.Sprintf("ability θ=%.2f >= %.2f with %d task(s) solved at %s level or above", ability.Theta, *check.RequiredAbility, check.SolvedAtOrAbove, level)
+        }
+        if !check.PointsApplicable {
+            reason += fmt.Sprintf("; %d-point threshold not applicable to this assessment", check.RequiredPoints)
+        }
+        return reason
+    }
+    return strings.Join(missing, "; ")
+}
+
+// levelSummary присвоенный уровень и что мешает следующему
+func levelSummary(e *models.LevelExplanation) string {
+    var next *models.LevelCheck
+    for i := range e.Checks {
+        if e.Checks[i].Level == e.Level {
+            break
+        }
+        next = &e.Checks[i]
+    }
+
+    summary := fmt.Sprintf("Level %s", e.Level)
+    if e.Level != "TRAINEE" {
+        for _, c := range e.Checks {
+            if c.Level == e.Level {
+                summary += ": " + c.Reason
+                break
+            }
+        }
+    }
+    if next != nil {
+        summary += fmt.Sprintf(". %s not reached: %s", next.Level, next.Reason)
+    }
+    return summary
+}
+
+// levelByPercentage уровень только по проценту — для разбивки по компетенциям
+func levelByPercentage(percentage float64, cfg models.ScoringConfig) string {
+    if percentage >= cfg.LevelPercentages["expert"] {
         return "EXPERT"
-    } else if percentage >= 70 {
+    } else if percentage >= cfg.LevelPercentages["senior"] {
         return "SENIOR"
-    } else if percentage >= 55 {
+    } else if percentage >= cfg.LevelPercentages["middle"] {
         return "MIDDLE"
-    } else if percentage >= 40 {
+    } else if percentage >= cfg.LevelPercentages["junior"] {
         return "JUNIOR"
     } else {
         return "TRAINEE"
     }
 }
+
+// ==========================
+// CONFIG VERSIONS
+// ==========================
+
+// ActiveConfig активная версия формулы. Если версий ещё нет, она собирается из
+// level_weights/level_thresholds/competencies и сохраняется как первая.
+func (s *scoringService) ActiveConfig(ctx context.Context) (*models.ScoringConfigVersion, error) {
+    active, err := s.scoringRepo.GetActiveConfig(ctx)
+    if err == nil {
+        return active, nil
+    }
+    if !errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, fmt.Errorf("load scoring config failed: %w", err)
+    }
+
+    cfg := s.legacyConfig(ctx)
+    version := &models.ScoringConfigVersion{
+        Config:  cfg,
+        Comment: "Initial config from level_weights/level_thresholds",
+    }
+    if err := s.scoringRepo.CreateConfig(ctx, version); err != nil {
+        return nil, fmt.Errorf("create scoring config failed: %w", err)
+    }
+    if err := s.scoringRepo.ActivateConfig(ctx, version.Version); err != nil {
+        return nil, fmt.Errorf("activate scoring config failed: %w", err)
+    }
+    version.IsActive = true
+    return version, nil
+}
+
+// legacyConfig формула по умолчанию, перекрытая значениями из таблиц миграции 004
+func (s *scoringService) legacyConfig(ctx context.Context) models.ScoringConfig {
+    cfg := models.DefaultScoringConfig()
+
+    if weights, err := s.scoringRepo.GetLevelWeights(ctx); err == nil {
+        for _, w := range weights {
+            cfg.LevelWeights[w.Level] = w.Weight
+        }
+    }
+    if thresholds, err := s.scoringRepo.GetLevelThresholds(ctx); err == nil {
+        for _, t := range thresholds {
+            cfg.LevelThresholds[t.Level] = t.Threshold
+        }
+    }
+    if weights, err := s.scoringRepo.GetCompetencyWeights(ctx); err == nil {
+        for name, w := range weights {
+            cfg.CompetencyWeights[name] = w
+        }
+    }
+    return cfg
+}
+
+// EffectiveConfig активная формула с переопределениями оценки (Assessment.CompetencyWeights)
+func (s *scoringService) EffectiveConfig(ctx context.Context, assessment *models.Assessment) (models.ScoringConfig, int, error) {
+    active, err := s.ActiveConfig(ctx)
+    if err != nil {
+        return models.ScoringConfig{}, 0, err
+    }
+
+    return withAssessmentOverrides(active.Config, assessment), active.Version, nil
+}
+
+func withAssessmentOverrides(cfg models.ScoringConfig, assessment *models.Assessment) models.ScoringConfig {
+    if assessment == nil {
+        return cfg
+    }
+    return withWeightOverrides(cfg, assessment.CompetencyWeights)
+}
+
+// withWeightOverrides веса компетенций поверх формулы (переопределения оценки)
+func withWeightOverrides(cfg models.ScoringConfig, overrides map[string]float64) models.ScoringConfig {
+    if len(overrides) == 0 {
+        return cfg
+    }
+    weights := make(map[string]float64, len(cfg.CompetencyWeights)+len(overrides))
+    for name, w := range cfg.CompetencyWeights {
+        weights[name] = w
+    }
+    for name, w := range overrides {
+        weights[name] = w
+    }
+    cfg.CompetencyWeights = weights
+    return cfg
+}
+
+func (s *scoringService) ListConfigs(ctx context.Context) ([]models.ScoringConfigVersion, error) {
+    return s.scoringRepo.ListConfigs(ctx)
+}
+
+func (s *scoringService) GetConfig(ctx context.Context, version int) (*models.ScoringConfigVersion, error) {
+    cfg, err := s.scoringRepo.GetConfigByVersion(ctx, version)
+    if err != nil {
+        return nil, fmt.Errorf("scoring config not found: %w", err)
+    }
+    return cfg, nil
+}
+
+// CreateConfig сохраняет новую версию формулы (и при необходимости сразу активирует её)
+func (s *scoringService) CreateConfig(ctx context.Context, req models.ScoringConfigRequest, createdBy string) (*models.ScoringConfigVersion, error) {
+    if err := validateScoringConfig(req.Config); err != nil {
+        return nil, err
+    }
+
+    version := &models.ScoringConfigVersion{
+        Config:  req.Config,
+        Comment: req.Comment,
+    }
+    if createdBy != "" {
+        version.CreatedBy = &createdBy
+    }
+    if err := s.scoringRepo.CreateConfig(ctx, version); err != nil {
+        return nil, fmt.Errorf("create scoring config failed: %w", err)
+    }
+
+    if req.Activate {
+        return s.ActivateConfig(ctx, version.Version)
+    }
+    return version, nil
+}
+
+func (s *scoringService) ActivateConfig(ctx context.Context, version int) (*models.ScoringConfigVersion, error) {
+    if err := s.scoringRepo.ActivateConfig(ctx, version); err != nil {
+        return nil, fmt.Errorf("activate scoring config failed: %w", err)
+    }
+    return s.GetConfig(ctx, version)
+}
+
+func validateScoringConfig(cfg models.ScoringConfig) error {
+    for _, level := range []string{"junior", "middle", "senior", "expert"} {
+        if cfg.LevelWeights[level] <= 0 {
+            return fmt.Errorf("level_weights.%s must be > 0", level)
+        }
+        if cfg.LevelThresholds[level] <= 0 {
+            return fmt.Errorf("level_thresholds.%s must be > 0", level)
+        }
+        if p := cfg.LevelPercentages[level]; p <= 0 || p > 100 {
+            return fmt.Errorf("level_percentages.%s must be in (0, 100]", level)
+        }
+    }
+    for name, w := range cfg.CompetencyWeights {
+        if w <= 0 {
+            return fmt.Errorf("competency_weights.%s must be > 0", name)
+        }
+    }
+    if cfg.DefaultCompetencyWeight < 0 {
+        return fmt.Errorf("default_competency_weight must be >= 0")
+    }
+
+    tb := cfg.TimeBonuses
+    if tb.VeryFastRatio <= 0 || tb.FastRatio < tb.VeryFastRatio || tb.FastRatio > 1 {
+        return fmt.Errorf("time_bonuses: expected 0 < very_fast_ratio <= fast_ratio <= 1")
+    }
+    if tb.FastBonus < 1 || tb.VeryFastBonus < tb.FastBonus {
+        return fmt.Errorf("time_bonuses: expected 1 <= fast_bonus <= very_fast_bonus")
+    }
+    if tb.BaselineSeconds < 0 || tb.LateGraceSeconds < 0 {
+        return fmt.Errorf("time_bonuses: baseline_seconds and late_grace_seconds must be >= 0")
+    }
+    if tb.LateMultiplier < 0 || tb.LateMultiplier > 1 {
+        return fmt.Errorf("time_bonuses.late_multiplier must be in [0, 1]")
+    }
+    if tb.ZeroCreditRatio != 0 && tb.ZeroCreditRatio < 1 {
+        return fmt.Errorf("time_bonuses.zero_credit_ratio must be 0 (disabled) or >= 1")
+    }
+
+    af := cfg.AntiFarming
+    if af.MaxJuniorScorePerDay < 0 {
+        return fmt.Errorf("anti_farming.max_junior_score_per_day must be >= 0")
+    }
+    if af.MaxSameLevelRatio < 0 || af.MaxSameLevelRatio > 1 {
+        return fmt.Errorf("anti_farming.max_same_level_ratio must be in [0, 1]")
+    }
+
+    al := cfg.AbilityLevels
+    if al.MinCalibratedItems < 0 {
+        return fmt.Errorf("ability_levels.min_calibrated_items must be >= 0")
+    }
+    if al.Enabled {
+        for i, level := range scoringLevels {
+            if _, ok := al.Thresholds[level]; !ok {
+                return fmt.Errorf("ability_levels.thresholds.%s is required", level)
+            }
+            if i > 0 && al.Thresholds[level] <= al.Thresholds[scoringLevels[i-1]] {
+                return fmt.Errorf("ability_levels.thresholds must increase from junior to expert")
+            }
+        }
+    }
+    return nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/easyhire/backend/internal/models"
+)
+
+func TestCalculateTimeBonus(t *testing.T) {
+	bonuses := models.DefaultScoringConfig().TimeBonuses
+	noZeroCredit := bonuses
+	noZeroCredit.ZeroCreditRatio = 0
+
+	tests := []struct {
+		name      string
+		timeSpent int
+		timeLimit int
+		bonuses   models.TimeBonusConfig
+		want      float64
+	}{
+		{"not measured", 0, 100, bonuses, 1.0},
+		{"very fast", 20, 100, bonuses, 1.2},
+		{"fast", 50, 100, bonuses, 1.1},
+		{"in time", 90, 100, bonuses, 1.0},
+		{"exactly at limit", 100, 100, bonuses, 1.0},
+		{"within grace", 105, 100, bonuses, 1.0},
+		{"late", 106, 100, bonuses, 0.5},
+		{"late at zero credit bound", 150, 100, bonuses, 0.5},
+		{"too late", 151, 100, bonuses, 0},
+		{"baseline when no limit", 60, 0, bonuses, 1.2},
+		{"late against baseline", 320, 0, bonuses, 0.5},
+		{"zero credit disabled", 1000, 100, noZeroCredit, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateTimeBonus(tt.timeSpent, tt.timeLimit, tt.bonuses); got != tt.want {
+				t.Errorf("calculateTimeBonus(%d, %d) = %v, want %v", tt.timeSpent, tt.timeLimit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateFinalScoreLateAnswers(t *testing.T) {
+	cfg := models.DefaultScoringConfig()
+	cfg.AntiFarming = models.AntiFarmingConfig{}
+	cfg.AbilityLevels.Enabled = false
+
+	var questions []models.Question
+	for _, id := range []string{"q1", "q2", "q3"} {
+		q := models.Question{Difficulty: models.DifficultyMiddle, Competency: "go_fundamentals", TimeLimit: 100}
+		q.ID = id
+		questions = append(questions, q)
+	}
+	answers := []models.CandidateAnswer{
+		{QuestionID: "q1", IsCorrect: true, TimeSpent: 90},
+		{QuestionID: "q2", IsCorrect: true, TimeSpent: 120}, // опоздание: половина баллов
+		{QuestionID: "q3", IsCorrect: true, TimeSpent: 200}, // слишком поздно: ноль
+	}
+
+	result, err := (&scoringService{}).CalculateFinalScore(answers, questions, cfg, models.ScoringHistory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approx(result.TotalScore, 3) {
+		t.Errorf("total score = %v, want 3", result.TotalScore)
+	}
+	if !approx(result.Percentage, 50) {
+		t.Errorf("percentage = %v, want 50", result.Percentage)
+	}
+	if len(result.CompetencyBreakdown) != 1 || result.CompetencyBreakdown[0].Correct != 2 {
+		t.Errorf("breakdown = %+v, want go_fundamentals with 2 solved (answer past zero credit is not solved)",
+			result.CompetencyBreakdown)
+	}
+}
+
+// Версии конфигурации, сохранённые до появления штрафа за опоздание, получают значения по умолчанию
+func TestScoringConfigLateDefaults(t *testing.T) {
+	stored := `{"time_bonuses":{"very_fast_ratio":0.3,"fast_ratio":0.7,"very_fast_bonus":1.2,"fast_bonus":1.1,"baseline_seconds":300}}`
+	var cfg models.ScoringConfig
+	if err := json.Unmarshal([]byte(stored), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	defaults := models.DefaultScoringConfig().TimeBonuses
+	tb := cfg.TimeBonuses
+	if tb.LateGraceSeconds != defaults.LateGraceSeconds || tb.LateMultiplier != defaults.LateMultiplier || tb.ZeroCreditRatio != defaults.ZeroCreditRatio {
+		t.Errorf("late settings = %+v, want defaults %+v", tb, defaults)
+	}
+
+	// явно заданные значения не перетираются
+	explicit := `{"time_bonuses":{"late_grace_seconds":0,"late_multiplier":0,"zero_credit_ratio":0}}`
+	cfg = models.ScoringConfig{}
+	if err := json.Unmarshal([]byte(explicit), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TimeBonuses.LateGraceSeconds != 0 || cfg.TimeBonuses.LateMultiplier != 0 || cfg.TimeBonuses.ZeroCreditRatio != 0 {
+		t.Errorf("explicit late settings = %+v, want zeros", cfg.TimeBonuses)
+	}
+}
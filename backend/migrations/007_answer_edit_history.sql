@@ -0,0 +1,27 @@
+-- Edit history of coding answers (keystroke replay)
+-- Version: 007
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS answer_edit_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES assessment_sessions(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL,
+    encoding VARCHAR(20) NOT NULL DEFAULT 'gzip+json',
+    payload BYTEA NOT NULL,
+    delta_count INTEGER NOT NULL,
+    first_t BIGINT NOT NULL,
+    last_t BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_edit_batch_seq ON answer_edit_batches(session_id, question_id, seq);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (7, 'answer_edit_history')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
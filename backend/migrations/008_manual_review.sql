@@ -0,0 +1,37 @@
+-- Manual review workflow for answers that can't be auto-graded
+-- Version: 008
+
+-- ADD VALUE can't run inside a transaction block on older PostgreSQL
+ALTER TYPE session_status ADD VALUE IF NOT EXISTS 'pending_review';
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS answer_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    answer_id UUID NOT NULL REFERENCES candidate_answers(id) ON DELETE CASCADE,
+    session_id UUID NOT NULL REFERENCES assessment_sessions(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    CHECK (status IN ('pending', 'assigned', 'submitted')),
+    score DECIMAL(7,2),
+    max_score DECIMAL(7,2) NOT NULL,
+    comment TEXT,
+    assigned_at TIMESTAMP,
+    submitted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_answer_reviews_answer ON answer_reviews(answer_id);
+CREATE INDEX IF NOT EXISTS idx_answer_reviews_session ON answer_reviews(session_id);
+CREATE INDEX IF NOT EXISTS idx_answer_reviews_question ON answer_reviews(question_id);
+CREATE INDEX IF NOT EXISTS idx_answer_reviews_reviewer ON answer_reviews(reviewer_id);
+CREATE INDEX IF NOT EXISTS idx_answer_reviews_status ON answer_reviews(status);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (8, 'manual_review')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- Rubrics (weighted grading criteria) for questions
+-- Version: 009
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS rubric_criteria (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    max_points DECIMAL(7,2) NOT NULL CHECK (max_points > 0),
+    "order" INTEGER NOT NULL,
+    anchors JSONB DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rubric_criteria_question ON rubric_criteria(question_id);
+
+-- Per-criterion scores written by auto-graders and reviewers
+ALTER TABLE candidate_answers ADD COLUMN IF NOT EXISTS criterion_scores JSONB;
+ALTER TABLE answer_reviews ADD COLUMN IF NOT EXISTS criterion_scores JSONB;
+
+INSERT INTO schema_migrations (version, name)
+VALUES (9, 'question_rubrics')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
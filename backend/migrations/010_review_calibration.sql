@@ -0,0 +1,19 @@
+-- Multi-reviewer calibration and adjudication
+-- Version: 010
+
+BEGIN;
+
+ALTER TABLE answer_reviews
+    ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'review';
+
+CREATE INDEX IF NOT EXISTS idx_answer_reviews_kind ON answer_reviews(kind);
+
+ALTER TABLE assessments
+    ADD COLUMN IF NOT EXISTS reviewers_per_answer INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS disagreement_threshold DECIMAL(4,2) NOT NULL DEFAULT 0.25;
+
+INSERT INTO schema_migrations (version, name)
+VALUES (10, 'review_calibration')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- Per-competency breakdown stored on results
+-- Version: 011
+
+BEGIN;
+
+ALTER TABLE results
+    ADD COLUMN IF NOT EXISTS competency_breakdown JSONB DEFAULT '[]';
+
+INSERT INTO schema_migrations (version, name)
+VALUES (11, 'result_competency_breakdown')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,46 @@
+-- Versioned scoring configs and per-assessment overrides
+-- Version: 012
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS scoring_configs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    version INTEGER NOT NULL UNIQUE,
+    config JSONB NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT FALSE,
+    comment TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_scoring_configs_active ON scoring_configs(is_active);
+
+-- Version 1 is built from the tables created in migration 004
+INSERT INTO scoring_configs (version, config, is_active, comment)
+SELECT 1,
+       jsonb_build_object(
+           'level_weights', COALESCE((SELECT jsonb_object_agg(level, weight) FROM level_weights), '{}'::jsonb),
+           'level_thresholds', COALESCE((SELECT jsonb_object_agg(level, threshold) FROM level_thresholds), '{}'::jsonb),
+           'competency_weights', COALESCE((SELECT jsonb_object_agg(name, base_weight) FROM competencies WHERE base_weight IS NOT NULL), '{}'::jsonb),
+           'default_competency_weight', 1.0,
+           'level_percentages', '{"junior": 40, "middle": 55, "senior": 70, "expert": 85}'::jsonb,
+           'time_bonuses', '{"very_fast_ratio": 0.3, "fast_ratio": 0.7, "very_fast_bonus": 1.2, "fast_bonus": 1.1, "baseline_seconds": 300}'::jsonb,
+           'anti_farming', '{"max_junior_score_per_day": 20, "max_same_level_ratio": 0.5}'::jsonb
+       ),
+       TRUE,
+       'Initial config from level_weights/level_thresholds'
+WHERE NOT EXISTS (SELECT 1 FROM scoring_configs);
+
+ALTER TABLE assessments
+    ADD COLUMN IF NOT EXISTS competency_weights JSONB DEFAULT '{}';
+
+ALTER TABLE results
+    ADD COLUMN IF NOT EXISTS scoring_config_version INTEGER NOT NULL DEFAULT 0;
+
+INSERT INTO schema_migrations (version, name)
+VALUES (12, 'scoring_configs')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
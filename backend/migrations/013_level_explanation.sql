@@ -0,0 +1,13 @@
+-- Explanation of the level assigned to a result
+-- Version: 013
+
+BEGIN;
+
+ALTER TABLE results
+    ADD COLUMN IF NOT EXISTS level_explanation JSONB;
+
+INSERT INTO schema_migrations (version, name)
+VALUES (13, 'level_explanation')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- Anti-farming caps: per-level scores and flags on results
+-- Version: 014
+
+BEGIN;
+
+ALTER TABLE results
+    ADD COLUMN IF NOT EXISTS level_scores JSONB DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS anti_farming_flags JSONB DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS farming_capped BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_results_farming_capped ON results(farming_capped) WHERE farming_capped;
+CREATE INDEX IF NOT EXISTS idx_results_completed_at ON results(completed_at);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (14, 'anti_farming')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
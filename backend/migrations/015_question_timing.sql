@@ -0,0 +1,17 @@
+-- Server-measured answer time and late-answer penalties
+-- Version: 015
+
+BEGIN;
+
+ALTER TABLE candidate_answers
+    ADD COLUMN IF NOT EXISTS client_time_spent INTEGER DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS is_late BOOLEAN DEFAULT FALSE;
+
+-- Config versions are immutable: new time bonus settings are not written into them,
+-- missing keys get their defaults when a config is loaded (ScoringConfig.UnmarshalJSON)
+
+INSERT INTO schema_migrations (version, name)
+VALUES (15, 'question_timing')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
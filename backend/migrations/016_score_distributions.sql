@@ -0,0 +1,74 @@
+-- Score normalization: percentile, z-score and rank per assessment and competency
+-- Version: 016
+
+BEGIN;
+
+-- One row per result and scope; competency = '' is the whole assessment
+CREATE TABLE IF NOT EXISTS result_scores (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    result_id UUID NOT NULL REFERENCES results(id) ON DELETE CASCADE,
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    competency VARCHAR(100) NOT NULL DEFAULT '',
+    percentage DECIMAL(7,4) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_result_scores_result ON result_scores(result_id);
+CREATE INDEX IF NOT EXISTS idx_result_scores_scope ON result_scores(assessment_id, competency, percentage);
+
+-- Running statistics (Welford), updated on every new result
+CREATE TABLE IF NOT EXISTS score_distributions (
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    competency VARCHAR(100) NOT NULL DEFAULT '',
+    count BIGINT NOT NULL DEFAULT 0,
+    mean DOUBLE PRECISION NOT NULL DEFAULT 0,
+    m2 DOUBLE PRECISION NOT NULL DEFAULT 0,
+    min DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (assessment_id, competency)
+);
+
+-- Backfill from existing results
+INSERT INTO result_scores (result_id, assessment_id, competency, percentage)
+SELECT r.id, s.assessment_id, '', r.percentage
+FROM results r
+JOIN assessment_sessions s ON s.id = r.session_id
+WHERE NOT EXISTS (SELECT 1 FROM result_scores rs WHERE rs.result_id = r.id);
+
+INSERT INTO result_scores (result_id, assessment_id, competency, percentage)
+SELECT r.id, s.assessment_id, cs->>'competency_id', (cs->>'percentage')::DECIMAL
+FROM results r
+JOIN assessment_sessions s ON s.id = r.session_id
+CROSS JOIN LATERAL jsonb_array_elements(COALESCE(r.competency_breakdown, '[]'::jsonb)) AS cs
+WHERE COALESCE((cs->>'possible')::DECIMAL, 0) > 0
+  AND NOT EXISTS (
+      SELECT 1 FROM result_scores rs
+      WHERE rs.result_id = r.id AND rs.competency = cs->>'competency_id'
+  );
+
+INSERT INTO score_distributions (assessment_id, competency, count, mean, m2, min, max)
+SELECT assessment_id, competency,
+       COUNT(*),
+       AVG(percentage),
+       COALESCE(VAR_POP(percentage), 0) * COUNT(*),
+       MIN(percentage),
+       MAX(percentage)
+FROM result_scores
+WHERE deleted_at IS NULL
+GROUP BY assessment_id, competency
+ON CONFLICT (assessment_id, competency) DO UPDATE
+SET count = EXCLUDED.count,
+    mean = EXCLUDED.mean,
+    m2 = EXCLUDED.m2,
+    min = EXCLUDED.min,
+    max = EXCLUDED.max,
+    updated_at = CURRENT_TIMESTAMP;
+
+INSERT INTO schema_migrations (version, name)
+VALUES (16, 'score_distributions')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,42 @@
+-- 2PL IRT calibration of the question bank
+-- Version: 017
+
+BEGIN;
+
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS irt_discrimination DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS irt_difficulty DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS irt_responses INTEGER DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS irt_calibrated_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS irt_calibrations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    status VARCHAR(20) NOT NULL,
+    CHECK (status IN ('running', 'completed', 'failed')),
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP,
+    min_responses INTEGER NOT NULL,
+    sessions INTEGER NOT NULL DEFAULT 0,
+    responses INTEGER NOT NULL DEFAULT 0,
+    calibrated INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    iterations INTEGER NOT NULL DEFAULT 0,
+    converged BOOLEAN NOT NULL DEFAULT FALSE,
+    log_likelihood DOUBLE PRECISION NOT NULL DEFAULT 0,
+    error TEXT,
+    triggered_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_irt_calibrations_started ON irt_calibrations(started_at DESC);
+
+-- Ability-based levels are off by default; stored config versions are not rewritten,
+-- ability_levels gets its defaults when a config is loaded (ScoringConfig.UnmarshalJSON)
+
+INSERT INTO schema_migrations (version, name)
+VALUES (17, 'irt_calibration')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
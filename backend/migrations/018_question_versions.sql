@@ -0,0 +1,24 @@
+-- Immutable question versions
+-- Version: 018
+
+BEGIN;
+
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS root_id UUID,
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS previous_version_id UUID REFERENCES questions(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS is_latest BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Existing questions become version 1 of themselves
+UPDATE questions SET root_id = id WHERE root_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_questions_root ON questions(root_id);
+CREATE INDEX IF NOT EXISTS idx_questions_is_latest ON questions(is_latest);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_questions_root_version ON questions(root_id, version);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_questions_root_latest ON questions(root_id) WHERE is_latest;
+
+INSERT INTO schema_migrations (version, name)
+VALUES (18, 'question_versions')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,48 @@
+-- Question validation workflow: draft -> pending -> approved / rejected / needs_review
+-- Version: 019
+
+BEGIN;
+
+-- 002 schema restricted validation_status to the old set of values
+ALTER TABLE questions DROP CONSTRAINT IF EXISTS questions_validation_status_check;
+
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS validation_status VARCHAR(50) NOT NULL DEFAULT 'draft',
+    ADD COLUMN IF NOT EXISTS validated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS validated_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS reference_solution TEXT,
+    ADD COLUMN IF NOT EXISTS solution_language VARCHAR(20);
+
+ALTER TABLE questions ALTER COLUMN validation_status SET DEFAULT 'draft';
+ALTER TABLE questions ADD CONSTRAINT questions_validation_status_check
+    CHECK (validation_status IN ('draft', 'pending', 'approved', 'rejected', 'needs_review'));
+
+-- Questions already served to candidates stay eligible
+UPDATE questions
+SET validation_status = 'approved'
+WHERE is_active = TRUE AND validation_status IN ('draft', 'pending');
+
+CREATE INDEX IF NOT EXISTS idx_questions_validation_status ON questions(validation_status);
+
+CREATE TABLE IF NOT EXISTS question_validations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    CHECK (action IN ('submit', 'approve', 'reject', 'needs_review')),
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    comment TEXT,
+    solution_check JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_validations_question ON question_validations(question_id);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (19, 'question_validation')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- Question bank import/export: stable external ids and starter files
+-- Version: 020
+
+BEGIN;
+
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS external_id VARCHAR(200),
+    ADD COLUMN IF NOT EXISTS starter_files JSONB;
+
+-- Existing lineages are keyed by their root question
+UPDATE questions SET external_id = root_id::text WHERE external_id IS NULL AND root_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_questions_external_id ON questions(external_id);
+-- One current version per external id (import upserts by it)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_questions_external_latest ON questions(external_id)
+    WHERE is_latest AND deleted_at IS NULL;
+
+INSERT INTO schema_migrations (version, name)
+VALUES (20, 'question_exchange')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- AI-generated questions
+-- Version: 021
+
+BEGIN;
+
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS ai_generated BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS ai_model VARCHAR(200);
+
+CREATE INDEX IF NOT EXISTS idx_questions_ai_generated ON questions(ai_generated);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (21, 'ai_generated_questions')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
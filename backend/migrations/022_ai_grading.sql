@@ -0,0 +1,46 @@
+-- AI-assisted grading suggestions
+-- Version: 022
+
+BEGIN;
+
+ALTER TABLE assessments
+    ADD COLUMN IF NOT EXISTS ai_grading BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS ai_grade_suggestions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    answer_id UUID NOT NULL REFERENCES candidate_answers(id) ON DELETE CASCADE,
+    session_id UUID NOT NULL REFERENCES assessment_sessions(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    CHECK (status IN ('suggested', 'failed')),
+    provider VARCHAR(50) NOT NULL,
+    model VARCHAR(200) NOT NULL,
+    prompt_version VARCHAR(50) NOT NULL,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    score DECIMAL(7,2),
+    max_score DECIMAL(7,2) NOT NULL,
+    criterion_scores JSONB,
+    justification TEXT,
+    error TEXT,
+    raw_response TEXT,
+    prompt_tokens INTEGER DEFAULT 0,
+    completion_tokens INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_grade_suggestions_answer ON ai_grade_suggestions(answer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ai_grade_suggestions_session ON ai_grade_suggestions(session_id);
+CREATE INDEX IF NOT EXISTS idx_ai_grade_suggestions_question ON ai_grade_suggestions(question_id);
+
+-- Which suggestion the expert saw and whether they accepted or overrode it
+ALTER TABLE answer_reviews
+    ADD COLUMN IF NOT EXISTS ai_suggestion_id UUID REFERENCES ai_grade_suggestions(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS ai_decision VARCHAR(20);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (22, 'ai_grading')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Result feedback mapped to the competency matrix
+-- Version: 023
+
+BEGIN;
+
+ALTER TABLE results
+    ADD COLUMN IF NOT EXISTS feedback JSONB DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS feedback_generated_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS feedback_ai_model VARCHAR(200);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (23, 'result_feedback')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
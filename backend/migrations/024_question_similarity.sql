@@ -0,0 +1,30 @@
+-- Near-duplicate detection for the question bank (MinHash + LSH)
+-- Version: 024
+
+BEGIN;
+
+-- MinHash signature of the latest version of each question
+CREATE TABLE IF NOT EXISTS question_signatures (
+    root_id UUID PRIMARY KEY,
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    signature JSONB NOT NULL,
+    shingles INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_signatures_question ON question_signatures(question_id);
+
+-- LSH band buckets: questions sharing a bucket are similarity candidates
+CREATE TABLE IF NOT EXISTS question_lsh_buckets (
+    root_id UUID NOT NULL REFERENCES question_signatures(root_id) ON DELETE CASCADE,
+    bucket BIGINT NOT NULL,
+    PRIMARY KEY (root_id, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_lsh_buckets_bucket ON question_lsh_buckets(bucket);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (24, 'question_similarity')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
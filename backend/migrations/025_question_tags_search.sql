@@ -0,0 +1,31 @@
+-- Question tags and full-text search
+-- Version: 025
+
+BEGIN;
+
+-- Tags: catalogue question_tags + question_tag_associations (migration 005);
+-- a question version is linked to tags by its id, lookups by tag go through tag_id
+CREATE INDEX IF NOT EXISTS idx_question_tag_associations_tag ON question_tag_associations(tag_id);
+
+-- tag names are stored normalized (trimmed, lower case); clashing names are left as is
+UPDATE question_tags t SET name = LOWER(TRIM(t.name))
+WHERE t.name <> LOWER(TRIM(t.name))
+  AND NOT EXISTS (SELECT 1 FROM question_tags o WHERE o.id <> t.id AND LOWER(TRIM(o.name)) = LOWER(TRIM(t.name)));
+
+-- Full-text search over title (A), description (B) and explanation (C);
+-- 'simple' config: no stemming, works the same for Russian and English text
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(description, '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE(explanation, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_questions_search_vector ON questions USING GIN (search_vector);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (25, 'question_tags_search')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,90 @@
+-- Assessment templates seeded from TEST_STRUCTURE (docs/assessment-framework.md)
+-- Version: 026
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS assessment_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    key VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    is_builtin BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    type VARCHAR(50) NOT NULL,
+    target_level VARCHAR(50) NOT NULL,
+    time_limit INTEGER NOT NULL,
+    total_questions INTEGER NOT NULL,
+    passing_score DECIMAL(5,2) NOT NULL DEFAULT 70,
+    shuffle_questions BOOLEAN NOT NULL DEFAULT TRUE,
+    show_explanation BOOLEAN NOT NULL DEFAULT TRUE,
+    reviewers_per_answer INTEGER NOT NULL DEFAULT 1,
+    disagreement_threshold DECIMAL(4,3) NOT NULL DEFAULT 0.25,
+    ai_grading BOOLEAN NOT NULL DEFAULT FALSE,
+    competencies JSONB NOT NULL DEFAULT '[]',
+    competency_weights JSONB DEFAULT '{}',
+    tags JSONB DEFAULT '[]',
+    question_types JSONB DEFAULT '{}',
+    level_distribution JSONB DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- deleted templates free their key
+CREATE UNIQUE INDEX IF NOT EXISTS idx_assessment_templates_key ON assessment_templates(key) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_assessment_templates_deleted_at ON assessment_templates(deleted_at);
+
+-- Built-in templates. Question counts follow TEST_STRUCTURE proportions
+-- (50% multiple choice, 30% coding, 15% architecture, 5% debugging); time limits use its
+-- per-type allocation (1.5 / 5 / 7 / 4 minutes) rounded up to 5 minutes.
+INSERT INTO assessment_templates (key, name, description, is_builtin, type, target_level, time_limit, total_questions,
+                                  passing_score, reviewers_per_answer, competencies, tags, question_types, level_distribution)
+VALUES
+    ('backend-go-standard', 'Backend Go (TEST_STRUCTURE), 70 min',
+     'The reference 20-question structure: backend core cluster, all levels.', TRUE, 'technical', 'middle', 4200, 20, 70, 1,
+     '[{"competency_id": "go_fundamentals", "level": "middle", "weight": 1.0, "min_questions": 3, "max_questions": 6},
+       {"competency_id": "data_structures_go", "level": "middle", "weight": 1.0, "min_questions": 2, "max_questions": 5},
+       {"competency_id": "concurrency", "level": "middle", "weight": 1.2, "min_questions": 3, "max_questions": 6},
+       {"competency_id": "memory_management", "level": "middle", "weight": 1.0, "min_questions": 2, "max_questions": 4}]',
+     '["go", "backend"]',
+     '{"multiple_choice": 10, "coding": 6, "architecture": 3, "debugging": 1}',
+     '{"junior": 6, "middle": 8, "senior": 4, "expert": 2}'),
+
+    ('junior-go-screening-30', 'Junior Go screening, 30 min',
+     'Quick screening of Go basics: mostly theory, two short coding tasks.', TRUE, 'technical', 'junior', 1800, 13, 60, 1,
+     '[{"competency_id": "go_fundamentals", "level": "junior", "weight": 1.0, "min_questions": 4, "max_questions": 6},
+       {"competency_id": "data_structures_go", "level": "junior", "weight": 1.0, "min_questions": 2, "max_questions": 4},
+       {"competency_id": "concurrency", "level": "junior", "weight": 1.0, "min_questions": 2, "max_questions": 3}]',
+     '["go", "screening"]',
+     '{"multiple_choice": 10, "coding": 2, "debugging": 1}',
+     '{"junior": 9, "middle": 4}'),
+
+    ('middle-backend-go-60', 'Middle Backend Go, 60 min',
+     'Core Go, concurrency, HTTP services and testing for middle backend engineers.', TRUE, 'technical', 'middle', 3600, 17, 70, 1,
+     '[{"competency_id": "go_fundamentals", "level": "middle", "weight": 1.0, "min_questions": 3, "max_questions": 5},
+       {"competency_id": "concurrency", "level": "middle", "weight": 1.2, "min_questions": 3, "max_questions": 5},
+       {"competency_id": "http_go", "level": "middle", "weight": 1.0, "min_questions": 2, "max_questions": 4},
+       {"competency_id": "data_structures_go", "level": "middle", "weight": 1.0, "min_questions": 2, "max_questions": 4},
+       {"competency_id": "testing", "level": "middle", "weight": 0.8, "min_questions": 1, "max_questions": 3}]',
+     '["go", "backend"]',
+     '{"multiple_choice": 9, "coding": 5, "architecture": 2, "debugging": 1}',
+     '{"junior": 4, "middle": 8, "senior": 4, "expert": 1}'),
+
+    ('senior-backend-go-90', 'Senior Backend Go, 90 min',
+     'Concurrency, memory, system design and reliability; architecture answers get two reviewers.', TRUE, 'technical', 'senior', 5400, 20, 70, 2,
+     '[{"competency_id": "concurrency", "level": "senior", "weight": 1.2, "min_questions": 3, "max_questions": 5},
+       {"competency_id": "memory_management", "level": "senior", "weight": 1.0, "min_questions": 2, "max_questions": 4},
+       {"competency_id": "system_design", "level": "senior", "weight": 1.3, "min_questions": 3, "max_questions": 5},
+       {"competency_id": "microservices", "level": "senior", "weight": 1.2, "min_questions": 2, "max_questions": 4},
+       {"competency_id": "reliability", "level": "senior", "weight": 1.0, "min_questions": 2, "max_questions": 3},
+       {"competency_id": "performance", "level": "senior", "weight": 1.0, "min_questions": 1, "max_questions": 3}]',
+     '["go", "backend", "technical"]',
+     '{"multiple_choice": 6, "coding": 7, "architecture": 5, "debugging": 2}',
+     '{"junior": 2, "middle": 6, "senior": 8, "expert": 4}')
+ON CONFLICT (key) WHERE deleted_at IS NULL DO NOTHING;
+
+INSERT INTO schema_migrations (version, name)
+VALUES (26, 'assessment_templates')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Assessment lifecycle: draft -> active (published) -> archived
+-- Version: 027
+
+BEGIN;
+
+ALTER TABLE assessments
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+-- assessments that were already active count as published when they were created
+UPDATE assessments SET published_at = created_at WHERE status = 'active' AND published_at IS NULL;
+UPDATE assessments SET archived_at = updated_at WHERE status = 'archived' AND archived_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_assessments_status ON assessments(status);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (27, 'assessment_lifecycle')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
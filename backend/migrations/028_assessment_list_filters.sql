@@ -0,0 +1,23 @@
+-- Assessment list: tag filter, keyset pagination by sort field + id
+-- Version: 028
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_assessment_tag_associations_tag ON assessment_tag_associations(tag_id);
+
+-- tag names are stored lowercase (the same normalization as question tags)
+UPDATE assessment_tags t SET name = lower(t.name)
+WHERE t.name <> lower(t.name)
+  AND NOT EXISTS (SELECT 1 FROM assessment_tags o WHERE o.name = lower(t.name));
+
+CREATE INDEX IF NOT EXISTS idx_assessments_created_at_id ON assessments(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_assessments_updated_at_id ON assessments(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_assessments_title_id ON assessments(title, id);
+CREATE INDEX IF NOT EXISTS idx_assessments_target_level ON assessments(target_level);
+CREATE INDEX IF NOT EXISTS idx_assessments_created_by ON assessments(created_by);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (28, 'assessment_list_filters')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
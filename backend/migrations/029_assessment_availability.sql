@@ -0,0 +1,37 @@
+-- Availability windows, attempt limit and cooldown for assessments and invitations
+-- Version: 029
+
+BEGIN;
+
+ALTER TABLE assessments
+    ADD COLUMN IF NOT EXISTS opens_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS closes_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS max_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS attempt_cooldown INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE invitations
+    ADD COLUMN IF NOT EXISTS opens_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS closes_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
+
+ALTER TABLE assessments DROP CONSTRAINT IF EXISTS chk_assessments_window;
+ALTER TABLE assessments ADD CONSTRAINT chk_assessments_window
+    CHECK (opens_at IS NULL OR closes_at IS NULL OR closes_at > opens_at);
+ALTER TABLE assessments DROP CONSTRAINT IF EXISTS chk_assessments_attempts;
+ALTER TABLE assessments ADD CONSTRAINT chk_assessments_attempts
+    CHECK (max_attempts >= 0 AND attempt_cooldown >= 0);
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS chk_invitations_window;
+ALTER TABLE invitations ADD CONSTRAINT chk_invitations_window
+    CHECK (opens_at IS NULL OR closes_at IS NULL OR closes_at > opens_at);
+
+-- attempt counting and the candidate's invitation lookup in StartSession
+CREATE INDEX IF NOT EXISTS idx_assessment_sessions_assessment_candidate
+    ON assessment_sessions(assessment_id, candidate_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_invitations_assessment_email ON invitations(assessment_id, lower(email));
+
+INSERT INTO schema_migrations (version, name)
+VALUES (29, 'assessment_availability')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
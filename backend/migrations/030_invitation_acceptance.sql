@@ -0,0 +1,26 @@
+-- Invitation acceptance: pending/sent -> opened -> accepted -> completed, sessions bound to invitations
+-- Version: 030
+
+BEGIN;
+
+ALTER TABLE invitations
+    ADD COLUMN IF NOT EXISTS opened_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS accepted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+
+-- the candidate account is linked only when the invitation is accepted
+ALTER TABLE invitations ALTER COLUMN candidate_id DROP NOT NULL;
+
+-- the early status check knew neither 'pending' nor 'accepted'
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS invitations_status_check;
+
+ALTER TABLE assessment_sessions
+    ADD COLUMN IF NOT EXISTS invitation_id UUID REFERENCES invitations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_assessment_sessions_invitation ON assessment_sessions(invitation_id);
+
+INSERT INTO schema_migrations (version, name)
+VALUES (30, 'invitation_acceptance')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- Test structure on assessments: questions per type and per level (copied from templates)
+-- Version: 031
+
+BEGIN;
+
+ALTER TABLE assessments
+    ADD COLUMN IF NOT EXISTS question_types JSONB,
+    ADD COLUMN IF NOT EXISTS level_distribution JSONB;
+
+INSERT INTO schema_migrations (version, name)
+VALUES (31, 'assessment_structure')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- Results keep the assessment competency weight overrides they were scored with
+-- Version: 032
+
+BEGIN;
+
+ALTER TABLE results
+    ADD COLUMN IF NOT EXISTS competency_weights JSONB;
+
+-- earlier results: the overrides as they are now are the best known approximation
+UPDATE results r
+SET competency_weights = a.competency_weights
+FROM assessment_sessions s
+JOIN assessments a ON a.id = s.assessment_id
+WHERE s.id = r.session_id
+  AND r.competency_weights IS NULL;
+
+INSERT INTO schema_migrations (version, name)
+VALUES (32, 'result_weight_snapshot')
+ON CONFLICT (version) DO NOTHING;
+
+COMMIT;